@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"bank-system/bank"
+)
+
+// errorResponse is the body of every non-2xx response.
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []fieldError `json:"fields,omitempty"`
+}
+
+// fieldError names one invalid field of the request body.
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// requestError is a problem with the request itself, found before it reaches
+// the banking system.
+type requestError struct {
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...any) error {
+	return &requestError{message: fmt.Sprintf(format, args...)}
+}
+
+// errorStatuses maps the bank's sentinel errors to a status code and a
+// machine-readable code. The first match wins.
+//
+// ErrEmptyEntry, ErrUnbalancedEntry, ErrNoKeyProvider and ErrUnknownKey are
+// left out on purpose: no request can cause them, only a bug in posting to
+// the ledger or a misconfigured key provider, so they are reported as 500
+// internal like any other unexpected error and their details only logged.
+var errorStatuses = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{bank.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{bank.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+	{bank.ErrTransactionNotFound, http.StatusNotFound, "transaction_not_found"},
+	{bank.ErrInstructionNotFound, http.StatusNotFound, "standing_instruction_not_found"},
+	{bank.ErrEmailExists, http.StatusConflict, "email_exists"},
+	{bank.ErrAccountExists, http.StatusConflict, "account_exists"},
+	{bank.ErrInsufficientFunds, http.StatusUnprocessableEntity, "insufficient_funds"},
+	{bank.ErrAccountNotActive, http.StatusUnprocessableEntity, "account_not_active"},
+	{bank.ErrNonZeroBalance, http.StatusUnprocessableEntity, "non_zero_balance"},
+	{bank.ErrOverdraftNotAllowed, http.StatusUnprocessableEntity, "overdraft_not_allowed"},
+	{bank.ErrInvalidTransition, http.StatusUnprocessableEntity, "invalid_status_transition"},
+	{bank.ErrInvalidStatus, http.StatusBadRequest, "invalid_status"},
+	{bank.ErrAlreadyReversed, http.StatusConflict, "already_reversed"},
+	{bank.ErrNotReversible, http.StatusUnprocessableEntity, "not_reversible"},
+	{bank.ErrHoldNotPending, http.StatusConflict, "hold_not_pending"},
+	{bank.ErrHoldExpired, http.StatusUnprocessableEntity, "hold_expired"},
+	{bank.ErrInstructionStatus, http.StatusConflict, "standing_instruction_status"},
+	{bank.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+	{bank.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+	{bank.ErrSameAccount, http.StatusBadRequest, "same_account"},
+	{bank.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{bank.ErrInvalidMoney, http.StatusBadRequest, "invalid_amount"},
+	{bank.ErrMoneyOverflow, http.StatusBadRequest, "invalid_amount"},
+	{bank.ErrUnknownCurrency, http.StatusBadRequest, "unknown_currency"},
+	{bank.ErrCurrencyMismatch, http.StatusBadRequest, "currency_mismatch"},
+	{bank.ErrWeakPassword, http.StatusBadRequest, "weak_password"},
+	{bank.ErrInvalidRole, http.StatusBadRequest, "invalid_role"},
+	{bank.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{bank.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+	{bank.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{bank.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, body := http.StatusInternalServerError, errorBody{Code: "internal", Message: "internal server error"}
+
+	var reqErr *requestError
+	var invalid *bank.ValidationError
+	if errors.As(err, &reqErr) {
+		status, body = http.StatusBadRequest, errorBody{Code: "invalid_request", Message: reqErr.message}
+	} else if errors.As(err, &invalid) {
+		status, body = http.StatusBadRequest, errorBody{Code: "validation_failed", Message: err.Error()}
+		for _, f := range invalid.Fields {
+			body.Fields = append(body.Fields, fieldError{Field: f.Field, Message: f.Err.Error()})
+		}
+	} else {
+		for _, e := range errorStatuses {
+			if errors.Is(err, e.err) {
+				status, body = e.status, errorBody{Code: e.code, Message: err.Error()}
+				break
+			}
+		}
+	}
+
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="bank"`)
+	}
+	if status == http.StatusInternalServerError {
+		log.Printf("api: %v", err)
+	}
+	writeJSON(w, status, errorResponse{Error: body})
+}
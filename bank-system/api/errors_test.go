@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"bank-system/bank"
+)
+
+// recordError runs writeError and decodes the body it wrote.
+func recordError(t *testing.T, err error) (*httptest.ResponseRecorder, errorBody) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	writeError(rec, err)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var resp errorResponse
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&resp); err != nil {
+		t.Fatalf("decoding error body: %v", err)
+	}
+	return rec, resp.Error
+}
+
+func TestWriteErrorMapsSentinels(t *testing.T) {
+	for _, e := range errorStatuses {
+		t.Run(e.code, func(t *testing.T) {
+			// The bank wraps its sentinels with detail.
+			err := fmt.Errorf("doing something: %w", e.err)
+			rec, body := recordError(t, err)
+
+			if rec.Code != e.status {
+				t.Errorf("status %d, want %d", rec.Code, e.status)
+			}
+			if want := (errorBody{Code: e.code, Message: err.Error()}); !reflect.DeepEqual(body, want) {
+				t.Errorf("body %+v, want %+v", body, want)
+			}
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if (e.status == http.StatusUnauthorized) != (challenge != "") {
+				t.Errorf("WWW-Authenticate = %q with status %d", challenge, rec.Code)
+			}
+		})
+	}
+}
+
+func TestWriteErrorRequestAndValidationErrors(t *testing.T) {
+	rec, body := recordError(t, badRequest("limit must be a number"))
+	if rec.Code != http.StatusBadRequest || !reflect.DeepEqual(body, errorBody{Code: "invalid_request", Message: "limit must be a number"}) {
+		t.Errorf("request error: %d %+v", rec.Code, body)
+	}
+
+	invalid := &bank.ValidationError{Fields: []bank.FieldError{
+		{Field: "email", Err: bank.ErrInvalidEmail},
+		{Field: "phone", Err: bank.ErrInvalidPhone},
+	}}
+	rec, body = recordError(t, fmt.Errorf("creating user: %w", invalid))
+	want := errorBody{Code: "validation_failed", Message: "creating user: " + invalid.Error(), Fields: []fieldError{
+		{Field: "email", Message: bank.ErrInvalidEmail.Error()},
+		{Field: "phone", Message: bank.ErrInvalidPhone.Error()},
+	}}
+	if rec.Code != http.StatusBadRequest || !reflect.DeepEqual(body, want) {
+		t.Errorf("validation error: %d %+v, want 400 %+v", rec.Code, body, want)
+	}
+}
+
+func TestWriteErrorHidesInternalErrors(t *testing.T) {
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+
+	// These are faults of the server, not of the request: a ledger that
+	// would not balance or missing encryption keys. Their details stay in
+	// the log.
+	for _, err := range []error{
+		bank.ErrEmptyEntry,
+		bank.ErrUnbalancedEntry,
+		bank.ErrNoKeyProvider,
+		bank.ErrUnknownKey,
+		errors.New("disk on fire"),
+	} {
+		rec, body := recordError(t, fmt.Errorf("posting: %w", err))
+		if rec.Code != http.StatusInternalServerError || !reflect.DeepEqual(body, errorBody{Code: "internal", Message: "internal server error"}) {
+			t.Errorf("%v: %d %+v, want 500 internal", err, rec.Code, body)
+		}
+	}
+}
@@ -0,0 +1,911 @@
+// Package api exposes a BankingSystem as a JSON HTTP API.
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"bank-system/bank"
+)
+
+const maxBodySize = 1 << 20
+
+// Server routes HTTP requests to a BankingSystem. It implements
+// http.Handler. Apart from signing up and logging in, every request needs
+// a bearer token and is limited to what the caller's role allows.
+type Server struct {
+	bank *bank.BankingSystem
+	mux  *http.ServeMux
+}
+
+func NewServer(bs *bank.BankingSystem) *Server {
+	s := &Server{
+		bank: bs,
+		mux:  http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("POST /users", s.createUser)
+	s.mux.HandleFunc("POST /sessions", s.login)
+	s.mux.HandleFunc("DELETE /sessions/current", s.logout)
+
+	s.mux.HandleFunc("GET /users", s.authenticated(s.listUsers))
+	s.mux.HandleFunc("GET /users/{id}", s.authenticated(s.getUser))
+	s.mux.HandleFunc("GET /users/{id}/accounts", s.authenticated(s.listUserAccounts))
+	s.mux.HandleFunc("PUT /users/{id}/role", s.authenticated(s.assignRole))
+
+	s.mux.HandleFunc("POST /accounts", s.authenticated(s.createAccount))
+	s.mux.HandleFunc("GET /accounts", s.authenticated(s.listAccounts))
+	s.mux.HandleFunc("GET /accounts/{number}", s.authenticated(s.getAccount))
+	s.mux.HandleFunc("POST /accounts/{number}/close", s.authenticated(s.closeAccount))
+	s.mux.HandleFunc("PUT /accounts/{number}/overdraft", s.authenticated(s.setOverdraftLimit))
+	s.mux.HandleFunc("PUT /accounts/{number}/status", s.authenticated(s.changeStatus))
+	s.mux.HandleFunc("POST /accounts/{number}/freeze", s.authenticated(s.freezeAccount))
+	s.mux.HandleFunc("POST /accounts/{number}/unfreeze", s.authenticated(s.unfreezeAccount))
+	s.mux.HandleFunc("GET /accounts/{number}/balance", s.authenticated(s.getBalance))
+	s.mux.HandleFunc("POST /accounts/{number}/deposits", s.authenticated(s.deposit))
+	s.mux.HandleFunc("POST /accounts/{number}/withdrawals", s.authenticated(s.withdraw))
+	s.mux.HandleFunc("POST /accounts/{number}/holds", s.authenticated(s.placeHold))
+	s.mux.HandleFunc("GET /accounts/{number}/transactions", s.authenticated(s.listAccountTransactions))
+	s.mux.HandleFunc("GET /accounts/{number}/summary", s.authenticated(s.getSummary))
+	s.mux.HandleFunc("GET /accounts/{number}/statements/{month}", s.authenticated(s.getStatement))
+
+	s.mux.HandleFunc("POST /transfers", s.authenticated(s.transfer))
+	s.mux.HandleFunc("GET /transactions", s.authenticated(s.listTransactions))
+	s.mux.HandleFunc("GET /transactions/{id}", s.authenticated(s.getTransaction))
+	s.mux.HandleFunc("POST /transactions/{id}/reversal", s.authenticated(s.reverseTransaction))
+	s.mux.HandleFunc("POST /transactions/{id}/capture", s.authenticated(s.captureHold))
+	s.mux.HandleFunc("POST /transactions/{id}/release", s.authenticated(s.releaseHold))
+
+	s.mux.HandleFunc("POST /standing-instructions", s.authenticated(s.createStandingInstruction))
+	s.mux.HandleFunc("GET /standing-instructions", s.authenticated(s.listStandingInstructions))
+	s.mux.HandleFunc("GET /standing-instructions/{id}", s.authenticated(s.getStandingInstruction))
+	s.mux.HandleFunc("GET /standing-instructions/{id}/attempts", s.authenticated(s.listInstructionAttempts))
+	s.mux.HandleFunc("POST /standing-instructions/{id}/pause", s.authenticated(s.changeStandingInstruction((*bank.BankingSystem).PauseStandingInstruction)))
+	s.mux.HandleFunc("POST /standing-instructions/{id}/resume", s.authenticated(s.changeStandingInstruction((*bank.BankingSystem).ResumeStandingInstruction)))
+	s.mux.HandleFunc("POST /standing-instructions/{id}/cancel", s.authenticated(s.changeStandingInstruction((*bank.BankingSystem).CancelStandingInstruction)))
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// authHandlerFunc handles a request on behalf of the customer who sent it.
+type authHandlerFunc func(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem)
+
+// authenticated requires a bearer token from POST /sessions and passes h a
+// view of the bank that acts for the token's owner.
+func (s *Server) authenticated(h authHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			writeError(w, bank.ErrUnauthenticated)
+			return
+		}
+
+		bs, err := s.bank.Authenticate(token)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		h(w, r, bs)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	session, err := s.bank.Login(req.Email, req.Password)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sessionResponse{
+		Token:     session.Token,
+		TokenType: "Bearer",
+		UserID:    session.Principal.UserID,
+		ExpiresAt: session.ExpiresAt,
+	})
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := bearerToken(r)
+	if !ok {
+		writeError(w, bank.ErrUnauthenticated)
+		return
+	}
+
+	s.bank.Logout(token)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req createUserRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := s.bank.CreateUser(req.FirstName, req.LastName, req.Email, req.Password,
+		req.Address, req.Phone, req.PanCardNumber, req.AadharCardNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newUserResponse(*user))
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	users, err := bs.ListUsers()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := make([]userResponse, len(users))
+	for i, user := range users {
+		resp[i] = newUserResponse(user)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	id, err := userID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := bs.GetUser(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUserResponse(*user))
+}
+
+func (s *Server) assignRole(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	id, err := userID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req assignRoleRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := bs.AssignRole(id, bank.Role(req.Role)); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := bs.GetUser(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUserResponse(*user))
+}
+
+func (s *Server) listUserAccounts(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	id, err := userID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	accounts, err := bs.ListUserAccounts(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountResponses(accounts))
+}
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req createAccountRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	account, err := bs.CreateAccount(req.AccountNumber, req.HolderName, req.AccountType, req.UserID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newAccountResponse(*account))
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accounts, err := bs.ListAccounts()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountResponses(accounts))
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	account, err := bs.GetAccount(r.PathValue("number"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountResponse(*account))
+}
+
+func (s *Server) closeAccount(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accountNumber := r.PathValue("number")
+	if err := bs.CloseAccount(accountNumber); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	account, err := bs.GetAccount(accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountResponse(*account))
+}
+
+func (s *Server) setOverdraftLimit(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accountNumber := r.PathValue("number")
+	var req overdraftRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	limit, err := req.money(accountCurrency(bs, accountNumber))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := bs.SetOverdraftLimit(accountNumber, limit); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	account, err := bs.GetAccount(accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountResponse(*account))
+}
+
+func (s *Server) changeStatus(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req statusRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	status, err := bank.ParseAccountStatus(req.Status)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeStatusChange(w, r, bs, func(accountNumber string) error {
+		return bs.ChangeAccountStatus(accountNumber, status, req.Reason)
+	})
+}
+
+func (s *Server) freezeAccount(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req statusRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeStatusChange(w, r, bs, func(accountNumber string) error {
+		return bs.FreezeAccount(accountNumber, req.Reason)
+	})
+}
+
+func (s *Server) unfreezeAccount(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req statusRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeStatusChange(w, r, bs, func(accountNumber string) error {
+		return bs.UnfreezeAccount(accountNumber, req.Reason)
+	})
+}
+
+// writeStatusChange applies change to the account in the path and responds
+// with the updated account.
+func (s *Server) writeStatusChange(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem, change func(accountNumber string) error) {
+	accountNumber := r.PathValue("number")
+	if err := change(accountNumber); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	account, err := bs.GetAccount(accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAccountResponse(*account))
+}
+
+func (s *Server) getBalance(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accountNumber := r.PathValue("number")
+	balance, err := bs.GetBalance(accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	available, err := bs.GetAvailableBalance(accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, balanceResponse{AccountNumber: accountNumber, Balance: balance, Available: available})
+}
+
+func (s *Server) deposit(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accountNumber := r.PathValue("number")
+	amount, err := decodeAmount(r, bs, accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	transaction, err := idempotent(r, bs).Deposit(accountNumber, amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTransactionResponse(transaction))
+}
+
+func (s *Server) withdraw(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accountNumber := r.PathValue("number")
+	amount, err := decodeAmount(r, bs, accountNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	transaction, err := idempotent(r, bs).Withdraw(accountNumber, amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTransactionResponse(transaction))
+}
+
+func (s *Server) transfer(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req transferRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	amount, err := req.money(accountCurrency(bs, req.FromAccount))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	transaction, err := idempotent(r, bs).Transfer(req.FromAccount, req.ToAccount, amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTransactionResponse(transaction))
+}
+
+func (s *Server) placeHold(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	accountNumber := r.PathValue("number")
+	var req holdRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	amount, err := req.money(accountCurrency(bs, accountNumber))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	hold, err := bs.PlaceHold(accountNumber, amount, req.Description)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTransactionResponse(hold))
+}
+
+// captureHold captures the amount in the body, or the whole hold if the
+// body names no amount.
+func (s *Server) captureHold(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	hold, err := bs.GetTransaction(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req amountRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	amount := hold.Amount
+	if req.Amount != "" {
+		if amount, err = req.money(hold.Amount.Currency()); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	withdrawal, err := bs.CaptureHold(hold.ID, amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTransactionResponse(withdrawal))
+}
+
+func (s *Server) releaseHold(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	hold, err := bs.ReleaseHold(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTransactionResponse(hold))
+}
+
+func (s *Server) reverseTransaction(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req reversalRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	reversal, err := bs.ReverseTransaction(r.PathValue("id"), req.Reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newTransactionResponse(reversal))
+}
+
+func (s *Server) listAccountTransactions(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	transactions, err := bs.ListAccountTransactions(r.PathValue("number"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTransactionResponses(transactions))
+}
+
+func (s *Server) getSummary(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	summary, err := bs.GetAccountSummary(r.PathValue("number"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	net, err := summary.NetAmount()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSummaryResponse(summary, net))
+}
+
+// getStatement returns the statement of an account for a month that has
+// ended, given as YYYY-MM. The format query parameter picks json, text, html
+// or pdf; without it the Accept header does, and JSON is the default.
+func (s *Server) getStatement(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	month, err := time.Parse("2006-01", r.PathValue("month"))
+	if err != nil {
+		writeError(w, badRequest("month must be given as YYYY-MM"))
+		return
+	}
+	format := statementFormat(r)
+	if format != "json" && bank.StatementFormat(format).ContentType() == "" {
+		writeError(w, badRequest("format must be json, text, html or pdf"))
+		return
+	}
+
+	statement, err := bs.MonthlyStatement(r.PathValue("number"), month.Year(), month.Month())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if format == "json" {
+		writeJSON(w, http.StatusOK, newStatementResponse(statement))
+		return
+	}
+	var body bytes.Buffer
+	if err := statement.Render(&body, bank.StatementFormat(format)); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", bank.StatementFormat(format).ContentType())
+	if format == "pdf" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="statement-%s-%s.pdf"`, statement.AccountNumber, month.Format("2006-01")))
+	}
+	if _, err := w.Write(body.Bytes()); err != nil {
+		log.Printf("api: writing response: %v", err)
+	}
+}
+
+// statementFormat reads the format query parameter, falling back to the
+// first media type in the Accept header that a statement can be rendered as.
+func statementFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format)
+	}
+	for _, accept := range listParam(r.Header.Values("Accept")) {
+		mediaType, _, _ := strings.Cut(accept, ";")
+		switch strings.TrimSpace(mediaType) {
+		case "application/json":
+			return "json"
+		case "text/plain":
+			return "text"
+		case "text/html":
+			return "html"
+		case "application/pdf":
+			return "pdf"
+		}
+	}
+	return "json"
+}
+
+// listTransactions returns a page of the transactions that match the query
+// string. A Link header with rel="next" gives the URL of the next page.
+func (s *Server) listTransactions(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	filter, err := transactionFilter(r, bs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	page, err := bs.QueryTransactions(filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if page.NextCursor != "" {
+		next := r.URL.Query()
+		next.Set("cursor", page.NextCursor)
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, next.Encode()))
+	}
+	writeJSON(w, http.StatusOK, newTransactionResponses(page.Transactions))
+}
+
+func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	transaction, err := bs.GetTransaction(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTransactionResponse(transaction))
+}
+
+func (s *Server) createStandingInstruction(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	var req instructionRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	amount, err := req.money(accountCurrency(bs, req.FromAccount))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	recurrence, err := req.recurrence()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	instruction, err := bs.CreateStandingInstruction(req.FromAccount, req.ToAccount, amount, req.Description, recurrence)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newInstructionResponse(*instruction))
+}
+
+func (s *Server) listStandingInstructions(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	instructions, err := bs.ListStandingInstructions()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := make([]instructionResponse, len(instructions))
+	for i, instruction := range instructions {
+		resp[i] = newInstructionResponse(instruction)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) getStandingInstruction(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	id, err := instructionID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	instruction, err := bs.GetStandingInstruction(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newInstructionResponse(*instruction))
+}
+
+func (s *Server) listInstructionAttempts(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+	id, err := instructionID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	attempts, err := bs.ListInstructionAttempts(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := make([]attemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		resp[i] = newAttemptResponse(attempt)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// changeStandingInstruction handles pausing, resuming and cancelling, which
+// take no body.
+func (s *Server) changeStandingInstruction(change func(*bank.BankingSystem, int) (*bank.StandingInstruction, error)) authHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, bs *bank.BankingSystem) {
+		id, err := instructionID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		instruction, err := change(bs, id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newInstructionResponse(*instruction))
+	}
+}
+
+func userID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, badRequest("user ID must be a number")
+	}
+	return id, nil
+}
+
+func instructionID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, badRequest("standing instruction ID must be a number")
+	}
+	return id, nil
+}
+
+// idempotencyHeader carries the client's idempotency key for a deposit,
+// withdrawal or transfer. See bank.BankingSystem.Idempotent.
+const idempotencyHeader = "Idempotency-Key"
+
+// idempotent applies the request's idempotency key, if it has one, to bs.
+func idempotent(r *http.Request, bs *bank.BankingSystem) *bank.BankingSystem {
+	key := strings.TrimSpace(r.Header.Get(idempotencyHeader))
+	if key == "" {
+		return bs
+	}
+	return bs.Idempotent(key)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("api: writing response: %v", err)
+	}
+}
+
+// decode reads a JSON request body into v, rejecting unknown fields and
+// trailing data.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid JSON body: %v", err)
+	}
+	if dec.More() {
+		return badRequest("invalid JSON body: unexpected data after the object")
+	}
+	return nil
+}
+
+// decodeAmount reads a deposit or withdrawal body. The amount is taken to be
+// in the account's currency unless the request names one.
+func decodeAmount(r *http.Request, bs *bank.BankingSystem, accountNumber string) (bank.Money, error) {
+	var req amountRequest
+	if err := decode(r, &req); err != nil {
+		return bank.Money{}, err
+	}
+	return req.money(accountCurrency(bs, accountNumber))
+}
+
+// transactionFilter reads the query string of GET /transactions. Types and
+// statuses may be repeated or comma-separated. Amounts are in the account's
+// currency unless currency is given, and times are RFC 3339.
+func transactionFilter(r *http.Request, bs *bank.BankingSystem) (bank.TransactionFilter, error) {
+	query := r.URL.Query()
+	filter := bank.TransactionFilter{
+		AccountNumber:   query.Get("account"),
+		Text:            query.Get("q"),
+		ReferenceNumber: query.Get("reference"),
+		Cursor:          query.Get("cursor"),
+	}
+
+	var err error
+	if v := query.Get("user_id"); v != "" {
+		if filter.UserID, err = strconv.Atoi(v); err != nil {
+			return filter, badRequest("user_id must be a number")
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return filter, badRequest("limit must be a number")
+		}
+	}
+	for _, v := range listParam(query["type"]) {
+		filter.Types = append(filter.Types, bank.TransactionType(strings.ToUpper(v)))
+	}
+	for _, v := range listParam(query["status"]) {
+		filter.Statuses = append(filter.Statuses, bank.TransactionStatus(strings.ToUpper(v)))
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.NewestFirst = true
+	default:
+		return filter, badRequest("order must be asc or desc")
+	}
+
+	if filter.Since, err = timeParam(query, "since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = timeParam(query, "until"); err != nil {
+		return filter, err
+	}
+
+	currency := bank.Currency(query.Get("currency"))
+	if currency == "" {
+		currency = accountCurrency(bs, filter.AccountNumber)
+	}
+	if filter.MinAmount, err = amountParam(query, "min_amount", currency); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = amountParam(query, "max_amount", currency); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// timeParam reads an optional RFC 3339 time from the query string.
+func timeParam(query url.Values, name string) (time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, badRequest("%s must be an RFC 3339 time such as 2026-01-02T15:04:05Z", name)
+	}
+	return t, nil
+}
+
+// amountParam reads an optional amount from the query string.
+func amountParam(query url.Values, name string, currency bank.Currency) (*bank.Money, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	amount, err := bank.ParseMoney(v, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &amount, nil
+}
+
+// listParam splits the values of a repeatable query parameter on commas.
+func listParam(values []string) []string {
+	var items []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// accountCurrency falls back to the default currency for an unknown
+// account; the operation itself then reports the account as missing.
+func accountCurrency(bs *bank.BankingSystem, accountNumber string) bank.Currency {
+	account, err := bs.GetAccount(accountNumber)
+	if err != nil {
+		return bank.DefaultCurrency
+	}
+	return account.Currency
+}
@@ -0,0 +1,623 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bank-system/api"
+	"bank-system/bank"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// client sends requests to the server, with a bearer token once logged in.
+type client struct {
+	t      *testing.T
+	server http.Handler
+	token  string
+}
+
+// do sends body, if any, as JSON and returns the response. headers are
+// name, value pairs.
+func (c client) do(method, path string, body any, headers ...string) *httptest.ResponseRecorder {
+	c.t.Helper()
+	var reader *bytes.Reader
+	switch body := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(body))
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	c.server.ServeHTTP(rec, req)
+	return rec
+}
+
+// expect checks the status of a response and decodes its body into v, if v
+// is not nil.
+func expect(t *testing.T, rec *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status %d, want %d; body %s", rec.Code, status, rec.Body)
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+	}
+}
+
+// expectError checks the status and error code of a failed request.
+func expectError(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	var resp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	expect(t, rec, status, &resp)
+	if resp.Error.Code != code || resp.Error.Message == "" {
+		t.Fatalf("error %+v, want code %q", resp.Error, code)
+	}
+}
+
+type user struct {
+	ID       int      `json:"id"`
+	Email    string   `json:"email"`
+	Accounts []string `json:"accounts"`
+	Role     string   `json:"role"`
+}
+
+type account struct {
+	AccountNumber string     `json:"account_number"`
+	Balance       bank.Money `json:"balance"`
+	Overdraft     bank.Money `json:"overdraft_limit"`
+	Held          bank.Money `json:"held"`
+	Status        string     `json:"status"`
+	StatusReason  string     `json:"status_reason"`
+}
+
+type transaction struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	Status       string     `json:"status"`
+	Amount       bank.Money `json:"amount"`
+	BalanceAfter bank.Money `json:"balance_after"`
+}
+
+type instruction struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+type testBank struct {
+	bs       *bank.BankingSystem
+	clock    *fakeClock
+	server   http.Handler
+	admin    client
+	customer client
+	// customerID owns SAV001 and SAV002; someone else owns SAV999.
+	customerID int
+}
+
+const password = "S3cure!Passw0rd"
+
+func signUp(t *testing.T, c client, email string) user {
+	t.Helper()
+	var u user
+	expect(t, c.do("POST", "/users", map[string]string{
+		"first_name":         "Test",
+		"last_name":          "User",
+		"email":              email,
+		"password":           password,
+		"address":            "Motihari, Bihar",
+		"phone":              "9876543210",
+		"pan_card_number":    "ABCPK1234F",
+		"aadhar_card_number": "234123412346",
+	}), http.StatusCreated, &u)
+	return u
+}
+
+func login(t *testing.T, c client, email string) client {
+	t.Helper()
+	var session struct {
+		Token     string `json:"token"`
+		TokenType string `json:"token_type"`
+	}
+	expect(t, c.do("POST", "/sessions", map[string]string{"email": email, "password": password}), http.StatusCreated, &session)
+	if session.Token == "" || session.TokenType != "Bearer" {
+		t.Fatalf("session %+v", session)
+	}
+	c.token = session.Token
+	return c
+}
+
+// openAccount opens an account through c and has the admin activate it, as
+// if the holder's identity had been verified.
+func (tb *testBank) openAccount(t *testing.T, c client, userID int, number, accountType string) {
+	t.Helper()
+	var opened account
+	expect(t, c.do("POST", "/accounts", map[string]any{
+		"user_id":        userID,
+		"account_number": number,
+		"holder_name":    "Test User",
+		"account_type":   accountType,
+	}), http.StatusCreated, &opened)
+	if opened.Status != string(bank.StatusPendingKYC) {
+		t.Errorf("new account %s is %s, want PendingKYC", number, opened.Status)
+	}
+	expect(t, tb.admin.do("PUT", "/accounts/"+number+"/status", map[string]string{"status": "Active", "reason": "KYC verified"}), http.StatusOK, nil)
+}
+
+// newTestBank serves a bank without fees on 10 February 2026, which opens
+// accounts pending KYC, with an admin and a customer logged in.
+func newTestBank(t *testing.T) *testBank {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2026, time.February, 10, 12, 0, 0, 0, time.UTC)}
+	bs := bank.NewBankingSystem(bank.WithClock(clock), bank.WithFeeSchedules(nil), bank.WithInitialAccountStatus(bank.StatusPendingKYC))
+	server := api.NewServer(bs)
+	anonymous := client{t: t, server: server}
+
+	admin := signUp(t, anonymous, "admin@example.com")
+	if err := bs.AssignRole(admin.ID, bank.RoleAdmin); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	customer := signUp(t, anonymous, "test.user@example.com")
+	other := signUp(t, anonymous, "other.user@example.com")
+
+	tb := &testBank{
+		bs:         bs,
+		clock:      clock,
+		server:     server,
+		admin:      login(t, anonymous, "admin@example.com"),
+		customer:   login(t, anonymous, "test.user@example.com"),
+		customerID: customer.ID,
+	}
+	tb.openAccount(t, tb.customer, customer.ID, "SAV001", "Savings")
+	tb.openAccount(t, tb.customer, customer.ID, "SAV002", "Savings")
+	tb.openAccount(t, tb.admin, other.ID, "SAV999", "Savings")
+	return tb
+}
+
+func TestSignUpAndSessions(t *testing.T) {
+	tb := newTestBank(t)
+	anonymous := client{t: t, server: tb.server}
+
+	rec := anonymous.do("POST", "/users", map[string]string{
+		"first_name": "Test", "last_name": "User", "email": "test.user@example.com", "password": password,
+		"address": "Motihari, Bihar", "phone": "9876543210", "pan_card_number": "ABCPK1234F", "aadhar_card_number": "234123412346",
+	})
+	expectError(t, rec, http.StatusConflict, "email_exists")
+	if strings.Contains(rec.Body.String(), password) {
+		t.Error("response contains the password")
+	}
+
+	var invalid struct {
+		Error struct {
+			Code   string `json:"code"`
+			Fields []struct {
+				Field string `json:"field"`
+			} `json:"fields"`
+		} `json:"error"`
+	}
+	expect(t, anonymous.do("POST", "/users", map[string]string{
+		"first_name": "Test", "last_name": "User", "email": "not-an-email", "password": password,
+		"address": "Motihari, Bihar", "phone": "12345", "pan_card_number": "ABCPK1234F", "aadhar_card_number": "234123412346",
+	}), http.StatusBadRequest, &invalid)
+	if invalid.Error.Code != "validation_failed" || len(invalid.Error.Fields) != 2 {
+		t.Errorf("invalid sign-up: %+v, want email and phone fields", invalid.Error)
+	}
+
+	rec = anonymous.do("POST", "/sessions", map[string]string{"email": "test.user@example.com", "password": "wrong"})
+	expectError(t, rec, http.StatusUnauthorized, "invalid_credentials")
+	if rec.Header().Get("WWW-Authenticate") == "" {
+		t.Error("401 without a WWW-Authenticate challenge")
+	}
+	expectError(t, anonymous.do("POST", "/sessions", `{"email": "test.user@example.com"`), http.StatusBadRequest, "invalid_request")
+
+	// Requests without a usable bearer token are refused.
+	for _, header := range []string{"", "Bearer", "Bearer ", "Basic " + tb.customer.token, "Bearer not-a-token"} {
+		rec := anonymous.do("GET", "/accounts/SAV001", nil, "Authorization", header)
+		expectError(t, rec, http.StatusUnauthorized, "unauthenticated")
+	}
+	expectError(t, anonymous.do("DELETE", "/sessions/current", nil), http.StatusUnauthorized, "unauthenticated")
+
+	// A second session survives logging out of the first.
+	second := login(t, anonymous, "test.user@example.com")
+	expect(t, tb.customer.do("DELETE", "/sessions/current", nil), http.StatusNoContent, nil)
+	expectError(t, tb.customer.do("GET", "/accounts/SAV001", nil), http.StatusUnauthorized, "unauthenticated")
+	expect(t, second.do("GET", "/accounts/SAV001", nil), http.StatusOK, nil)
+}
+
+func TestUserRoutes(t *testing.T) {
+	tb := newTestBank(t)
+
+	var u user
+	expect(t, tb.customer.do("GET", fmt.Sprintf("/users/%d", tb.customerID), nil), http.StatusOK, &u)
+	if u.Email != "test.user@example.com" || len(u.Accounts) != 2 || u.Role != "customer" {
+		t.Errorf("GET /users/{id} = %+v", u)
+	}
+	var accounts []account
+	expect(t, tb.customer.do("GET", fmt.Sprintf("/users/%d/accounts", tb.customerID), nil), http.StatusOK, &accounts)
+	if len(accounts) != 2 || accounts[0].AccountNumber != "SAV001" {
+		t.Errorf("GET /users/{id}/accounts = %+v", accounts)
+	}
+
+	expectError(t, tb.customer.do("GET", "/users", nil), http.StatusForbidden, "forbidden")
+	expectError(t, tb.customer.do("GET", "/users/1", nil), http.StatusForbidden, "forbidden")
+	expectError(t, tb.customer.do("GET", "/users/abc", nil), http.StatusBadRequest, "invalid_request")
+	expectError(t, tb.admin.do("GET", "/users/999", nil), http.StatusNotFound, "user_not_found")
+
+	var users []user
+	expect(t, tb.admin.do("GET", "/users", nil), http.StatusOK, &users)
+	if len(users) != 3 {
+		t.Errorf("GET /users returned %d users, want 3", len(users))
+	}
+
+	path := fmt.Sprintf("/users/%d/role", tb.customerID)
+	expectError(t, tb.customer.do("PUT", path, map[string]string{"role": "admin"}), http.StatusForbidden, "forbidden")
+	expectError(t, tb.admin.do("PUT", path, map[string]string{"role": "janitor"}), http.StatusBadRequest, "invalid_role")
+	expectError(t, tb.admin.do("PUT", path, map[string]any{"role": "teller", "extra": 1}), http.StatusBadRequest, "invalid_request")
+	expect(t, tb.admin.do("PUT", path, map[string]string{"role": "teller"}), http.StatusOK, &u)
+	if u.Role != "teller" {
+		t.Errorf("role after PUT /users/{id}/role = %q, want teller", u.Role)
+	}
+	// The new role applies to the session already open.
+	expect(t, tb.customer.do("GET", "/users/1", nil), http.StatusOK, nil)
+}
+
+func TestAccountRoutes(t *testing.T) {
+	tb := newTestBank(t)
+	c := tb.customer
+
+	expectError(t, c.do("POST", "/accounts", map[string]any{"user_id": tb.customerID, "account_number": "SAV001", "holder_name": "Test User", "account_type": "Savings"}),
+		http.StatusConflict, "account_exists")
+	expectError(t, c.do("POST", "/accounts", map[string]any{"user_id": 1, "account_number": "SAV003", "holder_name": "Test User", "account_type": "Savings"}),
+		http.StatusForbidden, "forbidden")
+
+	var a account
+	expect(t, c.do("GET", "/accounts/SAV001", nil), http.StatusOK, &a)
+	if a.AccountNumber != "SAV001" || !a.Balance.IsZero() {
+		t.Errorf("GET /accounts/{number} = %+v", a)
+	}
+	expectError(t, c.do("GET", "/accounts/SAV999", nil), http.StatusForbidden, "forbidden")
+	expectError(t, tb.admin.do("GET", "/accounts/NONE", nil), http.StatusNotFound, "account_not_found")
+	// Customers see only their own accounts.
+	var all []account
+	expect(t, c.do("GET", "/accounts", nil), http.StatusOK, &all)
+	if len(all) != 2 {
+		t.Errorf("GET /accounts by a customer returned %d accounts, want 2", len(all))
+	}
+	expect(t, tb.admin.do("GET", "/accounts", nil), http.StatusOK, &all)
+	if len(all) != 3 {
+		t.Errorf("GET /accounts by an admin returned %d accounts, want 3", len(all))
+	}
+
+	var tx transaction
+	expect(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "1000"}), http.StatusCreated, &tx)
+	if tx.Type != "DEPOSIT" || !tx.Amount.Equal(inr("1000")) || !tx.BalanceAfter.Equal(inr("1000")) {
+		t.Errorf("deposit = %+v", tx)
+	}
+	expect(t, c.do("POST", "/accounts/SAV001/withdrawals", map[string]string{"amount": "250.50", "currency": "INR"}), http.StatusCreated, &tx)
+	if !tx.BalanceAfter.Equal(inr("749.50")) {
+		t.Errorf("withdrawal = %+v", tx)
+	}
+	expectError(t, c.do("POST", "/accounts/SAV001/withdrawals", map[string]string{"amount": "5000"}), http.StatusUnprocessableEntity, "insufficient_funds")
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "abc"}), http.StatusBadRequest, "invalid_amount")
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "1.001"}), http.StatusBadRequest, "invalid_amount")
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "-5"}), http.StatusBadRequest, "invalid_amount")
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "5", "currency": "XYZ"}), http.StatusBadRequest, "unknown_currency")
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "5", "currency": "USD"}), http.StatusBadRequest, "currency_mismatch")
+	expectError(t, c.do("POST", "/accounts/SAV999/deposits", map[string]string{"amount": "5"}), http.StatusForbidden, "forbidden")
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", `{"amount": "5"} {}`), http.StatusBadRequest, "invalid_request")
+
+	// A hold reserves money until it is captured or released.
+	var hold transaction
+	expect(t, c.do("POST", "/accounts/SAV001/holds", map[string]string{"amount": "100", "description": "Hotel"}), http.StatusCreated, &hold)
+	if hold.Type != "HOLD" || hold.Status != "PENDING" {
+		t.Errorf("hold = %+v", hold)
+	}
+	var balance struct {
+		Balance   bank.Money `json:"balance"`
+		Available bank.Money `json:"available_balance"`
+	}
+	expect(t, c.do("GET", "/accounts/SAV001/balance", nil), http.StatusOK, &balance)
+	if !balance.Balance.Equal(inr("749.50")) || !balance.Available.Equal(inr("649.50")) {
+		t.Errorf("balance = %+v, want 749.50 with 649.50 available", balance)
+	}
+	expect(t, c.do("POST", "/transactions/"+hold.ID+"/capture", map[string]string{"amount": "80"}), http.StatusCreated, &tx)
+	if tx.Type != "WITHDRAWAL" || !tx.Amount.Equal(inr("80")) {
+		t.Errorf("capture = %+v", tx)
+	}
+	expectError(t, c.do("POST", "/transactions/"+hold.ID+"/release", nil), http.StatusConflict, "hold_not_pending")
+	expect(t, c.do("POST", "/accounts/SAV001/holds", map[string]string{"amount": "50"}), http.StatusCreated, &hold)
+	expect(t, c.do("POST", "/transactions/"+hold.ID+"/capture", map[string]string{}), http.StatusCreated, &tx)
+	if !tx.Amount.Equal(inr("50")) {
+		t.Errorf("capture of the whole hold took %s, want 50", tx.Amount)
+	}
+	expect(t, c.do("POST", "/accounts/SAV001/holds", map[string]string{"amount": "10"}), http.StatusCreated, &hold)
+	expect(t, c.do("POST", "/transactions/"+hold.ID+"/release", nil), http.StatusOK, &tx)
+	if tx.Status != "CANCELLED" {
+		t.Errorf("released hold = %+v", tx)
+	}
+	expectError(t, c.do("POST", "/transactions/NONE/release", nil), http.StatusNotFound, "transaction_not_found")
+
+	var summary struct {
+		TotalDeposits    bank.Money `json:"total_deposits"`
+		TotalWithdrawals bank.Money `json:"total_withdrawals"`
+		NetAmount        bank.Money `json:"net_amount"`
+	}
+	expect(t, c.do("GET", "/accounts/SAV001/summary", nil), http.StatusOK, &summary)
+	if !summary.TotalDeposits.Equal(inr("1000")) || !summary.TotalWithdrawals.Equal(inr("380.50")) || !summary.NetAmount.Equal(inr("619.50")) {
+		t.Errorf("summary = %+v", summary)
+	}
+	var transactions []transaction
+	expect(t, c.do("GET", "/accounts/SAV001/transactions", nil), http.StatusOK, &transactions)
+	// A deposit, a withdrawal, three holds and two captures.
+	if len(transactions) != 7 {
+		t.Errorf("GET /accounts/{number}/transactions returned %d, want 7", len(transactions))
+	}
+}
+
+func TestAccountStatusRoutes(t *testing.T) {
+	tb := newTestBank(t)
+	c := tb.customer
+	expect(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "100"}), http.StatusCreated, nil)
+
+	// Overdrafts are for current accounts, and only managers grant them.
+	expectError(t, c.do("PUT", "/accounts/SAV001/overdraft", map[string]string{"limit": "500"}), http.StatusForbidden, "forbidden")
+	expectError(t, tb.admin.do("PUT", "/accounts/SAV001/overdraft", map[string]string{"limit": "500"}), http.StatusUnprocessableEntity, "overdraft_not_allowed")
+	tb.openAccount(t, c, tb.customerID, "CUR001", "Current")
+	var a account
+	expect(t, tb.admin.do("PUT", "/accounts/CUR001/overdraft", map[string]string{"limit": "500"}), http.StatusOK, &a)
+	if !a.Overdraft.Equal(inr("500")) {
+		t.Errorf("overdraft limit %s, want 500", a.Overdraft)
+	}
+	expect(t, c.do("POST", "/accounts/CUR001/withdrawals", map[string]string{"amount": "300"}), http.StatusCreated, nil)
+
+	expectError(t, c.do("POST", "/accounts/SAV001/freeze", map[string]string{"reason": "fraud"}), http.StatusForbidden, "forbidden")
+	expect(t, tb.admin.do("POST", "/accounts/SAV001/freeze", map[string]string{"reason": "fraud"}), http.StatusOK, &a)
+	if a.Status != string(bank.StatusFrozen) || a.StatusReason != "fraud" {
+		t.Errorf("frozen account = %+v", a)
+	}
+	expectError(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "1"}), http.StatusUnprocessableEntity, "account_not_active")
+	expect(t, tb.admin.do("POST", "/accounts/SAV001/unfreeze", map[string]string{"reason": "cleared"}), http.StatusOK, &a)
+	if a.Status != string(bank.StatusActive) {
+		t.Errorf("unfrozen account = %+v", a)
+	}
+
+	expectError(t, tb.admin.do("PUT", "/accounts/SAV001/status", map[string]string{"status": "Sleeping", "reason": "x"}), http.StatusBadRequest, "invalid_status")
+	expect(t, tb.admin.do("PUT", "/accounts/SAV001/status", map[string]string{"status": "dormant", "reason": "unused"}), http.StatusOK, &a)
+	if a.Status != string(bank.StatusDormant) {
+		t.Errorf("status = %q, want Dormant", a.Status)
+	}
+	expectError(t, tb.admin.do("PUT", "/accounts/SAV001/status", map[string]string{"status": "DebitFrozen", "reason": "x"}), http.StatusUnprocessableEntity, "invalid_status_transition")
+
+	// Closing needs an empty account, and is final.
+	expectError(t, c.do("POST", "/accounts/CUR001/close", nil), http.StatusUnprocessableEntity, "non_zero_balance")
+	expect(t, c.do("POST", "/accounts/SAV002/close", nil), http.StatusOK, &a)
+	if a.Status != string(bank.StatusClosed) {
+		t.Errorf("closed account = %+v", a)
+	}
+	expectError(t, tb.admin.do("POST", "/accounts/SAV002/unfreeze", map[string]string{"reason": "x"}), http.StatusUnprocessableEntity, "invalid_status_transition")
+}
+
+func TestTransferAndTransactionRoutes(t *testing.T) {
+	tb := newTestBank(t)
+	c := tb.customer
+	expect(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "1000"}), http.StatusCreated, nil)
+
+	transfer := map[string]string{"from_account": "SAV001", "to_account": "SAV002", "amount": "100"}
+	var first, replay transaction
+	expect(t, c.do("POST", "/transfers", transfer, "Idempotency-Key", "k1"), http.StatusCreated, &first)
+	expect(t, c.do("POST", "/transfers", transfer, "Idempotency-Key", "k1"), http.StatusCreated, &replay)
+	if first.ID == "" || replay.ID != first.ID {
+		t.Errorf("replayed transfer %q, want %q", replay.ID, first.ID)
+	}
+	transfer["amount"] = "200"
+	expectError(t, c.do("POST", "/transfers", transfer, "Idempotency-Key", "k1"), http.StatusUnprocessableEntity, "idempotency_key_reused")
+	expect(t, c.do("POST", "/transfers", transfer), http.StatusCreated, nil)
+	expectError(t, c.do("POST", "/transfers", map[string]string{"from_account": "SAV001", "to_account": "SAV001", "amount": "1"}), http.StatusBadRequest, "same_account")
+	expectError(t, c.do("POST", "/transfers", map[string]string{"from_account": "SAV999", "to_account": "SAV001", "amount": "1"}), http.StatusForbidden, "forbidden")
+
+	var tx transaction
+	expect(t, c.do("GET", "/transactions/"+first.ID, nil), http.StatusOK, &tx)
+	if tx.ID != first.ID || tx.Type != "TRANSFER" {
+		t.Errorf("GET /transactions/{id} = %+v", tx)
+	}
+	expectError(t, c.do("GET", "/transactions/NONE", nil), http.StatusNotFound, "transaction_not_found")
+
+	// Pages follow the Link header.
+	var page []transaction
+	rec := c.do("GET", "/transactions?account=SAV002&type=transfer&limit=1", nil)
+	expect(t, rec, http.StatusOK, &page)
+	link := rec.Header().Get("Link")
+	if len(page) != 1 || page[0].ID != first.ID || !strings.HasSuffix(link, `>; rel="next"`) {
+		t.Fatalf("first page %+v, Link %q", page, link)
+	}
+	next, err := url.Parse(strings.TrimPrefix(strings.TrimSuffix(link, `>; rel="next"`), "<"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, c.do("GET", next.String(), nil), http.StatusOK, &page)
+	if len(page) != 1 || page[0].ID == first.ID || !page[0].Amount.Equal(inr("200")) {
+		t.Errorf("second page %+v", page)
+	}
+	expect(t, c.do("GET", "/transactions?account=SAV001&order=desc&min_amount=150", nil), http.StatusOK, &page)
+	if len(page) != 2 || !page[0].Amount.Equal(inr("200")) {
+		t.Errorf("filtered page %+v, want the transfer of 200 and the deposit", page)
+	}
+	for query, code := range map[string]string{
+		"account=SAV001&order=sideways":  "invalid_request",
+		"account=SAV001&limit=ten":       "invalid_request",
+		"account=SAV001&since=yesterday": "invalid_request",
+		"account=SAV001&min_amount=lots": "invalid_amount",
+		"account=SAV001&cursor=garbage":  "invalid_cursor",
+	} {
+		expectError(t, c.do("GET", "/transactions?"+query, nil), http.StatusBadRequest, code)
+	}
+	expectError(t, c.do("GET", "/transactions?account=SAV999", nil), http.StatusForbidden, "forbidden")
+
+	// Reversals need a reason and a member of staff.
+	expectError(t, c.do("POST", "/transactions/"+first.ID+"/reversal", map[string]string{"reason": "mistake"}), http.StatusForbidden, "forbidden")
+	expectError(t, tb.admin.do("POST", "/transactions/"+first.ID+"/reversal", map[string]string{}), http.StatusBadRequest, "invalid_input")
+	expect(t, tb.admin.do("POST", "/transactions/"+first.ID+"/reversal", map[string]string{"reason": "mistake"}), http.StatusCreated, &tx)
+	if tx.Type != "REVERSAL" || !tx.Amount.Equal(inr("100")) {
+		t.Errorf("reversal = %+v", tx)
+	}
+	expectError(t, tb.admin.do("POST", "/transactions/"+first.ID+"/reversal", map[string]string{"reason": "mistake"}), http.StatusConflict, "already_reversed")
+	expectError(t, tb.admin.do("POST", "/transactions/"+tx.ID+"/reversal", map[string]string{"reason": "mistake"}), http.StatusUnprocessableEntity, "not_reversible")
+}
+
+func TestStatementRoute(t *testing.T) {
+	tb := newTestBank(t)
+	c := tb.customer
+	expect(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "650"}), http.StatusCreated, nil)
+
+	expectError(t, c.do("GET", "/accounts/SAV001/statements/2026-02", nil), http.StatusBadRequest, "invalid_input")
+	tb.clock.Set(time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC))
+	// Weeks have passed, so the session has expired.
+	expectError(t, c.do("GET", "/accounts/SAV001", nil), http.StatusUnauthorized, "unauthenticated")
+	c = login(t, client{t: t, server: tb.server}, "test.user@example.com")
+
+	var statement struct {
+		AccountNumber  string     `json:"account_number"`
+		ClosingBalance bank.Money `json:"closing_balance"`
+		Entries        []struct {
+			Credit bank.Money `json:"credit"`
+		} `json:"entries"`
+	}
+	expect(t, c.do("GET", "/accounts/SAV001/statements/2026-02", nil), http.StatusOK, &statement)
+	if statement.AccountNumber != "SAV001" || len(statement.Entries) != 1 || !statement.ClosingBalance.Equal(inr("650")) {
+		t.Errorf("statement %+v", statement)
+	}
+
+	tests := []struct {
+		query, accept string
+		contentType   string
+		contains      string
+	}{
+		{"?format=text", "", "text/plain; charset=utf-8", "Closing balance"},
+		{"", "text/html, application/json;q=0.5", "text/html; charset=utf-8", "<td class=\"amount\">650.00</td>"},
+		{"?format=PDF", "text/html", "application/pdf", "%PDF-"},
+		{"", "application/xml, text/plain", "text/plain; charset=utf-8", "=== Account Statement ==="},
+	}
+	for _, tc := range tests {
+		rec := c.do("GET", "/accounts/SAV001/statements/2026-02"+tc.query, nil, "Accept", tc.accept)
+		expect(t, rec, http.StatusOK, nil)
+		if ct := rec.Header().Get("Content-Type"); ct != tc.contentType || !strings.Contains(rec.Body.String(), tc.contains) {
+			t.Errorf("%s (Accept %q): Content-Type %q, body missing %q", tc.query, tc.accept, ct, tc.contains)
+		}
+	}
+	rec := c.do("GET", "/accounts/SAV001/statements/2026-02?format=pdf", nil)
+	if cd := rec.Header().Get("Content-Disposition"); cd != `inline; filename="statement-SAV001-2026-02.pdf"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	expectError(t, c.do("GET", "/accounts/SAV001/statements/February", nil), http.StatusBadRequest, "invalid_request")
+	expectError(t, c.do("GET", "/accounts/SAV001/statements/2026-02?format=docx", nil), http.StatusBadRequest, "invalid_request")
+	expectError(t, c.do("GET", "/accounts/SAV999/statements/2026-02", nil), http.StatusForbidden, "forbidden")
+}
+
+func TestStandingInstructionRoutes(t *testing.T) {
+	tb := newTestBank(t)
+	c := tb.customer
+	expect(t, c.do("POST", "/accounts/SAV001/deposits", map[string]string{"amount": "1000"}), http.StatusCreated, nil)
+
+	request := map[string]any{
+		"from_account": "SAV001",
+		"to_account":   "SAV002",
+		"amount":       "100",
+		"description":  "Savings",
+		"frequency":    "monthly",
+		"start":        time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC),
+	}
+	var si instruction
+	expect(t, c.do("POST", "/standing-instructions", request), http.StatusCreated, &si)
+	if si.ID == 0 || si.Status != string(bank.InstructionActive) {
+		t.Errorf("created instruction %+v", si)
+	}
+	request["frequency"] = "hourly"
+	expectError(t, c.do("POST", "/standing-instructions", request), http.StatusBadRequest, "invalid_input")
+	request["frequency"], request["from_account"] = "weekly", "SAV999"
+	expectError(t, c.do("POST", "/standing-instructions", request), http.StatusForbidden, "forbidden")
+
+	path := fmt.Sprintf("/standing-instructions/%d", si.ID)
+	expect(t, c.do("GET", path, nil), http.StatusOK, &si)
+	var list []instruction
+	expect(t, c.do("GET", "/standing-instructions", nil), http.StatusOK, &list)
+	if len(list) != 1 || list[0].ID != si.ID {
+		t.Errorf("GET /standing-instructions = %+v", list)
+	}
+	expectError(t, c.do("GET", "/standing-instructions/abc", nil), http.StatusBadRequest, "invalid_request")
+	expectError(t, c.do("GET", "/standing-instructions/999", nil), http.StatusNotFound, "standing_instruction_not_found")
+
+	expect(t, c.do("POST", path+"/pause", nil), http.StatusOK, &si)
+	if si.Status != string(bank.InstructionPaused) {
+		t.Errorf("paused instruction %+v", si)
+	}
+	expectError(t, c.do("POST", path+"/pause", nil), http.StatusConflict, "standing_instruction_status")
+	expect(t, c.do("POST", path+"/resume", nil), http.StatusOK, &si)
+
+	// The first run is on 1 March.
+	tb.clock.Set(time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC))
+	if _, err := bank.NewScheduler(tb.bs).RunDue(); err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+	c = login(t, client{t: t, server: tb.server}, "test.user@example.com")
+	var attempts []struct {
+		Outcome       string `json:"outcome"`
+		TransactionID string `json:"transaction_id"`
+	}
+	expect(t, c.do("GET", path+"/attempts", nil), http.StatusOK, &attempts)
+	if len(attempts) != 1 || attempts[0].Outcome != string(bank.AttemptSucceeded) || attempts[0].TransactionID == "" {
+		t.Errorf("attempts %+v", attempts)
+	}
+
+	expect(t, c.do("POST", path+"/cancel", nil), http.StatusOK, &si)
+	if si.Status != string(bank.InstructionCancelled) {
+		t.Errorf("cancelled instruction %+v", si)
+	}
+	expectError(t, c.do("POST", path+"/resume", nil), http.StatusConflict, "standing_instruction_status")
+}
+
+func inr(s string) bank.Money {
+	return bank.MustParseMoney(s, bank.INR)
+}
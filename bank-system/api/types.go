@@ -0,0 +1,393 @@
+package api
+
+import (
+	"time"
+
+	"bank-system/bank"
+)
+
+type createUserRequest struct {
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	Email            string `json:"email"`
+	Password         string `json:"password"`
+	Address          string `json:"address"`
+	Phone            string `json:"phone"`
+	PanCardNumber    string `json:"pan_card_number"`
+	AadharCardNumber string `json:"aadhar_card_number"`
+}
+
+type assignRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type sessionResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type"`
+	UserID    int       `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type createAccountRequest struct {
+	UserID        int    `json:"user_id"`
+	AccountNumber string `json:"account_number"`
+	HolderName    string `json:"holder_name"`
+	AccountType   string `json:"account_type"`
+}
+
+// amountRequest is the body of a deposit or withdrawal. Amount is a decimal
+// string such as "250.50".
+type amountRequest struct {
+	Amount   string        `json:"amount"`
+	Currency bank.Currency `json:"currency,omitempty"`
+}
+
+func (r amountRequest) money(defaultCurrency bank.Currency) (bank.Money, error) {
+	currency := r.Currency
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	return bank.ParseMoney(r.Amount, currency)
+}
+
+// overdraftRequest sets an overdraft limit. A limit of "0" withdraws the
+// overdraft.
+type overdraftRequest struct {
+	Limit    string        `json:"limit"`
+	Currency bank.Currency `json:"currency,omitempty"`
+}
+
+func (r overdraftRequest) money(defaultCurrency bank.Currency) (bank.Money, error) {
+	return amountRequest{Amount: r.Limit, Currency: r.Currency}.money(defaultCurrency)
+}
+
+// statusRequest changes an account's status. Freezing and unfreezing take
+// only a reason.
+type statusRequest struct {
+	Status string `json:"status,omitempty"`
+	Reason string `json:"reason"`
+}
+
+type holdRequest struct {
+	amountRequest
+	Description string `json:"description"`
+}
+
+type reversalRequest struct {
+	Reason string `json:"reason"`
+}
+
+type transferRequest struct {
+	FromAccount string `json:"from_account"`
+	ToAccount   string `json:"to_account"`
+	amountRequest
+}
+
+// instructionRequest creates a standing instruction. Start and end are
+// RFC 3339 times; an instruction without a start runs first straight away.
+type instructionRequest struct {
+	FromAccount string `json:"from_account"`
+	ToAccount   string `json:"to_account"`
+	amountRequest
+	Description string     `json:"description"`
+	Frequency   string     `json:"frequency"`
+	DayOfMonth  int        `json:"day_of_month,omitempty"`
+	Start       *time.Time `json:"start,omitempty"`
+	End         *time.Time `json:"end,omitempty"`
+}
+
+func (r instructionRequest) recurrence() (bank.Recurrence, error) {
+	frequency, err := bank.ParseFrequency(r.Frequency)
+	if err != nil {
+		return bank.Recurrence{}, err
+	}
+
+	recurrence := bank.Recurrence{Frequency: frequency, DayOfMonth: r.DayOfMonth}
+	if r.Start != nil {
+		recurrence.Start = *r.Start
+	}
+	if r.End != nil {
+		recurrence.End = *r.End
+	}
+	return recurrence, nil
+}
+
+// userResponse deliberately leaves out the password.
+type userResponse struct {
+	ID               int      `json:"id"`
+	FirstName        string   `json:"first_name"`
+	LastName         string   `json:"last_name"`
+	Email            string   `json:"email"`
+	Address          string   `json:"address"`
+	Phone            string   `json:"phone"`
+	PanCardNumber    string   `json:"pan_card_number"`
+	AadharCardNumber string   `json:"aadhar_card_number"`
+	Accounts         []string `json:"accounts"`
+	Role             string   `json:"role"`
+}
+
+func newUserResponse(u bank.User) userResponse {
+	accounts := u.Accounts
+	if accounts == nil {
+		accounts = []string{}
+	}
+
+	return userResponse{
+		ID:               u.ID,
+		FirstName:        u.FirstName,
+		LastName:         u.LastName,
+		Email:            u.Email,
+		Address:          u.Address,
+		Phone:            u.Phone,
+		PanCardNumber:    u.PanCardNumber,
+		AadharCardNumber: u.AadharCardNumber,
+		Accounts:         accounts,
+		Role:             string(u.Role),
+	}
+}
+
+type accountResponse struct {
+	AccountNumber string        `json:"account_number"`
+	HolderName    string        `json:"holder_name"`
+	AccountType   string        `json:"account_type"`
+	Currency      bank.Currency `json:"currency"`
+	Balance       bank.Money    `json:"balance"`
+	Overdraft     bank.Money    `json:"overdraft_limit"`
+	Held          bank.Money    `json:"held"`
+	Status        string        `json:"status"`
+	StatusReason  string        `json:"status_reason,omitempty"`
+	StatusBy      string        `json:"status_changed_by,omitempty"`
+	StatusAt      *time.Time    `json:"status_changed_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+func newAccountResponse(a bank.Account) accountResponse {
+	resp := accountResponse{
+		AccountNumber: a.AccountNumber,
+		HolderName:    a.HolderName,
+		AccountType:   a.AccountType,
+		Currency:      a.Currency,
+		Balance:       a.Balance,
+		Overdraft:     bank.NewMoney(a.OverdraftLimit.MinorUnits(), a.Currency),
+		Held:          bank.NewMoney(a.HeldAmount.MinorUnits(), a.Currency),
+		Status:        string(a.Status),
+		StatusReason:  a.StatusReason,
+		StatusBy:      a.StatusChangedBy,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+	if !a.StatusChangedAt.IsZero() {
+		resp.StatusAt = &a.StatusChangedAt
+	}
+	return resp
+}
+
+func newAccountResponses(accounts []bank.Account) []accountResponse {
+	resp := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		resp[i] = newAccountResponse(account)
+	}
+	return resp
+}
+
+type balanceResponse struct {
+	AccountNumber string     `json:"account_number"`
+	Balance       bank.Money `json:"balance"`
+	Available     bank.Money `json:"available_balance"`
+}
+
+type transactionResponse struct {
+	ID              string                 `json:"id"`
+	Type            bank.TransactionType   `json:"type"`
+	Status          bank.TransactionStatus `json:"status"`
+	FromAccount     string                 `json:"from_account,omitempty"`
+	ToAccount       string                 `json:"to_account,omitempty"`
+	Amount          bank.Money             `json:"amount"`
+	Fee             bank.Money             `json:"fee"`
+	BalanceAfter    bank.Money             `json:"balance_after"`
+	Timestamp       time.Time              `json:"timestamp"`
+	Description     string                 `json:"description"`
+	ReferenceNumber string                 `json:"reference_number"`
+	LinkedTo        string                 `json:"linked_transaction_id,omitempty"`
+	ExpiresAt       *time.Time             `json:"expires_at,omitempty"`
+}
+
+func newTransactionResponse(t *bank.Transaction) transactionResponse {
+	resp := transactionResponse{
+		ID:              t.ID,
+		Type:            t.Type,
+		Status:          t.Status,
+		FromAccount:     t.FromAccount,
+		ToAccount:       t.ToAccount,
+		Amount:          t.Amount,
+		Fee:             t.Fee,
+		BalanceAfter:    t.BalanceAfter,
+		Timestamp:       t.Timestamp,
+		Description:     t.Description,
+		ReferenceNumber: t.ReferenceNumber,
+		LinkedTo:        t.LinkedTransactionID,
+	}
+	if !t.ExpiresAt.IsZero() {
+		resp.ExpiresAt = &t.ExpiresAt
+	}
+	return resp
+}
+
+func newTransactionResponses(transactions []*bank.Transaction) []transactionResponse {
+	resp := make([]transactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		resp[i] = newTransactionResponse(transaction)
+	}
+	return resp
+}
+
+type summaryResponse struct {
+	AccountNumber     string     `json:"account_number"`
+	TotalDeposits     bank.Money `json:"total_deposits"`
+	TotalWithdrawals  bank.Money `json:"total_withdrawals"`
+	TotalTransfersIn  bank.Money `json:"total_transfers_in"`
+	TotalTransfersOut bank.Money `json:"total_transfers_out"`
+	TotalFees         bank.Money `json:"total_fees"`
+	TotalInterest     bank.Money `json:"total_interest"`
+	NetAmount         bank.Money `json:"net_amount"`
+	TransactionCount  int        `json:"transaction_count"`
+	LastTransaction   *time.Time `json:"last_transaction,omitempty"`
+}
+
+func newSummaryResponse(s *bank.TransactionSummary, net bank.Money) summaryResponse {
+	resp := summaryResponse{
+		AccountNumber:     s.AccountNumber,
+		TotalDeposits:     s.TotalDeposits,
+		TotalWithdrawals:  s.TotalWithdrawals,
+		TotalTransfersIn:  s.TotalTransfersIn,
+		TotalTransfersOut: s.TotalTransfersOut,
+		TotalFees:         s.TotalFees,
+		TotalInterest:     s.TotalInterest,
+		NetAmount:         net,
+		TransactionCount:  s.TransactionCount,
+	}
+	if !s.LastTransaction.IsZero() {
+		resp.LastTransaction = &s.LastTransaction
+	}
+	return resp
+}
+
+type statementResponse struct {
+	AccountNumber  string                   `json:"account_number"`
+	HolderName     string                   `json:"holder_name"`
+	AccountType    string                   `json:"account_type"`
+	Currency       bank.Currency            `json:"currency"`
+	From           time.Time                `json:"from"`
+	To             time.Time                `json:"to"`
+	OpeningBalance bank.Money               `json:"opening_balance"`
+	Entries        []statementEntryResponse `json:"entries"`
+	ClosingBalance bank.Money               `json:"closing_balance"`
+	TotalCredits   bank.Money               `json:"total_credits"`
+	TotalDebits    bank.Money               `json:"total_debits"`
+	GeneratedAt    time.Time                `json:"generated_at"`
+}
+
+type statementEntryResponse struct {
+	Date            time.Time            `json:"date"`
+	TransactionID   string               `json:"transaction_id"`
+	ReferenceNumber string               `json:"reference_number,omitempty"`
+	Type            bank.TransactionType `json:"type,omitempty"`
+	Description     string               `json:"description"`
+	Credit          bank.Money           `json:"credit"`
+	Debit           bank.Money           `json:"debit"`
+	Balance         bank.Money           `json:"balance"`
+}
+
+func newStatementResponse(st *bank.Statement) statementResponse {
+	resp := statementResponse{
+		AccountNumber:  st.AccountNumber,
+		HolderName:     st.HolderName,
+		AccountType:    st.AccountType,
+		Currency:       st.Currency,
+		From:           st.From,
+		To:             st.To,
+		OpeningBalance: st.OpeningBalance,
+		Entries:        make([]statementEntryResponse, len(st.Entries)),
+		ClosingBalance: st.ClosingBalance,
+		TotalCredits:   st.TotalCredits,
+		TotalDebits:    st.TotalDebits,
+		GeneratedAt:    st.GeneratedAt,
+	}
+	for i, entry := range st.Entries {
+		resp.Entries[i] = statementEntryResponse(entry)
+	}
+	return resp
+}
+
+type instructionResponse struct {
+	ID          int                    `json:"id"`
+	FromAccount string                 `json:"from_account"`
+	ToAccount   string                 `json:"to_account"`
+	Amount      bank.Money             `json:"amount"`
+	Description string                 `json:"description"`
+	Frequency   bank.Frequency         `json:"frequency"`
+	DayOfMonth  int                    `json:"day_of_month,omitempty"`
+	Start       time.Time              `json:"start"`
+	End         *time.Time             `json:"end,omitempty"`
+	Status      bank.InstructionStatus `json:"status"`
+	NextRun     *time.Time             `json:"next_run,omitempty"`
+	RetryAt     *time.Time             `json:"retry_at,omitempty"`
+	Retries     int                    `json:"retries"`
+	CreatedBy   string                 `json:"created_by"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func newInstructionResponse(i bank.StandingInstruction) instructionResponse {
+	return instructionResponse{
+		ID:          i.ID,
+		FromAccount: i.FromAccount,
+		ToAccount:   i.ToAccount,
+		Amount:      i.Amount,
+		Description: i.Description,
+		Frequency:   i.Recurrence.Frequency,
+		DayOfMonth:  i.Recurrence.DayOfMonth,
+		Start:       i.Recurrence.Start,
+		End:         optionalTime(i.Recurrence.End),
+		Status:      i.Status,
+		NextRun:     optionalTime(i.NextRun),
+		RetryAt:     optionalTime(i.RetryAt),
+		Retries:     i.Retries,
+		CreatedBy:   i.CreatedBy,
+		CreatedAt:   i.CreatedAt,
+		UpdatedAt:   i.UpdatedAt,
+	}
+}
+
+type attemptResponse struct {
+	Sequence      int                 `json:"sequence"`
+	ScheduledFor  time.Time           `json:"scheduled_for"`
+	AttemptedAt   time.Time           `json:"attempted_at"`
+	Outcome       bank.AttemptOutcome `json:"outcome"`
+	TransactionID string              `json:"transaction_id,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+func newAttemptResponse(a bank.InstructionAttempt) attemptResponse {
+	return attemptResponse{
+		Sequence:      a.Sequence,
+		ScheduledFor:  a.ScheduledFor,
+		AttemptedAt:   a.AttemptedAt,
+		Outcome:       a.Outcome,
+		TransactionID: a.TransactionID,
+		Error:         a.Error,
+	}
+}
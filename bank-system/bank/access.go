@@ -0,0 +1,287 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrInvalidRole = errors.New("invalid role")
+
+// Role is what a user does at the bank. It decides, through a Policy, which
+// operations the user may run.
+type Role string
+
+const (
+	RoleCustomer      Role = "customer"
+	RoleTeller        Role = "teller"
+	RoleBranchManager Role = "branch_manager"
+	RoleAuditor       Role = "auditor"
+	RoleAdmin         Role = "admin"
+)
+
+// Roles lists every role from least to most privileged.
+var Roles = []Role{RoleCustomer, RoleTeller, RoleBranchManager, RoleAuditor, RoleAdmin}
+
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if !slices.Contains(Roles, role) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidRole, s)
+	}
+	return role, nil
+}
+
+// Action is an operation that is subject to authorization.
+type Action string
+
+const (
+	ActionViewUser        Action = "view user"
+	ActionListUsers       Action = "list users"
+	ActionAssignRole      Action = "assign role"
+	ActionChangePassword  Action = "change password"
+	ActionOpenAccount     Action = "open account"
+	ActionViewAccount     Action = "view account"
+	ActionDeposit         Action = "deposit"
+	ActionWithdraw        Action = "withdraw"
+	ActionTransfer        Action = "transfer"
+	ActionCloseAccount    Action = "close account"
+	ActionViewTransaction Action = "view transaction"
+	ActionViewLedger      Action = "view ledger"
+	ActionUnmaskPII       Action = "unmask personal details"
+	ActionRotateKeys      Action = "rotate encryption keys"
+	ActionViewAudit       Action = "view audit trail"
+	ActionRunBatch        Action = "run batch jobs"
+	ActionSetOverdraft    Action = "set overdraft limit"
+	// ActionChangeAccountStatus covers freezing, unfreezing and the other
+	// lifecycle changes of ChangeAccountStatus.
+	ActionChangeAccountStatus Action = "change account status"
+	ActionReverseTransaction  Action = "reverse transaction"
+)
+
+// Scope is how far a role may take an action.
+type Scope int
+
+const (
+	// ScopeNone forbids the action.
+	ScopeNone Scope = iota
+	// ScopeOwn allows the action on the user's own record and accounts.
+	ScopeOwn
+	// ScopeAny allows the action on anyone's.
+	ScopeAny
+)
+
+// Policy grants each role a scope per action. Anything not listed is
+// forbidden.
+type Policy map[Role]map[Action]Scope
+
+// DefaultPolicy lets customers manage their own banking, tellers serve any
+// customer at the counter and reverse mistaken transactions, branch managers
+// also see the ledger and full customer details, sanction overdrafts,
+// activate accounts once KYC is done and freeze them, auditors read
+// everything but change nothing and see personal details masked, and admins
+// do anything.
+var DefaultPolicy = Policy{
+	RoleCustomer: {
+		ActionViewUser:        ScopeOwn,
+		ActionChangePassword:  ScopeOwn,
+		ActionOpenAccount:     ScopeOwn,
+		ActionViewAccount:     ScopeOwn,
+		ActionDeposit:         ScopeOwn,
+		ActionWithdraw:        ScopeOwn,
+		ActionTransfer:        ScopeOwn,
+		ActionCloseAccount:    ScopeOwn,
+		ActionViewTransaction: ScopeOwn,
+		ActionUnmaskPII:       ScopeOwn,
+	},
+	RoleTeller: {
+		ActionViewUser:           ScopeAny,
+		ActionChangePassword:     ScopeOwn,
+		ActionOpenAccount:        ScopeAny,
+		ActionViewAccount:        ScopeAny,
+		ActionDeposit:            ScopeAny,
+		ActionWithdraw:           ScopeAny,
+		ActionTransfer:           ScopeAny,
+		ActionCloseAccount:       ScopeOwn,
+		ActionViewTransaction:    ScopeAny,
+		ActionReverseTransaction: ScopeAny,
+	},
+	RoleBranchManager: {
+		ActionViewUser:            ScopeAny,
+		ActionChangePassword:      ScopeOwn,
+		ActionOpenAccount:         ScopeAny,
+		ActionViewAccount:         ScopeAny,
+		ActionDeposit:             ScopeAny,
+		ActionWithdraw:            ScopeAny,
+		ActionTransfer:            ScopeAny,
+		ActionCloseAccount:        ScopeOwn,
+		ActionViewTransaction:     ScopeAny,
+		ActionReverseTransaction:  ScopeAny,
+		ActionViewLedger:          ScopeAny,
+		ActionUnmaskPII:           ScopeAny,
+		ActionSetOverdraft:        ScopeAny,
+		ActionChangeAccountStatus: ScopeAny,
+	},
+	RoleAuditor: {
+		ActionViewUser:        ScopeAny,
+		ActionChangePassword:  ScopeOwn,
+		ActionViewAccount:     ScopeAny,
+		ActionViewTransaction: ScopeAny,
+		ActionViewLedger:      ScopeAny,
+		ActionViewAudit:       ScopeAny,
+	},
+	RoleAdmin: {
+		ActionViewUser:            ScopeAny,
+		ActionListUsers:           ScopeAny,
+		ActionAssignRole:          ScopeAny,
+		ActionChangePassword:      ScopeAny,
+		ActionOpenAccount:         ScopeAny,
+		ActionViewAccount:         ScopeAny,
+		ActionDeposit:             ScopeAny,
+		ActionWithdraw:            ScopeAny,
+		ActionTransfer:            ScopeAny,
+		ActionCloseAccount:        ScopeAny,
+		ActionViewTransaction:     ScopeAny,
+		ActionReverseTransaction:  ScopeAny,
+		ActionViewLedger:          ScopeAny,
+		ActionUnmaskPII:           ScopeAny,
+		ActionRotateKeys:          ScopeAny,
+		ActionViewAudit:           ScopeAny,
+		ActionRunBatch:            ScopeAny,
+		ActionSetOverdraft:        ScopeAny,
+		ActionChangeAccountStatus: ScopeAny,
+	},
+}
+
+// WithPolicy replaces DefaultPolicy.
+func WithPolicy(policy Policy) Option {
+	return func(bs *BankingSystem) {
+		bs.policy = policy
+	}
+}
+
+// resource is what an action is applied to. A user owns the resource if it
+// is their user record or one of their accounts; nobody owns the zero
+// resource, which stands for the whole bank.
+type resource struct {
+	userID   int
+	accounts []string
+}
+
+var bankResource = resource{}
+
+func userResource(userID int) resource {
+	return resource{userID: userID}
+}
+
+func accountResource(accountNumbers ...string) resource {
+	return resource{accounts: accountNumbers}
+}
+
+func transactionResource(transaction *Transaction) resource {
+	return accountResource(transaction.FromAccount, transaction.ToAccount)
+}
+
+func (r resource) ownedBy(user *User) bool {
+	if r.userID != 0 && r.userID == user.ID {
+		return true
+	}
+	for _, accountNumber := range r.accounts {
+		if accountNumber != "" && slices.Contains(user.Accounts, accountNumber) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizer returns the access check for whoever bs acts for. Every
+// authorization decision goes through it; callers that check many resources
+// at once, such as listings, use it to look the principal up only once.
+func (bs *BankingSystem) authorizer() (func(Action, resource) error, error) {
+	if bs.principal == nil {
+		return func(Action, resource) error { return nil }, nil
+	}
+	user, role, err := bs.caller()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(action Action, res resource) error {
+		switch bs.policy[role][action] {
+		case ScopeAny:
+			return nil
+		case ScopeOwn:
+			if res.ownedBy(user) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s may not %s", ErrForbidden, role, action)
+	}, nil
+}
+
+// caller looks up the user bs acts for and their role. The role is read from
+// the store rather than the session, so a change of role applies to
+// sessions that are already open.
+func (bs *BankingSystem) caller() (*User, Role, error) {
+	user, err := bs.users.Get(bs.principal.UserID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, "", ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	role := user.Role
+	if role == "" {
+		role = RoleCustomer
+	}
+	return user, role, nil
+}
+
+// accountScope returns the accounts bs may perform action on: all of them if
+// all is set, otherwise only those listed. Searches use it to look only
+// through what the caller may see.
+func (bs *BankingSystem) accountScope(action Action) (accounts []string, all bool, err error) {
+	if bs.principal == nil {
+		return nil, true, nil
+	}
+	user, role, err := bs.caller()
+	if err != nil {
+		return nil, false, err
+	}
+	if bs.policy[role][action] == ScopeAny {
+		return nil, true, nil
+	}
+	return user.Accounts, false, nil
+}
+
+// authorize checks that bs may perform action on res.
+func (bs *BankingSystem) authorize(action Action, res resource) error {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return err
+	}
+	return allowed(action, res)
+}
+
+// AssignRole changes what a user is allowed to do. It takes effect
+// immediately, including for the user's open sessions.
+func (bs *BankingSystem) AssignRole(userID int, role Role) error {
+	if _, err := ParseRole(string(role)); err != nil {
+		return err
+	}
+	if err := bs.authorize(ActionAssignRole, userResource(userID)); err != nil {
+		return err
+	}
+
+	unlock := bs.accountLocks.lock(userLockKey(userID))
+	defer unlock()
+
+	return bs.update(func(s *services) error {
+		user, err := s.users.Get(userID)
+		if err != nil {
+			return err
+		}
+		user.Role = role
+		return s.users.Update(*user)
+	})
+}
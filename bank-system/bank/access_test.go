@@ -0,0 +1,256 @@
+package bank_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"bank-system/bank"
+)
+
+// accessTarget is what an action is tried on: one user's record, accounts
+// and transaction.
+type accessTarget struct {
+	userID int
+	// account is a current account holding ₹1000 and empty is a savings
+	// account with nothing in it, for closing.
+	account, empty string
+	// counterparty receives transfers from account.
+	counterparty string
+	// deposit is the transaction that funded account.
+	deposit string
+}
+
+// newAccessBank returns a bank acting for a user with role, the user's own
+// records and another customer's.
+func newAccessBank(t *testing.T, role bank.Role) (as *bank.BankingSystem, own, other accessTarget) {
+	t.Helper()
+	bs := bank.NewBankingSystem(bank.WithPasswordParams(cheapParams), bank.WithFeeSchedules(nil))
+
+	setUp := func(email, prefix string) accessTarget {
+		t.Helper()
+		user, err := bs.CreateUser("Test", "User", email, "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		target := accessTarget{userID: user.ID, account: prefix + "001", empty: prefix + "002"}
+		openAccount(t, bs, target.account, "Current", user.ID)
+		openAccount(t, bs, target.empty, "Savings", user.ID)
+		deposit, err := bs.Deposit(target.account, inr("1000"))
+		if err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+		target.deposit = deposit.ID
+		return target
+	}
+	own = setUp("actor@example.com", "ACT")
+	other = setUp("other@example.com", "OTH")
+	own.counterparty, other.counterparty = other.account, own.account
+
+	if err := bs.AssignRole(own.userID, role); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	return bs.As(bank.Principal{UserID: own.userID, Email: "actor@example.com"}), own, other
+}
+
+func TestAccessControl(t *testing.T) {
+	var (
+		everyone = bank.Roles
+		staff    = []bank.Role{bank.RoleTeller, bank.RoleBranchManager, bank.RoleAuditor, bank.RoleAdmin}
+		movers   = []bank.Role{bank.RoleCustomer, bank.RoleTeller, bank.RoleBranchManager, bank.RoleAdmin}
+		counter  = []bank.Role{bank.RoleTeller, bank.RoleBranchManager, bank.RoleAdmin}
+		managers = []bank.Role{bank.RoleBranchManager, bank.RoleAdmin}
+		readers  = []bank.Role{bank.RoleBranchManager, bank.RoleAuditor, bank.RoleAdmin}
+		admins   = []bank.Role{bank.RoleAdmin}
+	)
+
+	tests := []struct {
+		action bank.Action
+		try    func(bs *bank.BankingSystem, target accessTarget) error
+		// own and other list the roles allowed the action on their own
+		// records and on another customer's. Actions on the whole bank
+		// list the same roles for both.
+		own, other []bank.Role
+	}{
+		{bank.ActionViewUser, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.GetUser(target.userID)
+			return err
+		}, everyone, staff},
+		{bank.ActionListUsers, func(bs *bank.BankingSystem, _ accessTarget) error {
+			_, err := bs.ListUsers()
+			return err
+		}, admins, admins},
+		{bank.ActionAssignRole, func(bs *bank.BankingSystem, target accessTarget) error {
+			return bs.AssignRole(target.userID, bank.RoleCustomer)
+		}, admins, admins},
+		{bank.ActionChangePassword, func(bs *bank.BankingSystem, target accessTarget) error {
+			return bs.ChangePassword(target.userID, "S3cure!Passw0rd", "N3w!Passw0rd")
+		}, everyone, admins},
+		{bank.ActionOpenAccount, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.CreateAccount("NEW001", "Test User", "Savings", target.userID)
+			return err
+		}, movers, counter},
+		{bank.ActionViewAccount, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.GetAccount(target.account)
+			return err
+		}, everyone, staff},
+		{bank.ActionDeposit, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.Deposit(target.account, inr("10"))
+			return err
+		}, movers, counter},
+		{bank.ActionWithdraw, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.Withdraw(target.account, inr("10"))
+			return err
+		}, movers, counter},
+		{bank.ActionTransfer, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.Transfer(target.account, target.counterparty, inr("10"))
+			return err
+		}, movers, counter},
+		{bank.ActionCloseAccount, func(bs *bank.BankingSystem, target accessTarget) error {
+			return bs.CloseAccount(target.empty)
+		}, movers, admins},
+		{bank.ActionViewTransaction, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.GetTransaction(target.deposit)
+			return err
+		}, everyone, staff},
+		{bank.ActionReverseTransaction, func(bs *bank.BankingSystem, target accessTarget) error {
+			_, err := bs.ReverseTransaction(target.deposit, "deposited in error")
+			return err
+		}, counter, counter},
+		{bank.ActionViewLedger, func(bs *bank.BankingSystem, _ accessTarget) error {
+			_, err := bs.GetTrialBalance()
+			return err
+		}, readers, readers},
+		{bank.ActionUnmaskPII, func(bs *bank.BankingSystem, target accessTarget) error {
+			user, err := bs.GetUser(target.userID)
+			if err != nil {
+				return err
+			}
+			// Without the permission the user is still visible, masked.
+			if user.PanCardNumber != "ABCPK1234F" {
+				return bank.ErrForbidden
+			}
+			return nil
+		}, []bank.Role{bank.RoleCustomer, bank.RoleBranchManager, bank.RoleAdmin}, managers},
+		{bank.ActionRotateKeys, func(bs *bank.BankingSystem, _ accessTarget) error {
+			// The test bank has no keys to rotate; getting that far is
+			// enough.
+			if _, err := bs.RotateEncryptionKey(); !errors.Is(err, bank.ErrNoKeyProvider) {
+				return err
+			}
+			return nil
+		}, admins, admins},
+		{bank.ActionViewAudit, func(bs *bank.BankingSystem, _ accessTarget) error {
+			_, err := bs.ListAuditEntries()
+			return err
+		}, []bank.Role{bank.RoleAuditor, bank.RoleAdmin}, []bank.Role{bank.RoleAuditor, bank.RoleAdmin}},
+		{bank.ActionRunBatch, func(bs *bank.BankingSystem, _ accessTarget) error {
+			_, err := bs.ExpireHolds()
+			return err
+		}, admins, admins},
+		{bank.ActionSetOverdraft, func(bs *bank.BankingSystem, target accessTarget) error {
+			return bs.SetOverdraftLimit(target.account, inr("500"))
+		}, managers, managers},
+		{bank.ActionChangeAccountStatus, func(bs *bank.BankingSystem, target accessTarget) error {
+			return bs.FreezeAccount(target.account, "suspected fraud")
+		}, managers, managers},
+	}
+
+	for _, tc := range tests {
+		for _, role := range bank.Roles {
+			for _, whose := range []string{"own", "other"} {
+				t.Run(string(tc.action)+"/"+string(role)+"/"+whose, func(t *testing.T) {
+					bs, own, other := newAccessBank(t, role)
+					target, allowed := own, slices.Contains(tc.own, role)
+					if whose == "other" {
+						target, allowed = other, slices.Contains(tc.other, role)
+					}
+
+					err := tc.try(bs, target)
+					switch {
+					case allowed && err != nil:
+						t.Errorf("%s on %s records: %v, want it allowed", role, whose, err)
+					case !allowed && !errors.Is(err, bank.ErrForbidden):
+						t.Errorf("%s on %s records: %v, want ErrForbidden", role, whose, err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestAccessControlListings(t *testing.T) {
+	// Listings are filtered to what the caller may see rather than refused.
+	for _, tc := range []struct {
+		role     bank.Role
+		accounts int
+	}{
+		{bank.RoleCustomer, 2},
+		{bank.RoleTeller, 4},
+		{bank.RoleAuditor, 4},
+	} {
+		t.Run(string(tc.role), func(t *testing.T) {
+			bs, own, other := newAccessBank(t, tc.role)
+
+			accounts, err := bs.ListAccounts()
+			if err != nil {
+				t.Fatalf("ListAccounts: %v", err)
+			}
+			if len(accounts) != tc.accounts {
+				t.Errorf("ListAccounts returned %d accounts, want %d", len(accounts), tc.accounts)
+			}
+			transactions, err := bs.ListTransactions()
+			if err != nil {
+				t.Fatalf("ListTransactions: %v", err)
+			}
+			if len(transactions) != tc.accounts/2 {
+				t.Errorf("ListTransactions returned %d transactions, want %d", len(transactions), tc.accounts/2)
+			}
+
+			_, err = bs.ListUserAccounts(other.userID)
+			if tc.role == bank.RoleCustomer && !errors.Is(err, bank.ErrForbidden) {
+				t.Errorf("ListUserAccounts of another customer = %v, want ErrForbidden", err)
+			}
+			if mine, err := bs.ListUserAccounts(own.userID); err != nil || len(mine) != 2 {
+				t.Errorf("ListUserAccounts of self = %d accounts, %v; want 2, nil", len(mine), err)
+			}
+		})
+	}
+}
+
+func TestAuditorIsReadOnly(t *testing.T) {
+	bs, _, other := newAccessBank(t, bank.RoleAuditor)
+
+	// An auditor sees everything, with personal details masked...
+	user, err := bs.GetUser(other.userID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.PanCardNumber != "XXXXXX234F" || user.AadharCardNumber != "XXXX-XXXX-2346" {
+		t.Errorf("auditor sees PAN %q and Aadhaar %q, want them masked", user.PanCardNumber, user.AadharCardNumber)
+	}
+	if _, err := bs.GetBalance(other.account); err != nil {
+		t.Errorf("GetBalance: %v", err)
+	}
+	if _, err := bs.ReconcileLedger(); err != nil {
+		t.Errorf("ReconcileLedger: %v", err)
+	}
+
+	// ...but changes nothing, not even what can be done through a hold or a
+	// standing instruction.
+	for name, err := range map[string]error{
+		"PlaceHold": func() error { _, err := bs.PlaceHold(other.account, inr("10"), "hold"); return err }(),
+		"CreateStandingInstruction": func() error {
+			_, err := bs.CreateStandingInstruction(other.account, other.counterparty, inr("10"), "rent",
+				bank.Recurrence{Frequency: bank.Monthly, Start: date(2026, 3, 1)})
+			return err
+		}(),
+		"AccrueInterest":    func() error { _, err := bs.AccrueInterest(date(2026, 3, 1)); return err }(),
+		"ChargeMonthlyFees": func() error { _, err := bs.ChargeMonthlyFees(date(2026, 3, 1)); return err }(),
+	} {
+		if !errors.Is(err, bank.ErrForbidden) {
+			t.Errorf("%s by an auditor = %v, want ErrForbidden", name, err)
+		}
+	}
+}
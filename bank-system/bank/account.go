@@ -3,26 +3,62 @@ package bank
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 )
 
 type Account struct {
 	AccountNumber string
 	HolderName    string
-	Balance       float64
+	Balance       Money
+	Currency      Currency
 	AccountType   string
-	Status        string
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	Status        AccountStatus
+	// StatusReason, StatusChangedBy and StatusChangedAt record the last
+	// change of Status: why it was made, by whom, and when.
+	StatusReason    string
+	StatusChangedBy string
+	StatusChangedAt time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	// InterestCreditedUntil is the end of the last period interest was
+	// credited for; interest accrues again from there. See AccrueInterest.
+	InterestCreditedUntil time.Time
+	// FeesChargedUntil is the end of the last month monthly fees were
+	// charged for. See ChargeMonthlyFees.
+	FeesChargedUntil time.Time
+	// OverdraftLimit is how far below zero the balance may go. Only current
+	// accounts have one.
+	OverdraftLimit Money
+	// HeldAmount is the total of the account's pending holds. See
+	// PlaceHold.
+	HeldAmount Money
 }
 
 type AccountService interface {
 	Create(account Account) (*Account, error)
-	Deposit(accountNumber string, amount float64) error
-	Withdraw(accountNumber string, amount float64) error
-	GetBalance(accountNumber string) (float64, error)
+	Deposit(accountNumber string, amount Money) error
+	Withdraw(accountNumber string, amount Money) error
+	// Debit takes money from an account even beyond its overdraft limit.
+	// It is for charges the bank levies, such as overdraft interest.
+	Debit(accountNumber string, amount Money) error
+	GetBalance(accountNumber string) (Money, error)
 	GetAccountDetails(accountNumber string) (*Account, error)
-	CloseAccount(accountNumber string) error
+	// ChangeStatus moves an account to another status if the transition
+	// table allows it. Closing an account requires a zero balance.
+	ChangeStatus(accountNumber string, status AccountStatus, reason, actor string) error
+	MarkInterestCredited(accountNumber string, until time.Time) error
+	MarkFeesCharged(accountNumber string, until time.Time) error
+	SetOverdraftLimit(accountNumber string, limit Money) error
+	// AvailableBalance is the balance plus any unused overdraft, less the
+	// money held.
+	AvailableBalance(accountNumber string) (Money, error)
+	// Hold reserves money that is available, and ReleaseHold makes it
+	// available again.
+	Hold(accountNumber string, amount Money) error
+	ReleaseHold(accountNumber string, amount Money) error
+	List() ([]Account, error)
 }
 
 var (
@@ -30,15 +66,20 @@ var (
 	ErrInsufficientFunds = errors.New("insufficient funds")
 	ErrInvalidAmount     = errors.New("invalid amount")
 	ErrAccountExists     = errors.New("account already exists")
+	ErrAccountNotActive  = errors.New("account is not active")
+	ErrNonZeroBalance    = errors.New("account balance must be zero to close the account")
 )
 
 type accountService struct {
-	accounts map[string]Account
+	mu    sync.Mutex
+	repo  AccountRepository
+	clock Clock
 }
 
-func NewAccountService() AccountService {
+func NewAccountService(repo AccountRepository, clock Clock) AccountService {
 	return &accountService{
-		accounts: make(map[string]Account),
+		repo:  repo,
+		clock: clock,
 	}
 }
 
@@ -47,108 +88,304 @@ func (ac *accountService) Create(account Account) (*Account, error) {
 		return nil, ErrInvalidInput
 	}
 
-	if _, exists := ac.accounts[account.AccountNumber]; exists {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	_, err := ac.repo.Get(account.AccountNumber)
+	if err == nil {
 		return nil, ErrAccountExists
 	}
+	if !errors.Is(err, ErrAccountNotFound) {
+		return nil, err
+	}
+
+	if account.Currency == "" {
+		account.Currency = DefaultCurrency
+	}
+	if !account.Currency.Valid() {
+		return nil, ErrUnknownCurrency
+	}
 
-	account.Balance = 0.0
-	account.Status = "Active"
-	account.CreatedAt = time.Now()
-	account.UpdatedAt = time.Now()
+	account.Balance = Zero(account.Currency)
+	if account.Status == "" {
+		account.Status = StatusActive
+	}
+	if account.Status != StatusActive && account.Status != StatusPendingKYC {
+		return nil, fmt.Errorf("%w: cannot open an account as %s", ErrInvalidTransition, account.Status)
+	}
+	account.CreatedAt = ac.clock.Now()
+	account.UpdatedAt = account.CreatedAt
+	account.StatusChangedAt = account.CreatedAt
 
-	ac.accounts[account.AccountNumber] = account
+	if err := ac.repo.Save(account); err != nil {
+		return nil, err
+	}
 	return &account, nil
 }
 
-func (ac *accountService) Deposit(accountNumber string, amount float64) error {
-	if amount <= 0 {
+func (ac *accountService) Deposit(accountNumber string, amount Money) error {
+	if !amount.IsPositive() {
 		return ErrInvalidAmount
 	}
 
-	account, exists := ac.accounts[accountNumber]
-	if !exists {
-		return ErrAccountNotFound
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
 	}
 
-	if account.Status != "Active" {
-		return fmt.Errorf("cannot deposit to an account with status: %s", account.Status)
+	if account.Status == StatusClosed {
+		return fmt.Errorf("cannot deposit to a closed account: %w", ErrAccountNotActive)
 	}
 
-	account.Balance += amount
-	account.UpdatedAt = time.Now()
-	ac.accounts[accountNumber] = account
+	balance, err := account.Balance.Add(amount)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	account.Balance = balance
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
 }
 
-func (ac *accountService) Withdraw(accountNumber string, amount float64) error {
-	if amount <= 0 {
+func (ac *accountService) Withdraw(accountNumber string, amount Money) error {
+	return ac.withdraw(accountNumber, amount, true)
+}
+
+func (ac *accountService) Debit(accountNumber string, amount Money) error {
+	return ac.withdraw(accountNumber, amount, false)
+}
+
+func (ac *accountService) withdraw(accountNumber string, amount Money, withinLimit bool) error {
+	if !amount.IsPositive() {
 		return ErrInvalidAmount
 	}
 
-	account, exists := ac.accounts[accountNumber]
-	if !exists {
-		return ErrAccountNotFound
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
 	}
 
-	if account.Status != "Active" {
-		return fmt.Errorf("cannot withdraw from an account with status: %s", account.Status)
+	if account.Status == StatusClosed {
+		return fmt.Errorf("cannot withdraw from a closed account: %w", ErrAccountNotActive)
 	}
 
-	if account.Balance < amount {
-		return ErrInsufficientFunds
+	balance, err := account.Balance.Sub(amount)
+	if err != nil {
+		return err
 	}
 
-	account.Balance -= amount
-	account.UpdatedAt = time.Now()
-	ac.accounts[accountNumber] = account
+	if withinLimit {
+		available, err := account.AvailableBalance()
+		if err != nil {
+			return err
+		}
+		if c, err := amount.Cmp(available); err != nil {
+			return err
+		} else if c > 0 {
+			return ErrInsufficientFunds
+		}
+	}
 
-	return nil
+	account.Balance = balance
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
 }
 
-func (ac *accountService) GetBalance(accountNumber string) (float64, error) {
-	account, exists := ac.accounts[accountNumber]
-	if !exists {
-		return 0, ErrAccountNotFound
+func (ac *accountService) GetBalance(accountNumber string) (Money, error) {
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return Money{}, err
 	}
 
 	return account.Balance, nil
 }
 
 func (ac *accountService) GetAccountDetails(accountNumber string) (*Account, error) {
-	account, exists := ac.accounts[accountNumber]
-	if !exists {
-		return nil, ErrAccountNotFound
+	return ac.repo.Get(accountNumber)
+}
+
+func (ac *accountService) ChangeStatus(accountNumber string, status AccountStatus, reason, actor string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w: a reason is required to change an account's status", ErrInvalidInput)
 	}
 
-	return &account, nil
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	if !account.Status.CanBecome(status) {
+		return fmt.Errorf("%w: account %s cannot go from %s to %s", ErrInvalidTransition, accountNumber, account.Status, status)
+	}
+	if status == StatusClosed && !account.Balance.IsZero() {
+		return ErrNonZeroBalance
+	}
+
+	account.Status = status
+	account.StatusReason = reason
+	account.StatusChangedBy = actor
+	account.StatusChangedAt = ac.clock.Now()
+	account.UpdatedAt = account.StatusChangedAt
+	return ac.repo.Save(*account)
+}
+
+func (ac *accountService) MarkInterestCredited(accountNumber string, until time.Time) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	account.InterestCreditedUntil = until
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
+}
+
+func (ac *accountService) MarkFeesCharged(accountNumber string, until time.Time) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	account.FeesChargedUntil = until
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
+}
+
+func (ac *accountService) SetOverdraftLimit(accountNumber string, limit Money) error {
+	if limit.IsNegative() {
+		return ErrInvalidAmount
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	if account.AccountType != "Current" && limit.IsPositive() {
+		return ErrOverdraftNotAllowed
+	}
+	if c := limit.Currency(); c != "" && c != account.Currency {
+		return fmt.Errorf("%w: %s limit on a %s account", ErrCurrencyMismatch, c, account.Currency)
+	}
+
+	account.OverdraftLimit = NewMoney(limit.MinorUnits(), account.Currency)
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
 }
 
-func (ac *accountService) CloseAccount(accountNumber string) error {
-	account, exists := ac.accounts[accountNumber]
-	if !exists {
-		return ErrAccountNotFound
+func (ac *accountService) AvailableBalance(accountNumber string) (Money, error) {
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return Money{}, err
 	}
+	return account.AvailableBalance()
+}
 
-	if account.Balance != 0 {
-		return errors.New("account balance must be zero to close the account")
+// AvailableBalance is the balance plus any unused overdraft, less the money
+// held for pending payments. It is negative if the account is overdrawn
+// beyond its limit.
+func (a Account) AvailableBalance() (Money, error) {
+	available, err := a.Balance.Add(a.OverdraftLimit)
+	if err != nil {
+		return Money{}, err
+	}
+	if a.HeldAmount.IsZero() {
+		return available, nil
 	}
+	return available.Sub(a.HeldAmount)
+}
 
-	account.Status = "Closed"
-	account.UpdatedAt = time.Now()
-	ac.accounts[accountNumber] = account
+func (ac *accountService) Hold(accountNumber string, amount Money) error {
+	if !amount.IsPositive() {
+		return ErrInvalidAmount
+	}
 
-	return nil
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	available, err := account.AvailableBalance()
+	if err != nil {
+		return err
+	}
+	if c, err := amount.Cmp(available); err != nil {
+		return err
+	} else if c > 0 {
+		return ErrInsufficientFunds
+	}
+
+	if account.HeldAmount, err = amount.Add(account.HeldAmount); err != nil {
+		return err
+	}
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
+}
+
+func (ac *accountService) ReleaseHold(accountNumber string, amount Money) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	account, err := ac.repo.Get(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	held, err := account.HeldAmount.Sub(amount)
+	if err != nil {
+		return err
+	}
+	if held.IsNegative() {
+		return fmt.Errorf("%w: cannot release %s of %s held", ErrInvalidAmount, amount, account.HeldAmount)
+	}
+
+	account.HeldAmount = held
+	account.UpdatedAt = ac.clock.Now()
+	return ac.repo.Save(*account)
+}
+
+func (ac *accountService) List() ([]Account, error) {
+	return ac.repo.List()
 }
 
 func (a Account) DisplayAccountInfo() {
 	fmt.Println("=== Account Information ===")
 	fmt.Printf("Account Number: %s\n", a.AccountNumber)
 	fmt.Printf("Holder Name: %s\n", a.HolderName)
-	fmt.Printf("Balance: $%.2f\n", a.Balance)
+	fmt.Printf("Balance: %s\n", a.Balance)
+	if a.OverdraftLimit.IsPositive() {
+		fmt.Printf("Overdraft Limit: %s\n", a.OverdraftLimit)
+	}
+	if a.HeldAmount.IsPositive() {
+		fmt.Printf("Held: %s\n", a.HeldAmount)
+	}
 	fmt.Printf("Type: %s\n", a.AccountType)
 	fmt.Printf("Status: %s\n", a.Status)
+	if a.StatusReason != "" {
+		fmt.Printf("Status Reason: %s (by %s on %s)\n", a.StatusReason, a.StatusChangedBy, a.StatusChangedAt.Format("2006-01-02 15:04:05"))
+	}
 	fmt.Printf("Created: %s\n", a.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Updated: %s\n", a.UpdatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Println("---------------------------")
-}
\ No newline at end of file
+}
@@ -0,0 +1,265 @@
+package bank
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrAuditTampered = errors.New("audit trail has been tampered with")
+
+// Audit actions.
+const (
+	AuditCreateUser          = "create_user"
+	AuditUpdateUser          = "update_user"
+	AuditDeleteUser          = "delete_user"
+	AuditChangePassword      = "change_password"
+	AuditCreateAccount       = "create_account"
+	AuditDeposit             = "deposit"
+	AuditWithdraw            = "withdraw"
+	AuditTransfer            = "transfer"
+	AuditCloseAccount        = "close_account"
+	AuditCreditInterest      = "credit_interest"
+	AuditChargeFee           = "charge_fee"
+	AuditSetOverdraft        = "set_overdraft_limit"
+	AuditChangeAccountStatus = "change_account_status"
+	AuditReverseTransaction  = "reverse_transaction"
+	AuditPlaceHold           = "place_hold"
+	AuditCaptureHold         = "capture_hold"
+	AuditReleaseHold         = "release_hold"
+	AuditExpireHold          = "expire_hold"
+	AuditCreateInstruction   = "create_standing_instruction"
+	AuditPauseInstruction    = "pause_standing_instruction"
+	AuditResumeInstruction   = "resume_standing_instruction"
+	AuditCancelInstruction   = "cancel_standing_instruction"
+)
+
+// AuditEntry records one change to the bank: who made it, when, and the
+// affected records before and after. Entries form a hash chain: each
+// entry's Hash covers its contents and the previous entry's Hash, so editing
+// or removing an entry breaks every later link.
+type AuditEntry struct {
+	Sequence  int64
+	Timestamp time.Time
+	// Actor is "user:<id>" for a logged-in user and "system" for trusted
+	// callers acting without a principal.
+	Actor    string
+	Action   string
+	Resource string
+	Before   json.RawMessage `json:",omitempty"`
+	After    json.RawMessage `json:",omitempty"`
+	PrevHash string
+	Hash     string
+}
+
+// computeHash hashes every field but Hash itself. The timestamp is
+// formatted in UTC so that stores may hand it back in any time zone.
+func (e AuditEntry) computeHash() string {
+	content, _ := json.Marshal(struct {
+		Sequence  int64
+		Timestamp string
+		Actor     string
+		Action    string
+		Resource  string
+		Before    string
+		After     string
+		PrevHash  string
+	}{
+		e.Sequence, e.Timestamp.UTC().Format(time.RFC3339Nano), e.Actor, e.Action, e.Resource,
+		string(e.Before), string(e.After), e.PrevHash,
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditTrail checks that entries, in sequence order, form an unbroken
+// hash chain starting at sequence 1. It reports the first entry that was
+// edited, removed or inserted. Removing entries from the end of the trail
+// leaves a valid chain; compare the last Hash with a copy kept elsewhere to
+// detect that.
+func VerifyAuditTrail(entries []AuditEntry) error {
+	prevHash := ""
+	for i, e := range entries {
+		if want := int64(i + 1); e.Sequence != want {
+			return fmt.Errorf("%w: expected entry %d, found %d", ErrAuditTampered, want, e.Sequence)
+		}
+		if e.PrevHash != prevHash {
+			return fmt.Errorf("%w: entry %d does not follow entry %d", ErrAuditTampered, e.Sequence, e.Sequence-1)
+		}
+		if e.Hash != e.computeHash() {
+			return fmt.Errorf("%w: entry %d was modified", ErrAuditTampered, e.Sequence)
+		}
+		prevHash = e.Hash
+	}
+	return nil
+}
+
+// auditLog collects the audit entries of one unit of work. They are chained
+// and appended when the unit of work finishes; see BankingSystem.update.
+type auditLog struct {
+	actor   string
+	clock   Clock
+	pending []AuditEntry
+}
+
+func (l *auditLog) record(action, resource string, before, after any) {
+	l.pending = append(l.pending, AuditEntry{
+		Actor:    l.actor,
+		Action:   action,
+		Resource: resource,
+		Before:   auditJSON(before),
+		After:    auditJSON(after),
+	})
+}
+
+// auditJSON encodes a record for the trail. Users are masked and stripped
+// of credentials, so the trail never holds what the store encrypts.
+func auditJSON(v any) json.RawMessage {
+	switch r := v.(type) {
+	case nil:
+		return nil
+	case *User:
+		if r == nil {
+			return nil
+		}
+		v = auditUser(*r)
+	case User:
+		v = auditUser(r)
+	case *Account:
+		if r == nil {
+			return nil
+		}
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage(strconv.Quote(err.Error()))
+	}
+	return raw
+}
+
+func auditUser(u User) User {
+	u = u.Masked()
+	u.Password = ""
+	u.PasswordHash = ""
+	u.DataKey = ""
+	return u
+}
+
+// flush chains the pending entries onto the trail. The caller must hold
+// bs.auditMu until the unit of work commits, so that no other unit of work
+// can append between reading the head and committing.
+func (l *auditLog) flush(repo AuditRepository) error {
+	head, err := repo.Last()
+	if err != nil {
+		return err
+	}
+
+	var seq int64
+	var prevHash string
+	if head != nil {
+		seq, prevHash = head.Sequence, head.Hash
+	}
+
+	now := l.clock.Now().UTC()
+	for _, e := range l.pending {
+		seq++
+		e.Sequence = seq
+		e.Timestamp = now
+		e.PrevHash = prevHash
+		e.Hash = e.computeHash()
+		if err := repo.Append(e); err != nil {
+			return err
+		}
+		prevHash = e.Hash
+	}
+	return nil
+}
+
+func (bs *BankingSystem) actor() string {
+	if bs.principal == nil {
+		return "system"
+	}
+	return "user:" + strconv.Itoa(bs.principal.UserID)
+}
+
+// auditedUserService records every change made through UserService in the
+// unit of work's audit log.
+type auditedUserService struct {
+	UserService
+	audit *auditLog
+}
+
+func (us auditedUserService) Create(user User) (*User, error) {
+	created, err := us.UserService.Create(user)
+	if err != nil {
+		return nil, err
+	}
+	us.audit.record(AuditCreateUser, "user:"+strconv.Itoa(created.ID), nil, created)
+	return created, nil
+}
+
+func (us auditedUserService) Update(user User) error {
+	before, err := us.UserService.Get(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := us.UserService.Update(user); err != nil {
+		return err
+	}
+	after, err := us.UserService.Get(user.ID)
+	if err != nil {
+		return err
+	}
+	us.audit.record(AuditUpdateUser, "user:"+strconv.Itoa(user.ID), before, after)
+	return nil
+}
+
+func (us auditedUserService) Delete(id int) error {
+	before, err := us.UserService.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := us.UserService.Delete(id); err != nil {
+		return err
+	}
+	us.audit.record(AuditDeleteUser, "user:"+strconv.Itoa(id), before, nil)
+	return nil
+}
+
+func (us auditedUserService) ChangePassword(userID int, currentPassword, newPassword string) error {
+	if err := us.UserService.ChangePassword(userID, currentPassword, newPassword); err != nil {
+		return err
+	}
+	us.audit.record(AuditChangePassword, "user:"+strconv.Itoa(userID), nil, nil)
+	return nil
+}
+
+// ListAuditEntries returns the whole audit trail, oldest first.
+func (bs *BankingSystem) ListAuditEntries() ([]AuditEntry, error) {
+	if err := bs.authorize(ActionViewAudit, bankResource); err != nil {
+		return nil, err
+	}
+	return bs.store.Audit().List()
+}
+
+// VerifyAuditLog checks the stored audit trail with VerifyAuditTrail and
+// returns its last entry, whose Hash can be kept elsewhere to detect later
+// truncation. The last entry is nil for an empty trail.
+func (bs *BankingSystem) VerifyAuditLog() (*AuditEntry, error) {
+	entries, err := bs.ListAuditEntries()
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyAuditTrail(entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[len(entries)-1], nil
+}
@@ -3,24 +3,204 @@ package bank
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-type BankingSystem struct {
+// services bundles the domain services bound to one Store: either the
+// system's store itself or a unit of work inside it.
+type services struct {
 	users        UserService
 	accounts     AccountService
 	transactions TransactionService
+	ledger       Ledger
+	instructions StandingInstructionRepository
+	idempotency  IdempotencyRepository
+	audit        *auditLog
+	clock        Clock
+	feeSchedules map[string]FeeSchedule
+	// notifications are delivered once the unit of work commits.
+	notifications []Notification
+}
+
+func (bs *BankingSystem) newServices(store Store) *services {
+	audit := &auditLog{actor: bs.actor(), clock: bs.clock}
+	users := auditedUserService{
+		UserService: NewUserService(newEncryptedUserRepository(store.Users(), bs.keys), bs.passwords, bs.passwordPolicy),
+		audit:       audit,
+	}
+	accounts := NewAccountService(store.Accounts(), bs.clock)
+
+	return &services{
+		users:        users,
+		accounts:     accounts,
+		transactions: NewTransactionService(store.Transactions(), accounts, users, bs.clock, bs.ids),
+		ledger:       NewLedger(store.Journal(), bs.clock),
+		instructions: store.StandingInstructions(),
+		idempotency:  store.Idempotency(),
+		audit:        audit,
+		clock:        bs.clock,
+		feeSchedules: bs.feeSchedules,
+	}
+}
+
+// BankingSystem is safe for concurrent use. Operations that change state run
+// as a single unit of work against the store and hold the locks of every
+// account involved for their whole duration.
+type BankingSystem struct {
+	*services
+
+	store          Store
+	accountLocks   *lockTable
+	passwords      *PasswordHasher
+	passwordPolicy PasswordPolicy
+	sessionTTL     time.Duration
+	sessions       *sessionStore
+	policy         Policy
+	keys           KeyProvider
+	clock          Clock
+	ids            IDGenerator
+	// interestProducts and feeSchedules map account types to the interest
+	// they earn and the fees they pay.
+	interestProducts map[string]InterestProduct
+	feeSchedules     map[string]FeeSchedule
+	notifier         Notifier
+	holdDuration     time.Duration
+	// initialStatus is the status accounts open in.
+	initialStatus AccountStatus
+	// idempotencyRetention is how long idempotency keys are remembered.
+	idempotencyRetention time.Duration
+	// auditMu serializes appends to the audit trail's hash chain.
+	auditMu *sync.Mutex
+
+	// principal is who this view of the system acts for; nil means
+	// unrestricted. See As.
+	principal *Principal
+	// idempotencyKey, if set, makes this view's money movements happen at
+	// most once. See Idempotent.
+	idempotencyKey string
+}
+
+type Option func(*BankingSystem)
+
+// WithStore selects where the banking system keeps its data. The default is
+// an in-memory store.
+func WithStore(store Store) Option {
+	return func(bs *BankingSystem) {
+		bs.store = store
+	}
+}
+
+// WithPasswordParams sets the argon2id cost of new password hashes.
+// Existing hashes are upgraded as their owners log in.
+func WithPasswordParams(params PasswordParams) Option {
+	return func(bs *BankingSystem) {
+		bs.passwords = NewPasswordHasher(params)
+	}
+}
+
+// WithKeyProvider turns on encryption of users' PII at rest, using keys
+// from keys. Users stored earlier in plaintext are encrypted when they are
+// next saved or by RotateEncryptionKey.
+func WithKeyProvider(keys KeyProvider) Option {
+	return func(bs *BankingSystem) {
+		bs.keys = keys
+	}
 }
 
-func NewBankingSystem() *BankingSystem {
+func WithPasswordPolicy(policy PasswordPolicy) Option {
+	return func(bs *BankingSystem) {
+		bs.passwordPolicy = policy
+	}
+}
+
+func NewBankingSystem(opts ...Option) *BankingSystem {
 	bankingSystem := &BankingSystem{
-		users:    NewUserService(),
-		accounts: NewAccountService(),
+		accountLocks:         newLockTable(),
+		passwords:            NewPasswordHasher(DefaultPasswordParams),
+		passwordPolicy:       DefaultPasswordPolicy,
+		sessionTTL:           DefaultSessionTTL,
+		policy:               DefaultPolicy,
+		clock:                SystemClock,
+		interestProducts:     DefaultInterestProducts,
+		feeSchedules:         DefaultFeeSchedules,
+		holdDuration:         DefaultHoldDuration,
+		initialStatus:        StatusActive,
+		idempotencyRetention: DefaultIdempotencyRetention,
+		auditMu:              &sync.Mutex{},
+	}
+
+	for _, opt := range opts {
+		opt(bankingSystem)
+	}
+
+	if bankingSystem.store == nil {
+		bankingSystem.store = NewMemoryStore()
+	}
+	if bankingSystem.ids == nil {
+		bankingSystem.ids = &snowflakeGenerator{}
 	}
 
-	bankingSystem.transactions = NewTransactionService(bankingSystem)
+	bankingSystem.sessions = newSessionStore(bankingSystem.sessionTTL, bankingSystem.clock)
+	bankingSystem.services = bankingSystem.newServices(bankingSystem.store)
 	return bankingSystem
 }
 
+// Close releases the underlying store.
+func (bs *BankingSystem) Close() error {
+	return bs.store.Close()
+}
+
+// update runs fn with services bound to a single unit of work, so that all of
+// its writes are committed together or not at all. The audit entries fn
+// records are appended to the trail in the same unit of work, and its
+// notifications are delivered once the unit of work has committed.
+func (bs *BankingSystem) update(fn func(s *services) error) error {
+	chaining := false
+	defer func() {
+		if chaining {
+			bs.auditMu.Unlock()
+		}
+	}()
+
+	var notifications []Notification
+	err := bs.store.Update(func(tx Store) error {
+		s := bs.newServices(tx)
+		if err := fn(s); err != nil {
+			return err
+		}
+		notifications = s.notifications
+		if len(s.audit.pending) == 0 {
+			return nil
+		}
+
+		// Hold the chain until the unit of work has committed.
+		bs.auditMu.Lock()
+		chaining = true
+		return s.audit.flush(tx.Audit())
+	})
+	if err != nil {
+		return err
+	}
+
+	if bs.notifier != nil {
+		for _, n := range notifications {
+			bs.notifier.Notify(n)
+		}
+	}
+	return nil
+}
+
+// ErrSameAccount is returned for a transfer whose source and destination are
+// the same account.
+var ErrSameAccount = errors.New("cannot transfer to the same account")
+
+func userLockKey(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
 func (bs *BankingSystem) CreateUser(firstName, lastName, email, password, address, phone, panCard, aadharCard string) (*User, error) {
 	user := User{
 		FirstName:        firstName,
@@ -33,139 +213,496 @@ func (bs *BankingSystem) CreateUser(firstName, lastName, email, password, addres
 		AadharCardNumber: aadharCard,
 	}
 
-	createdUser, err := bs.users.Create(user)
+	unlock := bs.accountLocks.lock("email:" + email)
+	defer unlock()
+
+	var createdUser *User
+	err := bs.update(func(s *services) error {
+		var err error
+		createdUser, err = s.users.Create(user)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("User created successfully: %s %s (ID: %d)\n", firstName, lastName, createdUser.ID)
-	return createdUser, nil
+	return bs.revealUser(createdUser)
 }
 
-func (bs *BankingSystem) CreateAccount(accountNumber, holderName, accountType string, userID int) error {
-	// Verify user exists
-	user, err := bs.users.Get(userID)
+// VerifyPassword checks a user's credentials and returns the user. It
+// returns ErrInvalidCredentials for an unknown email or a wrong password.
+func (bs *BankingSystem) VerifyPassword(email, password string) (*User, error) {
+	// Take the user's lock too: an outdated hash is rewritten, and that
+	// must not race with other changes to the user.
+	keys := []string{"email:" + email}
+	if existing, err := bs.users.GetByEmail(email); err == nil {
+		keys = append(keys, userLockKey(existing.ID))
+	}
+	unlock := bs.accountLocks.lock(keys...)
+	defer unlock()
+
+	var user *User
+	err := bs.update(func(s *services) error {
+		var err error
+		user, err = s.users.VerifyPassword(email, password)
+		return err
+	})
 	if err != nil {
-		return errors.New("user not found")
+		return nil, err
+	}
+	return user, nil
+}
+
+func (bs *BankingSystem) ChangePassword(userID int, currentPassword, newPassword string) error {
+	if err := bs.authorize(ActionChangePassword, userResource(userID)); err != nil {
+		return err
+	}
+
+	unlock := bs.accountLocks.lock(userLockKey(userID))
+	defer unlock()
+
+	return bs.update(func(s *services) error {
+		return s.users.ChangePassword(userID, currentPassword, newPassword)
+	})
+}
+
+// CreateAccount opens an account for a user. It opens Active unless the
+// system was set up WithInitialAccountStatus(StatusPendingKYC), in which
+// case it takes deposits only until the holder's identity has been verified
+// and a branch manager makes it Active with ChangeAccountStatus.
+func (bs *BankingSystem) CreateAccount(accountNumber, holderName, accountType string, userID int) (*Account, error) {
+	if err := bs.authorize(ActionOpenAccount, userResource(userID)); err != nil {
+		return nil, err
+	}
+
+	// Verify user exists
+	if _, err := bs.users.Get(userID); err != nil {
+		return nil, err
 	}
 
 	account := Account{
 		AccountNumber: accountNumber,
 		HolderName:    holderName,
 		AccountType:   accountType,
+		Status:        bs.initialStatus,
 	}
 
-	createdAccount, err := bs.accounts.Create(account)
+	unlock := bs.accountLocks.lock(accountNumber, userLockKey(userID))
+	defer unlock()
+
+	var createdAccount *Account
+	err := bs.update(func(s *services) error {
+		var err error
+		createdAccount, err = s.accounts.Create(account)
+		if err != nil {
+			return err
+		}
+
+		// Link account to user
+		if err := s.users.AddAccountToUser(userID, accountNumber); err != nil {
+			return err
+		}
+
+		s.audit.record(AuditCreateAccount, "account:"+accountNumber, nil, createdAccount)
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Link account to user
-	err = bs.users.AddAccountToUser(userID, accountNumber)
-	if err != nil {
+	return createdAccount, nil
+}
+
+func (bs *BankingSystem) Deposit(accountNumber string, amount Money) (*Transaction, error) {
+	if err := bs.authorize(ActionDeposit, accountResource(accountNumber)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber, bs.idempotencyLock())
+	defer unlock()
+
+	var transaction *Transaction
+	err := bs.update(func(s *services) error {
+		var err error
+		transaction, err = bs.once(s, idempotentRequest(Deposit, "", accountNumber, amount), func() (*Transaction, error) {
+			return s.move(Deposit, "", accountNumber, amount, "Cash deposit", depositLines(accountNumber, amount))
+		})
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Account created successfully for %s (User ID: %d)\n", holderName, userID)
-	user.DisplayUserInfo()
-	createdAccount.DisplayAccountInfo()
-	return nil
+	return transaction, nil
 }
 
-func (bs *BankingSystem) Deposit(accountNumber string, amount float64) error {
-	err := bs.accounts.Deposit(accountNumber, amount)
-	if err != nil {
+func (bs *BankingSystem) Withdraw(accountNumber string, amount Money) (*Transaction, error) {
+	if err := bs.authorize(ActionWithdraw, accountResource(accountNumber)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber, bs.idempotencyLock())
+	defer unlock()
+
+	var transaction *Transaction
+	err := bs.update(func(s *services) error {
+		var err error
+		transaction, err = bs.once(s, idempotentRequest(Withdrawal, accountNumber, "", amount), func() (*Transaction, error) {
+			return s.move(Withdrawal, accountNumber, "", amount, "Cash withdrawal", withdrawalLines(accountNumber, amount))
+		})
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Record transaction
-	_, err = bs.transactions.CreateTransaction(Deposit, "", accountNumber, amount, "Cash deposit")
+	return transaction, nil
+}
+
+// Transfer moves money between two accounts. The withdrawal, the deposit, the
+// transaction record and the journal entry are committed as one unit of work.
+// Customers may transfer from their own accounts to any account.
+func (bs *BankingSystem) Transfer(fromAccount, toAccount string, amount Money) (*Transaction, error) {
+	return bs.transfer(fromAccount, toAccount, amount, "Fund transfer", nil)
+}
+
+// transfer is Transfer with a description. If then is set, it runs in the
+// same unit of work once the money has moved, and the transfer is undone if
+// it fails.
+func (bs *BankingSystem) transfer(fromAccount, toAccount string, amount Money, description string, then func(s *services, t *Transaction) error) (*Transaction, error) {
+	if fromAccount == toAccount {
+		return nil, ErrSameAccount
+	}
+	if err := bs.authorize(ActionTransfer, accountResource(fromAccount)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(fromAccount, toAccount, bs.idempotencyLock())
+	defer unlock()
+
+	var transaction *Transaction
+	err := bs.update(func(s *services) error {
+		var err error
+		transaction, err = bs.once(s, idempotentRequest(Transfer, fromAccount, toAccount, amount), func() (*Transaction, error) {
+			transaction, err := s.move(Transfer, fromAccount, toAccount, amount, description, transferLines(fromAccount, toAccount, amount))
+			if err != nil || then == nil {
+				return transaction, err
+			}
+			return transaction, then(s, transaction)
+		})
+		return err
+	})
 	if err != nil {
-		fmt.Printf("Warning: Failed to record deposit transaction: %v\n", err)
+		return nil, err
 	}
 
-	fmt.Printf("Deposited $%.2f to account %s\n", amount, accountNumber)
-	return nil
+	return transaction, nil
 }
 
-func (bs *BankingSystem) Withdraw(accountNumber string, amount float64) error {
-	err := bs.accounts.Withdraw(accountNumber, amount)
+// move applies a money movement to the account balances, records the
+// transaction and posts the matching journal entry, then charges any fee
+// the paying account's schedule sets for it. Either account may be empty
+// for cash movements, and each account's status must allow its side of the
+// movement. Money held for pending payments cannot be moved, but holds that
+// have lapsed are released first. It must run inside a unit of work, so a
+// failure at any step discards the earlier ones, and the caller must hold
+// the locks of both accounts.
+func (s *services) move(tType TransactionType, fromAccount, toAccount string, amount Money, description string, lines []JournalLine) (*Transaction, error) {
+	if fromAccount != "" {
+		if err := s.checkOperation(fromAccount, debitOperations[tType]); err != nil {
+			return nil, err
+		}
+		if err := s.expireHolds(fromAccount); err != nil {
+			return nil, err
+		}
+	}
+	if toAccount != "" {
+		if err := s.checkOperation(toAccount, creditOperations[tType]); err != nil {
+			return nil, err
+		}
+	}
+
+	payer := fromAccount
+	if payer == "" {
+		payer = toAccount
+	}
+	fee, err := s.transactionFee(tType, payer, amount)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Record transaction
-	_, err = bs.transactions.CreateTransaction(Withdrawal, accountNumber, "", amount, "Cash withdrawal")
+	transaction, err := s.post(tType, fromAccount, toAccount, amount, description, lines)
 	if err != nil {
-		fmt.Printf("Warning: Failed to record withdrawal transaction: %v\n", err)
+		return nil, err
 	}
 
-	fmt.Printf("Withdrew $%.2f from account %s\n", amount, accountNumber)
-	return nil
+	if fee.IsPositive() {
+		feeTransaction, err := s.chargeFee(payer, fee, fmt.Sprintf("%s fee for %s", strings.ToLower(string(tType)), transaction.ID), transaction.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to charge %s fee: %w", tType, err)
+		}
+		transaction.Fee = feeTransaction.Amount
+	}
+	return transaction, nil
+}
+
+// post is move without the fee.
+func (s *services) post(tType TransactionType, fromAccount, toAccount string, amount Money, description string, lines []JournalLine) (*Transaction, error) {
+	return s.postAt(time.Time{}, tType, fromAccount, toAccount, amount, description, lines)
 }
 
-func (bs *BankingSystem) Transfer(fromAccount, toAccount string, amount float64) error {
-	// Withdraw from source account
-	err := bs.accounts.Withdraw(fromAccount, amount)
+// postAt is post for a transaction that took effect at an earlier time, or
+// now if at is zero.
+func (s *services) postAt(at time.Time, tType TransactionType, fromAccount, toAccount string, amount Money, description string, lines []JournalLine) (*Transaction, error) {
+	before, err := s.balances(fromAccount, toAccount)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if fromAccount != "" {
+		// Interest is charged whatever the overdraft limit.
+		withdraw := s.accounts.Withdraw
+		if tType == Interest {
+			withdraw = s.accounts.Debit
+		}
+		if err := withdraw(fromAccount, amount); err != nil {
+			return nil, err
+		}
+		if err := s.checkOverdraft(fromAccount); err != nil {
+			return nil, err
+		}
+	}
+
+	if toAccount != "" {
+		if err := s.accounts.Deposit(toAccount, amount); err != nil {
+			return nil, err
+		}
 	}
 
-	// Deposit to destination account
-	err = bs.accounts.Deposit(toAccount, amount)
+	transaction, err := s.transactions.CreateTransactionAt(tType, fromAccount, toAccount, amount, description, at)
 	if err != nil {
-		// Rollback the withdrawal if deposit fails
-		bs.accounts.Deposit(fromAccount, amount)
-		return err
+		return nil, fmt.Errorf("failed to record %s transaction: %w", tType, err)
 	}
 
-	// Record transaction
-	_, err = bs.transactions.CreateTransaction(Transfer, fromAccount, toAccount, amount, "Fund transfer")
+	_, err = s.ledger.Post(JournalEntry{
+		TransactionID: transaction.ID,
+		Timestamp:     transaction.Timestamp,
+		Description:   description,
+		Lines:         lines,
+	})
 	if err != nil {
-		fmt.Printf("Warning: Failed to record transfer transaction: %v\n", err)
+		return nil, fmt.Errorf("failed to post %s to ledger: %w", tType, err)
 	}
 
-	fmt.Printf("Transferred $%.2f from %s to %s\n", amount, fromAccount, toAccount)
-	return nil
+	after, err := s.balances(fromAccount, toAccount)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.record(auditActions[tType], "transaction:"+transaction.ID, before, after)
+
+	return transaction, nil
+}
+
+var auditActions = map[TransactionType]string{
+	Deposit:    AuditDeposit,
+	Withdrawal: AuditWithdraw,
+	Transfer:   AuditTransfer,
+	Interest:   AuditCreditInterest,
+	Fee:        AuditChargeFee,
+	Reversal:   AuditReverseTransaction,
+}
+
+// balances returns the balances of the named accounts, skipping empty names.
+func (s *services) balances(accountNumbers ...string) (map[string]Money, error) {
+	balances := make(map[string]Money, len(accountNumbers))
+	for _, accountNumber := range accountNumbers {
+		if accountNumber == "" {
+			continue
+		}
+		balance, err := s.accounts.GetBalance(accountNumber)
+		if err != nil {
+			return nil, err
+		}
+		balances[accountNumber] = balance
+	}
+	return balances, nil
 }
 
 func (bs *BankingSystem) GetAccount(accountNumber string) (*Account, error) {
+	if err := bs.authorize(ActionViewAccount, accountResource(accountNumber)); err != nil {
+		return nil, err
+	}
 	return bs.accounts.GetAccountDetails(accountNumber)
 }
 
 func (bs *BankingSystem) GetUser(userID int) (*User, error) {
-	return bs.users.Get(userID)
+	if err := bs.authorize(ActionViewUser, userResource(userID)); err != nil {
+		return nil, err
+	}
+
+	user, err := bs.users.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	return bs.revealUser(user)
 }
 
 func (bs *BankingSystem) GetUserByEmail(email string) (*User, error) {
-	return bs.users.GetByEmail(email)
+	user, err := bs.users.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.authorize(ActionViewUser, userResource(user.ID)); err != nil {
+		return nil, err
+	}
+	return bs.revealUser(user)
 }
 
-func (bs *BankingSystem) GetBalance(accountNumber string) (float64, error) {
+func (bs *BankingSystem) GetBalance(accountNumber string) (Money, error) {
+	if err := bs.authorize(ActionViewAccount, accountResource(accountNumber)); err != nil {
+		return Money{}, err
+	}
 	return bs.accounts.GetBalance(accountNumber)
 }
 
+// ListAccounts returns every account the caller may see, ordered by account
+// number.
+func (bs *BankingSystem) ListAccounts() ([]Account, error) {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := bs.accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []Account{}
+	for _, account := range accounts {
+		if allowed(ActionViewAccount, accountResource(account.AccountNumber)) == nil {
+			visible = append(visible, account)
+		}
+	}
+	return visible, nil
+}
+
+// ListUsers returns every user, ordered by ID. Only admins may list users.
+func (bs *BankingSystem) ListUsers() ([]User, error) {
+	if err := bs.authorize(ActionListUsers, bankResource); err != nil {
+		return nil, err
+	}
+
+	users, err := bs.users.List()
+	if err != nil {
+		return nil, err
+	}
+	return bs.revealPII(users...)
+}
+
+// ListTransactions returns every transaction the caller may see, oldest
+// first.
+func (bs *BankingSystem) ListTransactions() ([]*Transaction, error) {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := bs.transactions.GetAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+	visible := []*Transaction{}
+	for _, transaction := range transactions {
+		if allowed(ActionViewTransaction, transactionResource(transaction)) == nil {
+			visible = append(visible, transaction)
+		}
+	}
+	return visible, nil
+}
+
+// ListUserAccounts returns the accounts owned by a user in the order they
+// were opened.
+func (bs *BankingSystem) ListUserAccounts(userID int) ([]Account, error) {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return nil, err
+	}
+	if err := allowed(ActionViewUser, userResource(userID)); err != nil {
+		return nil, err
+	}
+
+	user, err := bs.users.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]Account, 0, len(user.Accounts))
+	for _, accountNumber := range user.Accounts {
+		if err := allowed(ActionViewAccount, accountResource(accountNumber)); err != nil {
+			return nil, err
+		}
+		account, err := bs.accounts.GetAccountDetails(accountNumber)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, nil
+}
+
+// GetTransaction returns a transaction if the caller may see one of the
+// accounts it touches.
+func (bs *BankingSystem) GetTransaction(transactionID string) (*Transaction, error) {
+	transaction, err := bs.transactions.GetTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bs.authorize(ActionViewTransaction, transactionResource(transaction)); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// ListAccountTransactions returns the transactions that touch an account,
+// oldest first. An account without transactions yields an empty slice.
+func (bs *BankingSystem) ListAccountTransactions(accountNumber string) ([]*Transaction, error) {
+	if _, err := bs.GetAccount(accountNumber); err != nil {
+		return nil, err
+	}
+
+	return bs.transactions.GetTransactionsByAccount(accountNumber)
+}
+
+func (bs *BankingSystem) GetAccountSummary(accountNumber string) (*TransactionSummary, error) {
+	if _, err := bs.GetAccount(accountNumber); err != nil {
+		return nil, err
+	}
+	return bs.transactions.GetTransactionSummary(accountNumber)
+}
+
 func (bs *BankingSystem) ListAllAccounts() {
 	fmt.Println("\n=== All Accounts ===")
-	users, _ := bs.users.List()
-	accountFound := false
-	for _, user := range users {
-		for _, accountNumber := range user.Accounts {
-			account, err := bs.accounts.GetAccountDetails(accountNumber)
-			if err == nil {
-				account.DisplayAccountInfo()
-				accountFound = true
-			}
-		}
+	accounts, err := bs.ListAccounts()
+	if err != nil {
+		fmt.Printf("Error listing accounts: %v\n", err)
+		return
 	}
-	if !accountFound {
+
+	if len(accounts) == 0 {
 		fmt.Println("No accounts found.")
+		return
+	}
+
+	for _, account := range accounts {
+		account.DisplayAccountInfo()
 	}
 }
 
 func (bs *BankingSystem) ListAllUsers() {
 	fmt.Println("\n=== All Users ===")
-	users, err := bs.users.List()
+	users, err := bs.ListUsers()
 	if err != nil {
 		fmt.Printf("Error listing users: %v\n", err)
 		return
@@ -183,12 +720,16 @@ func (bs *BankingSystem) ListAllUsers() {
 
 func (bs *BankingSystem) GetTransactionHistory() {
 	fmt.Println("\n=== Transaction History ===")
-	transactions := bs.transactions.GetAllTransactions()
+	transactions, err := bs.ListTransactions()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
 	DisplayTransactions(transactions, "All Transactions")
 }
 
 func (bs *BankingSystem) GetUserAccounts(userID int) {
-	user, err := bs.users.Get(userID)
+	user, err := bs.GetUser(userID)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
@@ -199,7 +740,7 @@ func (bs *BankingSystem) GetUserAccounts(userID int) {
 		fmt.Println("No accounts found for this user.")
 		return
 	}
-	
+
 	for _, accountNumber := range user.Accounts {
 		account, err := bs.accounts.GetAccountDetails(accountNumber)
 		if err == nil {
@@ -209,7 +750,7 @@ func (bs *BankingSystem) GetUserAccounts(userID int) {
 }
 
 func (bs *BankingSystem) GetAccountTransactions(accountNumber string) {
-	transactions, err := bs.transactions.GetTransactionsByAccount(accountNumber)
+	transactions, err := bs.ListAccountTransactions(accountNumber)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
@@ -218,10 +759,78 @@ func (bs *BankingSystem) GetAccountTransactions(accountNumber string) {
 }
 
 func (bs *BankingSystem) GetTransactionSummary(accountNumber string) {
-	summary := bs.transactions.GetTransactionSummary(accountNumber)
+	summary, err := bs.GetAccountSummary(accountNumber)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
 	summary.DisplayTransactionSummary()
 }
 
 func (bs *BankingSystem) CloseAccount(accountNumber string) error {
-	return bs.accounts.CloseAccount(accountNumber)
-}
\ No newline at end of file
+	if err := bs.authorize(ActionCloseAccount, accountResource(accountNumber)); err != nil {
+		return err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	return bs.update(func(s *services) error {
+		return s.changeStatus(accountNumber, StatusClosed, "Closed at the holder's request", AuditCloseAccount)
+	})
+}
+
+func (bs *BankingSystem) GetTrialBalance() (*TrialBalance, error) {
+	if err := bs.authorize(ActionViewLedger, bankResource); err != nil {
+		return nil, err
+	}
+	return bs.ledger.TrialBalance()
+}
+
+// LedgerDiscrepancy describes an account whose stored balance disagrees with
+// the balance derived from the journal.
+type LedgerDiscrepancy struct {
+	AccountNumber string
+	Balance       Money
+	LedgerBalance Money
+}
+
+// ReconcileLedger checks every account's balance against the ledger and
+// returns the accounts that do not match.
+func (bs *BankingSystem) ReconcileLedger() ([]LedgerDiscrepancy, error) {
+	if err := bs.authorize(ActionViewLedger, bankResource); err != nil {
+		return nil, err
+	}
+
+	accounts, err := bs.accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var discrepancies []LedgerDiscrepancy
+	for _, listed := range accounts {
+		// Read the balance again under the lock, so it cannot move between
+		// reading it and reading the ledger.
+		unlock := bs.accountLocks.lock(listed.AccountNumber)
+		account, err := bs.accounts.GetAccountDetails(listed.AccountNumber)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		ledgerBalance, err := bs.ledger.GetBalance(account.AccountNumber)
+		unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		if !account.Balance.Equal(ledgerBalance) {
+			discrepancies = append(discrepancies, LedgerDiscrepancy{
+				AccountNumber: account.AccountNumber,
+				Balance:       account.Balance,
+				LedgerBalance: ledgerBalance,
+			})
+		}
+	}
+
+	return discrepancies, nil
+}
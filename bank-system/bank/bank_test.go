@@ -0,0 +1,2218 @@
+package bank_test
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bank-system/bank"
+	"bank-system/bank/sqlitestore"
+)
+
+func inr(s string) bank.Money {
+	return bank.MustParseMoney(s, bank.INR)
+}
+
+// forEachStore runs test once on a MemoryStore and once on a SQLite store,
+// each in its own subtest.
+func forEachStore(t *testing.T, test func(t *testing.T, store bank.Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, bank.NewMemoryStore())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		store, err := sqlitestore.Open(filepath.Join(t.TempDir(), "bank.db"))
+		if err != nil {
+			t.Fatalf("sqlitestore.Open: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		test(t, store)
+	})
+}
+
+// newTestBank returns a banking system with n funded accounts named ACC000,
+// ACC001, ... each holding the given opening balance.
+func newTestBank(t testing.TB, n int, opening bank.Money, opts ...bank.Option) (*bank.BankingSystem, []string) {
+	t.Helper()
+
+	bs := bank.NewBankingSystem(append([]bank.Option{bank.WithFeeSchedules(nil)}, opts...)...)
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	accounts := make([]string, n)
+	for i := range accounts {
+		accounts[i] = fmt.Sprintf("ACC%03d", i)
+		openAccount(t, bs, accounts[i], "Savings", user.ID)
+		if opening.IsPositive() {
+			if _, err := bs.Deposit(accounts[i], opening); err != nil {
+				t.Fatalf("Deposit(%s): %v", accounts[i], err)
+			}
+		}
+	}
+	return bs, accounts
+}
+
+// openAccount opens an account and makes sure it is active, as a branch
+// manager would once the holder's identity has been verified.
+func openAccount(t testing.TB, bs *bank.BankingSystem, accountNumber, accountType string, userID int) {
+	t.Helper()
+	account, err := bs.CreateAccount(accountNumber, "Test User", accountType, userID)
+	if err != nil {
+		t.Fatalf("CreateAccount(%s): %v", accountNumber, err)
+	}
+	if account.Status == bank.StatusActive {
+		return
+	}
+	if err := bs.ChangeAccountStatus(accountNumber, bank.StatusActive, "KYC verified"); err != nil {
+		t.Fatalf("ChangeAccountStatus(%s): %v", accountNumber, err)
+	}
+}
+
+func totalBalance(t testing.TB, bs *bank.BankingSystem, accounts []string) bank.Money {
+	t.Helper()
+
+	total := bank.Zero(bank.INR)
+	for _, acc := range accounts {
+		balance, err := bs.GetBalance(acc)
+		if err != nil {
+			t.Fatalf("GetBalance(%s): %v", acc, err)
+		}
+		if balance.IsNegative() {
+			t.Errorf("account %s has negative balance %s", acc, balance)
+		}
+		if total, err = total.Add(balance); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	return total
+}
+
+func assertLedgerConsistent(t testing.TB, bs *bank.BankingSystem) {
+	t.Helper()
+
+	tb, err := bs.GetTrialBalance()
+	if err != nil {
+		t.Fatalf("GetTrialBalance: %v", err)
+	}
+	if !tb.IsBalanced() {
+		t.Errorf("trial balance does not net to zero: debits %s, credits %s", tb.TotalDebits, tb.TotalCredits)
+	}
+
+	discrepancies, err := bs.ReconcileLedger()
+	if err != nil {
+		t.Fatalf("ReconcileLedger: %v", err)
+	}
+	for _, d := range discrepancies {
+		t.Errorf("account %s: balance %s, ledger %s", d.AccountNumber, d.Balance, d.LedgerBalance)
+	}
+
+	if _, err := bs.VerifyAuditLog(); err != nil {
+		t.Errorf("VerifyAuditLog: %v", err)
+	}
+}
+
+func TestReconcileLedger(t *testing.T) {
+	store := bank.NewMemoryStore()
+	bs, accounts := newTestBank(t, 2, inr("100"), bank.WithStore(store))
+	assertLedgerConsistent(t, bs)
+
+	// Tamper with a balance behind the ledger's back, and add an account
+	// that no user lists.
+	tampered, err := store.Accounts().Get(accounts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered.Balance = inr("150")
+	if err := store.Accounts().Save(*tampered); err != nil {
+		t.Fatal(err)
+	}
+	orphan := *tampered
+	orphan.AccountNumber, orphan.Balance = "ORPHAN", inr("10")
+	if err := store.Accounts().Save(orphan); err != nil {
+		t.Fatal(err)
+	}
+
+	discrepancies, err := bs.ReconcileLedger()
+	if err != nil {
+		t.Fatalf("ReconcileLedger: %v", err)
+	}
+	want := map[string][2]string{accounts[1]: {"150", "100"}, "ORPHAN": {"10", "0"}}
+	if len(discrepancies) != len(want) {
+		t.Fatalf("ReconcileLedger = %+v, want discrepancies in %v", discrepancies, want)
+	}
+	for _, d := range discrepancies {
+		balances, ok := want[d.AccountNumber]
+		if !ok || !d.Balance.Equal(inr(balances[0])) || !d.LedgerBalance.Equal(inr(balances[1])) {
+			t.Errorf("discrepancy %+v, want balance and ledger %v", d, balances)
+		}
+	}
+}
+
+func TestJournalListByAccount(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		bs, accounts := newTestBank(t, 3, inr("100"), bank.WithStore(store))
+		if _, err := bs.Transfer(accounts[0], accounts[1], inr("30")); err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+
+		// descriptions lists the entries of an account, oldest first.
+		descriptions := func(journal bank.JournalRepository, account string) []string {
+			t.Helper()
+			entries, err := journal.ListByAccount(account)
+			if err != nil {
+				t.Fatalf("ListByAccount(%s): %v", account, err)
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Description)
+			}
+			return got
+		}
+		if got := descriptions(store.Journal(), accounts[1]); len(got) != 2 || got[0] != "Cash deposit" {
+			t.Errorf("entries of %s = %q, want its deposit then the transfer", accounts[1], got)
+		}
+		if got := descriptions(store.Journal(), accounts[2]); len(got) != 1 {
+			t.Errorf("entries of %s = %q, want only its deposit", accounts[2], got)
+		}
+		if got := descriptions(store.Journal(), "NONE"); len(got) != 0 {
+			t.Errorf("entries of an unknown account = %q, want none", got)
+		}
+
+		// An entry not yet committed is listed within its unit of work.
+		err := store.Update(func(tx bank.Store) error {
+			err := tx.Journal().Append(bank.JournalEntry{ID: "JE-STAGED", Sequence: 1 << 40, Timestamp: date(2026, time.January, 1),
+				Description: "staged", Lines: []bank.JournalLine{
+					{Account: bank.GLCashInVault, Side: bank.Debit, Amount: inr("1")},
+					{Account: accounts[2], Side: bank.Credit, Amount: inr("1")},
+				}})
+			if err != nil {
+				return err
+			}
+			if got := descriptions(tx.Journal(), accounts[2]); len(got) != 2 || got[1] != "staged" {
+				t.Errorf("entries of %s inside Update = %q, want its deposit then the staged entry", accounts[2], got)
+			}
+			return errors.New("roll back")
+		})
+		if err == nil {
+			t.Fatal("Update did not roll back")
+		}
+		if got := descriptions(store.Journal(), accounts[2]); len(got) != 1 {
+			t.Errorf("entries of %s after rolling back = %q, want only its deposit", accounts[2], got)
+		}
+	})
+}
+
+func TestConcurrentTransfersConserveBalance(t *testing.T) {
+	const (
+		numAccounts = 8
+		workers     = 16
+		perWorker   = 200
+	)
+
+	bs, accounts := newTestBank(t, numAccounts, inr("1000.00"))
+	want := totalBalance(t, bs, accounts)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(w), 42))
+			for range perWorker {
+				from := accounts[rng.IntN(numAccounts)]
+				to := accounts[rng.IntN(numAccounts)]
+				if from == to {
+					continue
+				}
+				amount := bank.NewMoney(int64(rng.IntN(50_000)+1), bank.INR)
+				_, err := bs.Transfer(from, to, amount)
+				if err != nil && !errors.Is(err, bank.ErrInsufficientFunds) {
+					t.Errorf("Transfer(%s, %s, %s): %v", from, to, amount, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := totalBalance(t, bs, accounts); !got.Equal(want) {
+		t.Fatalf("total balance = %s, want %s", got, want)
+	}
+	assertLedgerConsistent(t, bs)
+}
+
+func TestOpposingTransfersDoNotDeadlock(t *testing.T) {
+	bs, accounts := newTestBank(t, 2, inr("100.00"))
+	a, b := accounts[0], accounts[1]
+
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			from, to := a, b
+			if i == 1 {
+				from, to = b, a
+			}
+			for range 500 {
+				_, err := bs.Transfer(from, to, inr("1.00"))
+				if err != nil && !errors.Is(err, bank.ErrInsufficientFunds) {
+					t.Errorf("Transfer(%s, %s): %v", from, to, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := totalBalance(t, bs, accounts), inr("200.00"); !got.Equal(want) {
+		t.Fatalf("total balance = %s, want %s", got, want)
+	}
+}
+
+func TestConcurrentDepositsAndWithdrawals(t *testing.T) {
+	const workers = 20
+
+	bs, accounts := newTestBank(t, 1, inr("500.00"))
+	acc := accounts[0]
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := bs.Deposit(acc, inr("2.00")); err != nil {
+					t.Errorf("Deposit: %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := bs.Withdraw(acc, inr("1.00")); err != nil {
+					t.Errorf("Withdraw: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 500 + 20*50*2 - 20*50*1
+	if got, want := totalBalance(t, bs, accounts), inr("1500.00"); !got.Equal(want) {
+		t.Fatalf("balance = %s, want %s", got, want)
+	}
+	assertLedgerConsistent(t, bs)
+}
+
+func TestConcurrentUserAndAccountCreation(t *testing.T) {
+	bs := bank.NewBankingSystem()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := bs.CreateUser("User", fmt.Sprint(i), fmt.Sprintf("user%d@example.com", i), "S3cure!Passw0rd",
+				"Patna, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+			if err != nil {
+				t.Errorf("CreateUser: %v", err)
+				return
+			}
+			for j := range 3 {
+				if _, err := bs.CreateAccount(fmt.Sprintf("U%02dA%d", i, j), "User", "Savings", user.ID); err != nil {
+					t.Errorf("CreateAccount: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 1; i <= 20; i++ {
+		user, err := bs.GetUser(i)
+		if err != nil {
+			t.Fatalf("GetUser(%d): %v", i, err)
+		}
+		if len(user.Accounts) != 3 {
+			t.Errorf("user %d has %d accounts, want 3", i, len(user.Accounts))
+		}
+	}
+}
+
+func TestPIIEncryptedAtRestAndRotated(t *testing.T) {
+	keys, err := bank.OpenKeyFile(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatalf("OpenKeyFile: %v", err)
+	}
+	store := bank.NewMemoryStore()
+	bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithKeyProvider(keys))
+
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	stored, err := store.Users().Get(user.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	for _, value := range []string{stored.Address, stored.Phone, stored.PanCardNumber, stored.AadharCardNumber} {
+		if !strings.HasPrefix(value, "enc:") {
+			t.Errorf("stored value %q is not encrypted", value)
+		}
+	}
+	if strings.Contains(stored.String(), "9876543210") {
+		t.Errorf("String() = %q, want the phone number masked", stored.String())
+	}
+
+	if n, err := bs.RotateEncryptionKey(); err != nil || n != 1 {
+		t.Fatalf("RotateEncryptionKey = %d, %v, want 1 user re-encrypted", n, err)
+	}
+	rotated, err := store.Users().Get(user.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !strings.HasPrefix(rotated.DataKey, keys.CurrentKeyID()+":") || rotated.Phone == stored.Phone {
+		t.Errorf("user not re-encrypted under key %s: data key %q", keys.CurrentKeyID(), rotated.DataKey)
+	}
+
+	got, err := bs.GetUser(user.ID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.AadharCardNumber != "234123412346" {
+		t.Errorf("Aadhaar = %q after rotation, want 234123412346", got.AadharCardNumber)
+	}
+	if masked := got.Masked(); masked.AadharCardNumber != "XXXX-XXXX-2346" || masked.PanCardNumber != "XXXXXX234F" {
+		t.Errorf("Masked() = %s, %s", masked.AadharCardNumber, masked.PanCardNumber)
+	}
+}
+
+func TestAuditTrailDetectsTampering(t *testing.T) {
+	bs, accounts := newTestBank(t, 2, inr("100.00"))
+	if _, err := bs.Transfer(accounts[0], accounts[1], inr("25.00")); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if _, err := bs.Withdraw(accounts[1], inr("125.00")); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	if err := bs.CloseAccount(accounts[1]); err != nil {
+		t.Fatalf("CloseAccount: %v", err)
+	}
+
+	entries, err := bs.ListAuditEntries()
+	if err != nil {
+		t.Fatalf("ListAuditEntries: %v", err)
+	}
+	var actions []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	want := []string{
+		"create_user",
+		"create_account", "deposit",
+		"create_account", "deposit",
+		"transfer", "withdraw", "close_account",
+	}
+	if !slices.Equal(actions, want) {
+		t.Fatalf("audit actions = %v, want %v", actions, want)
+	}
+	if err := bank.VerifyAuditTrail(entries); err != nil {
+		t.Fatalf("VerifyAuditTrail on untouched trail: %v", err)
+	}
+
+	edited := slices.Clone(entries)
+	edited[5].After = []byte(`{"ACC000":{"amount":"1000.00","currency":"INR"}}`)
+	removed := slices.Delete(slices.Clone(entries), 3, 4)
+	rehashed := slices.Clone(entries)
+	rehashed[6].Actor = "user:99"
+	rehashed[6].Hash = rehashed[7].PrevHash
+
+	for name, tampered := range map[string][]bank.AuditEntry{"edited": edited, "removed": removed, "rehashed": rehashed} {
+		if err := bank.VerifyAuditTrail(tampered); !errors.Is(err, bank.ErrAuditTampered) {
+			t.Errorf("%s entry: VerifyAuditTrail = %v, want ErrAuditTampered", name, err)
+		}
+	}
+}
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAccrueInterest(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.January, 1).Add(10 * time.Hour)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithInterestProducts(map[string]bank.InterestProduct{
+			"Savings": bank.DefaultInterestProducts["Savings"],
+			"Tiered": {
+				Method:    bank.SimpleInterest,
+				Crediting: bank.CreditMonthly,
+				Tiers: []bank.InterestTier{
+					{From: inr("0"), Rate: bank.MustParsePercent("3.65")},
+					{From: inr("500.00"), Rate: bank.MustParsePercent("7.30%")},
+				},
+			},
+			"Compound": {
+				Method:    bank.CompoundInterest,
+				Crediting: bank.CreditMonthly,
+				Tiers:     []bank.InterestTier{{From: inr("0"), Rate: bank.MustParsePercent("36.5")}},
+			},
+		}))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		opening := map[string]bank.Money{"Savings": inr("100000.00"), "Tiered": inr("1000.00"), "Compound": inr("1000.00")}
+		for accountType, amount := range opening {
+			openAccount(t, bs, accountType, accountType, user.ID)
+			if _, err := bs.Deposit(accountType, amount); err != nil {
+				t.Fatalf("Deposit(%s): %v", accountType, err)
+			}
+		}
+
+		clock.Set(date(2026, time.February, 1).Add(time.Hour))
+		run, err := bs.AccrueInterest(date(2026, time.February, 1))
+		if err != nil {
+			t.Fatalf("AccrueInterest(Feb 1): %v", err)
+		}
+		credited := map[string]bank.Money{}
+		for _, transaction := range run.Credits {
+			if transaction.Type != bank.Interest {
+				t.Errorf("credit %s has type %s", transaction.ID, transaction.Type)
+			}
+			credited[transaction.ToAccount] = transaction.Amount
+		}
+		// 31 days at 15 paise a day: 3.65% on the first 500 and 7.30% on the
+		// rest. A rupee a day compounding daily comes to 31.47 rather than 31.
+		wantCredited := map[string]bank.Money{"Tiered": inr("4.65"), "Compound": inr("31.47")}
+		if !maps.EqualFunc(credited, wantCredited, bank.Money.Equal) {
+			t.Errorf("credits through Feb 1 = %v, want %v", credited, wantCredited)
+		}
+		// The quarter has not ended, so savings interest is only accrued:
+		// 100000 * 2.7% * 31/365.
+		if got := run.Accrued["Savings"]; !got.Equal(inr("229.32")) {
+			t.Errorf("accrued on Savings = %s, want 229.32", got)
+		}
+
+		clock.Set(date(2026, time.April, 2))
+		for range 2 {
+			if _, err := bs.AccrueInterest(date(2026, time.April, 1)); err != nil {
+				t.Fatalf("AccrueInterest(Apr 1): %v", err)
+			}
+		}
+		transactions, err := bs.ListAccountTransactions("Savings")
+		if err != nil {
+			t.Fatalf("ListAccountTransactions: %v", err)
+		}
+		var interest []bank.Money
+		for _, transaction := range transactions {
+			if transaction.Type == bank.Interest {
+				interest = append(interest, transaction.Amount)
+			}
+		}
+		// 100000 * 2.7% * 90/365, posted once despite the second run.
+		if want := []bank.Money{inr("665.75")}; !slices.EqualFunc(interest, want, bank.Money.Equal) {
+			t.Errorf("interest on Savings = %v, want %v", interest, want)
+		}
+
+		if _, err := bs.AccrueInterest(date(2026, time.April, 3)); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("AccrueInterest in the future = %v, want ErrInvalidInput", err)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestAccrueInterestCatchesUp(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.January, 1).Add(10 * time.Hour)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil), bank.WithInterestProducts(map[string]bank.InterestProduct{
+			"Savings": {
+				Method:    bank.SimpleInterest,
+				Crediting: bank.CreditMonthly,
+				Tiers:     []bank.InterestTier{{From: inr("0"), Rate: bank.MustParsePercent("36.5")}},
+			},
+			"Current": {
+				Method:        bank.SimpleInterest,
+				Crediting:     bank.CreditMonthly,
+				OverdraftRate: bank.MustParsePercent("36.5"),
+			},
+		}))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		for _, accountType := range []string{"Savings", "Current"} {
+			openAccount(t, bs, accountType, accountType, user.ID)
+		}
+		if _, err := bs.Deposit("Savings", inr("1000")); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+		if err := bs.SetOverdraftLimit("Current", inr("2000")); err != nil {
+			t.Fatalf("SetOverdraftLimit: %v", err)
+		}
+		if _, err := bs.Withdraw("Current", inr("1000")); err != nil {
+			t.Fatalf("Withdraw: %v", err)
+		}
+
+		// Nothing runs until April, when one run posts the whole quarter.
+		clock.Set(date(2026, time.April, 1).Add(10 * time.Hour))
+		run, err := bs.AccrueInterest(date(2026, time.April, 1))
+		if err != nil {
+			t.Fatalf("AccrueInterest: %v", err)
+		}
+
+		// Each month is posted as of its end, and earns or costs interest
+		// in the months after: a rupee a day on 1000, then on 1031.00 for
+		// February's 28 days and on 1059.87 for March's 31.
+		want := []string{
+			"2026-02-01 00:00:00 ₹31.00",
+			"2026-03-01 00:00:00 ₹28.87",
+			"2026-04-01 00:00:00 ₹32.86",
+		}
+		for name, transactions := range map[string][]*bank.Transaction{"credits": run.Credits, "charges": run.Charges} {
+			var got []string
+			for _, transaction := range transactions {
+				got = append(got, transaction.Timestamp.UTC().Format(time.DateTime)+" "+transaction.Amount.String())
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("%s = %q, want %q", name, got, want)
+			}
+		}
+
+		for account, want := range map[string]bank.Money{"Savings": inr("1092.73"), "Current": inr("-1092.73")} {
+			if balance, err := bs.GetBalance(account); err != nil || !balance.Equal(want) {
+				t.Errorf("balance of %s = %s, %v; want %s", account, balance, err, want)
+			}
+		}
+
+		// The February statement shows January's interest, not the run.
+		statement, err := bs.MonthlyStatement("Savings", 2026, time.February)
+		if err != nil {
+			t.Fatalf("MonthlyStatement: %v", err)
+		}
+		if !statement.OpeningBalance.Equal(inr("1000")) || !statement.ClosingBalance.Equal(inr("1031")) {
+			t.Errorf("February statement runs from %s to %s, want 1000.00 to 1031.00", statement.OpeningBalance, statement.ClosingBalance)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestFees(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.January, 5).Add(10 * time.Hour)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(map[string]bank.FeeSchedule{
+			"Savings": {
+				Transactions: map[bank.TransactionType]bank.TransactionFee{
+					bank.Withdrawal: {FreePerMonth: 2, Flat: inr("10"), Rate: bank.MustParsePercent("1")},
+				},
+				MinimumBalance:        inr("1000"),
+				MinimumBalancePenalty: inr("50"),
+			},
+			"Current": {Maintenance: inr("100")},
+		}))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		for _, account := range []struct {
+			number, accountType string
+			opening             bank.Money
+		}{
+			{"SAV", "Savings", inr("2000")},
+			{"LOW", "Savings", inr("500")},
+			{"CUR", "Current", inr("50")},
+		} {
+			openAccount(t, bs, account.number, account.accountType, user.ID)
+			if _, err := bs.Deposit(account.number, account.opening); err != nil {
+				t.Fatalf("Deposit(%s): %v", account.number, err)
+			}
+		}
+
+		var withdrawal *bank.Transaction
+		for range 3 {
+			if withdrawal, err = bs.Withdraw("SAV", inr("100")); err != nil {
+				t.Fatalf("Withdraw: %v", err)
+			}
+		}
+		// Two free withdrawals, then 10 plus 1% of 100.
+		if !withdrawal.Fee.Equal(inr("11")) {
+			t.Errorf("fee on third withdrawal = %s, want 11.00", withdrawal.Fee)
+		}
+		transactions, err := bs.ListAccountTransactions("SAV")
+		if err != nil {
+			t.Fatalf("ListAccountTransactions: %v", err)
+		}
+		last := transactions[len(transactions)-1]
+		if last.Type != bank.Fee || last.LinkedTransactionID != withdrawal.ID || !last.Amount.Equal(inr("11")) {
+			t.Errorf("last transaction = %s %s linked to %q, want an 11.00 fee linked to %s",
+				last.Type, last.Amount, last.LinkedTransactionID, withdrawal.ID)
+		}
+
+		// The fee on withdrawing the whole balance cannot be paid, so nothing
+		// is withdrawn.
+		if _, err := bs.Withdraw("SAV", inr("1689")); !errors.Is(err, bank.ErrInsufficientFunds) {
+			t.Errorf("Withdraw(whole balance) = %v, want ErrInsufficientFunds", err)
+		}
+		if balance, _ := bs.GetBalance("SAV"); !balance.Equal(inr("1689")) {
+			t.Errorf("balance after failed withdrawal = %s, want 1689.00", balance)
+		}
+
+		clock.Set(date(2026, time.March, 2))
+		charged, err := bs.ChargeMonthlyFees(date(2026, time.March, 1))
+		if err != nil {
+			t.Fatalf("ChargeMonthlyFees: %v", err)
+		}
+		var got []string
+		for _, fee := range charged {
+			got = append(got, fee.FromAccount+" "+fee.Amount.Amount())
+		}
+		// LOW misses the minimum balance in January and February. CUR owes 100
+		// for January but only has 50, and nothing for February.
+		if want := []string{"CUR 50.00", "LOW 50.00", "LOW 50.00"}; !slices.Equal(got, want) {
+			t.Errorf("monthly fees = %v, want %v", got, want)
+		}
+
+		if charged, err := bs.ChargeMonthlyFees(date(2026, time.March, 1)); err != nil || len(charged) != 0 {
+			t.Errorf("second ChargeMonthlyFees = %d fees, %v; want none", len(charged), err)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+// failingStore is a Store whose transaction and journal queries fail while
+// the matching failure is set.
+type failingStore struct {
+	bank.Store
+	fail *failures
+}
+
+// failures says which queries of a failingStore fail.
+type failures struct {
+	transactions, journal atomic.Bool
+}
+
+var errQueryFailed = errors.New("query failed")
+
+func (s failingStore) Transactions() bank.TransactionRepository {
+	return failingTransactions{s.Store.Transactions(), s.fail}
+}
+
+func (s failingStore) Journal() bank.JournalRepository {
+	return failingJournal{s.Store.Journal(), s.fail}
+}
+
+func (s failingStore) Update(fn func(tx bank.Store) error) error {
+	return s.Store.Update(func(tx bank.Store) error {
+		return fn(failingStore{tx, s.fail})
+	})
+}
+
+type failingTransactions struct {
+	bank.TransactionRepository
+	fail *failures
+}
+
+func (r failingTransactions) Query(q bank.TransactionQuery) ([]*bank.Transaction, error) {
+	if r.fail.transactions.Load() {
+		return nil, errQueryFailed
+	}
+	return r.TransactionRepository.Query(q)
+}
+
+func (r failingTransactions) List() ([]*bank.Transaction, error) {
+	if r.fail.transactions.Load() {
+		return nil, errQueryFailed
+	}
+	return r.TransactionRepository.List()
+}
+
+// failingJournal fails the queries that read entries or totals back. Posting
+// still works.
+type failingJournal struct {
+	bank.JournalRepository
+	fail *failures
+}
+
+func (r failingJournal) List() ([]bank.JournalEntry, error) {
+	if r.fail.journal.Load() {
+		return nil, errQueryFailed
+	}
+	return r.JournalRepository.List()
+}
+
+func (r failingJournal) ListByAccount(account string) ([]bank.JournalEntry, error) {
+	if r.fail.journal.Load() {
+		return nil, errQueryFailed
+	}
+	return r.JournalRepository.ListByAccount(account)
+}
+
+func (r failingJournal) AllTotals() (map[string]bank.AccountTotals, error) {
+	if r.fail.journal.Load() {
+		return nil, errQueryFailed
+	}
+	return r.JournalRepository.AllTotals()
+}
+
+// newFailingBank returns a bank with two accounts holding 1000 each, whose
+// queries fail as fail says.
+func newFailingBank(t *testing.T, opts ...bank.Option) (bs *bank.BankingSystem, accounts []string, fail *failures) {
+	fail = new(failures)
+	bs, accounts = newTestBank(t, 2, inr("1000"), append(opts, bank.WithStore(failingStore{bank.NewMemoryStore(), fail}))...)
+	return bs, accounts, fail
+}
+
+func TestTransactionQueryFailures(t *testing.T) {
+	t.Run("listing and summary", func(t *testing.T) {
+		// An empty list or summary would pass for an account that has
+		// seen nothing.
+		bs, accounts, fail := newFailingBank(t)
+		fail.transactions.Store(true)
+		if transactions, err := bs.ListTransactions(); !errors.Is(err, errQueryFailed) {
+			t.Errorf("ListTransactions = %d transactions, %v; want the query error", len(transactions), err)
+		}
+		if summary, err := bs.GetAccountSummary(accounts[0]); !errors.Is(err, errQueryFailed) {
+			t.Errorf("GetAccountSummary = %+v, %v; want the query error", summary, err)
+		}
+	})
+
+	t.Run("transaction fee", func(t *testing.T) {
+		// Without the history the free allowance cannot be counted, so the
+		// transfer must not go through free.
+		bs, accounts, fail := newFailingBank(t, bank.WithFeeSchedules(bank.DefaultFeeSchedules))
+		fail.transactions.Store(true)
+		if _, err := bs.Transfer(accounts[0], accounts[1], inr("100")); !errors.Is(err, errQueryFailed) {
+			t.Errorf("Transfer = %v, want the query error", err)
+		}
+		if balance, _ := bs.GetBalance(accounts[0]); !balance.Equal(inr("1000")) {
+			t.Errorf("balance after the failed transfer = %s, want 1000.00", balance)
+		}
+	})
+
+	t.Run("reversal", func(t *testing.T) {
+		// Without the history any fee cannot be found to refund, so the
+		// reversal must not go ahead without it.
+		bs, accounts, fail := newFailingBank(t)
+		transfer, err := bs.Transfer(accounts[0], accounts[1], inr("100"))
+		if err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+		fail.transactions.Store(true)
+		if _, err := bs.ReverseTransaction(transfer.ID, "sent in error"); !errors.Is(err, errQueryFailed) {
+			t.Errorf("ReverseTransaction = %v, want the query error", err)
+		}
+		if balance, _ := bs.GetBalance(accounts[0]); !balance.Equal(inr("900")) {
+			t.Errorf("balance after the failed reversal = %s, want 900.00", balance)
+		}
+	})
+
+	t.Run("lapsed holds", func(t *testing.T) {
+		// Without the holds the available balance is unknown, and a payment
+		// cannot tell which held money has been freed.
+		bs, accounts, fail := newFailingBank(t)
+		if _, err := bs.PlaceHold(accounts[0], inr("100"), "Hotel booking"); err != nil {
+			t.Fatalf("PlaceHold: %v", err)
+		}
+		fail.transactions.Store(true)
+		if _, err := bs.GetAvailableBalance(accounts[0]); !errors.Is(err, errQueryFailed) {
+			t.Errorf("GetAvailableBalance = %v, want the query error", err)
+		}
+		if _, err := bs.Withdraw(accounts[0], inr("100")); !errors.Is(err, errQueryFailed) {
+			t.Errorf("Withdraw = %v, want the query error", err)
+		}
+		if expired, err := bs.ExpireHolds(); !errors.Is(err, errQueryFailed) {
+			t.Errorf("ExpireHolds = %d holds, %v; want the query error", len(expired), err)
+		}
+	})
+}
+
+func TestJournalQueryFailures(t *testing.T) {
+	t.Run("trial balance", func(t *testing.T) {
+		// A ledger that cannot be read is not a balanced one.
+		bs, _, fail := newFailingBank(t)
+		fail.journal.Store(true)
+		if tb, err := bs.GetTrialBalance(); !errors.Is(err, errQueryFailed) {
+			t.Errorf("GetTrialBalance = %+v, %v; want the query error", tb, err)
+		}
+	})
+
+	t.Run("monthly fees", func(t *testing.T) {
+		// Without the entries the average balance is unknown, so no penalty
+		// is charged and the month is left to charge again.
+		clock := &fakeClock{now: date(2026, time.January, 5)}
+		bs, accounts, fail := newFailingBank(t, bank.WithClock(clock), bank.WithFeeSchedules(map[string]bank.FeeSchedule{
+			"Savings": {MinimumBalance: inr("5000"), MinimumBalancePenalty: inr("50")},
+		}))
+		clock.Set(date(2026, time.February, 2))
+		fail.journal.Store(true)
+		if charged, err := bs.ChargeMonthlyFees(date(2026, time.February, 1)); !errors.Is(err, errQueryFailed) || len(charged) != 0 {
+			t.Errorf("ChargeMonthlyFees = %d fees, %v; want none and the query error", len(charged), err)
+		}
+		fail.journal.Store(false)
+		charged, err := bs.ChargeMonthlyFees(date(2026, time.February, 1))
+		if err != nil || len(charged) != len(accounts) {
+			t.Errorf("ChargeMonthlyFees after the failure = %d fees, %v; want one per account", len(charged), err)
+		}
+	})
+
+	t.Run("interest", func(t *testing.T) {
+		// Without the entries the balances are unknown, so nothing is
+		// credited and the month is left to credit again.
+		clock := &fakeClock{now: date(2026, time.January, 5)}
+		bs, accounts, fail := newFailingBank(t, bank.WithClock(clock), bank.WithInterestProducts(map[string]bank.InterestProduct{
+			"Savings": {
+				Method:    bank.SimpleInterest,
+				Crediting: bank.CreditMonthly,
+				Tiers:     []bank.InterestTier{{From: inr("0"), Rate: bank.MustParsePercent("36.5")}},
+			},
+		}))
+		clock.Set(date(2026, time.February, 2))
+		fail.journal.Store(true)
+		if run, err := bs.AccrueInterest(date(2026, time.February, 1)); !errors.Is(err, errQueryFailed) || len(run.Credits) != 0 {
+			t.Errorf("AccrueInterest = %+v, %v; want no credits and the query error", run, err)
+		}
+		fail.journal.Store(false)
+		run, err := bs.AccrueInterest(date(2026, time.February, 1))
+		if err != nil || len(run.Credits) != len(accounts) {
+			t.Fatalf("AccrueInterest after the failure = %+v, %v; want one credit per account", run, err)
+		}
+		// 27 days at 0.1% a day on 1000.
+		if !run.Credits[0].Amount.Equal(inr("27")) {
+			t.Errorf("interest credited = %s, want 27.00", run.Credits[0].Amount)
+		}
+	})
+
+	t.Run("reversal", func(t *testing.T) {
+		// A transaction whose entry cannot be read back may well be
+		// reversible, so the reversal reports the failure rather than
+		// refusing it.
+		bs, accounts, fail := newFailingBank(t)
+		transfer, err := bs.Transfer(accounts[0], accounts[1], inr("100"))
+		if err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+		fail.journal.Store(true)
+		if _, err := bs.ReverseTransaction(transfer.ID, "sent in error"); !errors.Is(err, errQueryFailed) || errors.Is(err, bank.ErrNotReversible) {
+			t.Errorf("ReverseTransaction = %v, want the query error", err)
+		}
+		fail.journal.Store(false)
+		if _, err := bs.ReverseTransaction(transfer.ID, "sent in error"); err != nil {
+			t.Errorf("ReverseTransaction after the failure: %v", err)
+		}
+	})
+
+	t.Run("statement", func(t *testing.T) {
+		bs, accounts, fail := newFailingBank(t)
+		fail.journal.Store(true)
+		now := time.Now()
+		if st, err := bs.GenerateStatement(accounts[0], now.AddDate(0, -1, 0), now); !errors.Is(err, errQueryFailed) {
+			t.Errorf("GenerateStatement = %+v, %v; want the query error", st, err)
+		}
+	})
+}
+
+func TestOverdraft(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.January, 1).Add(10 * time.Hour)}
+		var notifications []bank.Notification
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil),
+			bank.WithNotifier(bank.NotifierFunc(func(n bank.Notification) {
+				notifications = append(notifications, n)
+			})),
+			bank.WithInterestProducts(map[string]bank.InterestProduct{
+				"Current": {Crediting: bank.CreditMonthly, OverdraftRate: bank.MustParsePercent("36.5")},
+			}))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		for number, accountType := range map[string]string{"CUR": "Current", "SAV": "Savings"} {
+			openAccount(t, bs, number, accountType, user.ID)
+		}
+
+		if err := bs.SetOverdraftLimit("SAV", inr("1000")); !errors.Is(err, bank.ErrOverdraftNotAllowed) {
+			t.Errorf("SetOverdraftLimit(Savings) = %v, want ErrOverdraftNotAllowed", err)
+		}
+		if _, err := bs.Withdraw("CUR", inr("1")); !errors.Is(err, bank.ErrInsufficientFunds) {
+			t.Errorf("Withdraw without overdraft = %v, want ErrInsufficientFunds", err)
+		}
+
+		if err := bs.SetOverdraftLimit("CUR", inr("1000")); err != nil {
+			t.Fatalf("SetOverdraftLimit: %v", err)
+		}
+		if _, err := bs.Withdraw("CUR", inr("1000")); err != nil {
+			t.Fatalf("Withdraw to the limit: %v", err)
+		}
+		if _, err := bs.Withdraw("CUR", inr("0.01")); !errors.Is(err, bank.ErrInsufficientFunds) {
+			t.Errorf("Withdraw beyond the limit = %v, want ErrInsufficientFunds", err)
+		}
+		if len(notifications) != 0 {
+			t.Errorf("notifications within the limit: %v", notifications)
+		}
+
+		// A rupee a day on 1000 overdrawn for January, charged beyond the limit.
+		clock.Set(date(2026, time.February, 2))
+		run, err := bs.AccrueInterest(date(2026, time.February, 1))
+		if err != nil {
+			t.Fatalf("AccrueInterest: %v", err)
+		}
+		if len(run.Charges) != 1 || !run.Charges[0].Amount.Equal(inr("31")) {
+			t.Fatalf("overdraft interest charges = %v, want one of 31.00", run.Charges)
+		}
+		if balance, _ := bs.GetBalance("CUR"); !balance.Equal(inr("-1031")) {
+			t.Errorf("balance after overdraft interest = %s, want -1031.00", balance)
+		}
+		if len(notifications) != 1 || notifications[0].Kind != bank.NotifyOverdraftBreached || notifications[0].AccountNumber != "CUR" {
+			t.Errorf("notifications after interest = %v, want one breach of CUR", notifications)
+		}
+
+		if err := bs.SetOverdraftLimit("CUR", inr("500")); err != nil {
+			t.Fatalf("SetOverdraftLimit(lower): %v", err)
+		}
+		if len(notifications) != 2 {
+			t.Errorf("lowering the limit below the overdrawn amount sent %d notifications, want 2", len(notifications))
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestAccountsOpenActiveByDefault(t *testing.T) {
+	bs, _ := newTestBank(t, 0, inr("0"))
+	user, err := bs.GetUserByEmail("test.user@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	opened, err := bs.CreateAccount("NEW", "Test User", "Savings", user.ID)
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if opened.Status != bank.StatusActive {
+		t.Errorf("new account is %s, want Active", opened.Status)
+	}
+	if _, err := bs.Deposit("NEW", inr("50")); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	if _, err := bs.Withdraw("NEW", inr("10")); err != nil {
+		t.Errorf("Withdraw from a new account: %v", err)
+	}
+}
+
+func TestAccountLifecycle(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		bs, accounts := newTestBank(t, 2, inr("100"), bank.WithStore(store), bank.WithInitialAccountStatus(bank.StatusPendingKYC))
+		a, b := accounts[0], accounts[1]
+
+		// A bank that verifies identities after opening accounts has a new
+		// account take deposits only until KYC is done.
+		user, err := bs.GetUserByEmail("test.user@example.com")
+		if err != nil {
+			t.Fatalf("GetUserByEmail: %v", err)
+		}
+		opened, err := bs.CreateAccount("NEW", "Test User", "Savings", user.ID)
+		if err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+		if opened.Status != bank.StatusPendingKYC {
+			t.Errorf("new account is %s, want PendingKYC", opened.Status)
+		}
+		if _, err := bs.Deposit("NEW", inr("50")); err != nil {
+			t.Errorf("Deposit to a PendingKYC account: %v", err)
+		}
+		if _, err := bs.Withdraw("NEW", inr("10")); !errors.Is(err, bank.ErrAccountNotActive) {
+			t.Errorf("Withdraw from a PendingKYC account = %v, want ErrAccountNotActive", err)
+		}
+		if _, err := bs.Transfer("NEW", a, inr("10")); !errors.Is(err, bank.ErrAccountNotActive) {
+			t.Errorf("Transfer from a PendingKYC account = %v, want ErrAccountNotActive", err)
+		}
+		if _, err := bs.Transfer(a, "NEW", inr("10")); !errors.Is(err, bank.ErrAccountNotActive) {
+			t.Errorf("Transfer to a PendingKYC account = %v, want ErrAccountNotActive", err)
+		}
+		if err := bs.ChangeAccountStatus("NEW", bank.StatusFrozen, "Too early"); !errors.Is(err, bank.ErrInvalidTransition) {
+			t.Errorf("freezing a PendingKYC account = %v, want ErrInvalidTransition", err)
+		}
+		if err := bs.ChangeAccountStatus("NEW", bank.StatusActive, "KYC verified"); err != nil {
+			t.Fatalf("ChangeAccountStatus(Active): %v", err)
+		}
+		if _, err := bs.Withdraw("NEW", inr("50")); err != nil {
+			t.Errorf("Withdraw after KYC: %v", err)
+		}
+
+		if err := bs.FreezeAccount(a, ""); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("FreezeAccount without a reason = %v, want ErrInvalidInput", err)
+		}
+		if err := bs.FreezeAccount(a, "Suspected fraud"); err != nil {
+			t.Fatalf("FreezeAccount: %v", err)
+		}
+		if _, err := bs.Deposit(a, inr("10")); !errors.Is(err, bank.ErrAccountNotActive) {
+			t.Errorf("Deposit to a frozen account = %v, want ErrAccountNotActive", err)
+		}
+		if _, err := bs.Transfer(b, a, inr("10")); !errors.Is(err, bank.ErrAccountNotActive) {
+			t.Errorf("Transfer to a frozen account = %v, want ErrAccountNotActive", err)
+		}
+		if err := bs.CloseAccount(a); !errors.Is(err, bank.ErrInvalidTransition) {
+			t.Errorf("CloseAccount on a frozen account = %v, want ErrInvalidTransition", err)
+		}
+
+		account, err := bs.GetAccount(a)
+		if err != nil {
+			t.Fatalf("GetAccount: %v", err)
+		}
+		if account.Status != bank.StatusFrozen || account.StatusReason != "Suspected fraud" || account.StatusChangedBy != "system" {
+			t.Errorf("frozen account status = %s %q by %q", account.Status, account.StatusReason, account.StatusChangedBy)
+		}
+
+		// A debit freeze lets money in but not out.
+		if err := bs.ChangeAccountStatus(a, bank.StatusDebitFrozen, "Court order"); err != nil {
+			t.Fatalf("ChangeAccountStatus(DebitFrozen): %v", err)
+		}
+		if _, err := bs.Transfer(b, a, inr("10")); err != nil {
+			t.Errorf("Transfer to a debit-frozen account: %v", err)
+		}
+		if _, err := bs.Withdraw(a, inr("10")); !errors.Is(err, bank.ErrAccountNotActive) {
+			t.Errorf("Withdraw from a debit-frozen account = %v, want ErrAccountNotActive", err)
+		}
+
+		if err := bs.UnfreezeAccount(a, "Order lifted"); err != nil {
+			t.Fatalf("UnfreezeAccount: %v", err)
+		}
+		if err := bs.UnfreezeAccount(a, "Again"); !errors.Is(err, bank.ErrInvalidTransition) {
+			t.Errorf("UnfreezeAccount on an active account = %v, want ErrInvalidTransition", err)
+		}
+		if _, err := bs.Withdraw(a, inr("110")); err != nil {
+			t.Errorf("Withdraw after unfreezing: %v", err)
+		}
+		if err := bs.CloseAccount(a); err != nil {
+			t.Fatalf("CloseAccount: %v", err)
+		}
+		if err := bs.ChangeAccountStatus(a, bank.StatusActive, "Reopen"); !errors.Is(err, bank.ErrInvalidTransition) {
+			t.Errorf("reopening a closed account = %v, want ErrInvalidTransition", err)
+		}
+
+		entries, err := bs.ListAuditEntries()
+		if err != nil {
+			t.Fatalf("ListAuditEntries: %v", err)
+		}
+		changes := 0
+		for _, entry := range entries {
+			if entry.Action == bank.AuditChangeAccountStatus {
+				changes++
+			}
+		}
+		// Three accounts activated, then three changes to a.
+		if changes != 6 {
+			t.Errorf("audit trail has %d status changes, want 6", changes)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestReverseTransaction(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		bs, accounts := newTestBank(t, 2, inr("100"), bank.WithStore(store))
+		a, b := accounts[0], accounts[1]
+
+		transfer, err := bs.Transfer(a, b, inr("40"))
+		if err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+		if _, err := bs.ReverseTransaction(transfer.ID, ""); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("ReverseTransaction without a reason = %v, want ErrInvalidInput", err)
+		}
+
+		reversal, err := bs.ReverseTransaction(transfer.ID, "Wrong beneficiary")
+		if err != nil {
+			t.Fatalf("ReverseTransaction: %v", err)
+		}
+		if reversal.Type != bank.Reversal || reversal.FromAccount != b || reversal.ToAccount != a ||
+			reversal.ReferenceNumber != transfer.ReferenceNumber || reversal.LinkedTransactionID != transfer.ID {
+			t.Errorf("reversal = %+v, want %s to %s sharing reference %s", reversal, b, a, transfer.ReferenceNumber)
+		}
+		for _, account := range accounts {
+			if balance, _ := bs.GetBalance(account); !balance.Equal(inr("100")) {
+				t.Errorf("balance of %s after reversal = %s, want 100.00", account, balance)
+			}
+		}
+		if original, _ := bs.GetTransaction(transfer.ID); original.Status != bank.Reversed {
+			t.Errorf("original status = %s, want REVERSED", original.Status)
+		}
+
+		summary, err := bs.GetAccountSummary(a)
+		if err != nil {
+			t.Fatalf("GetAccountSummary: %v", err)
+		}
+		if !summary.TotalTransfersOut.IsZero() || !summary.TotalDeposits.Equal(inr("100")) {
+			t.Errorf("summary after reversal: transfers out %s, deposits %s; want 0.00 and 100.00",
+				summary.TotalTransfersOut, summary.TotalDeposits)
+		}
+
+		if _, err := bs.ReverseTransaction(transfer.ID, "Again"); !errors.Is(err, bank.ErrAlreadyReversed) {
+			t.Errorf("second reversal = %v, want ErrAlreadyReversed", err)
+		}
+		if _, err := bs.ReverseTransaction(reversal.ID, "Undo"); !errors.Is(err, bank.ErrNotReversible) {
+			t.Errorf("reversing a reversal = %v, want ErrNotReversible", err)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestReverseTransactionRefundsFee(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithFeeSchedules(map[string]bank.FeeSchedule{
+			"Savings": {Transactions: map[bank.TransactionType]bank.TransactionFee{
+				bank.Withdrawal: {Flat: inr("5")},
+			}},
+		}))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		openAccount(t, bs, "SAV", "Savings", user.ID)
+		if _, err := bs.Deposit("SAV", inr("100")); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+
+		withdrawal, err := bs.Withdraw("SAV", inr("30"))
+		if err != nil {
+			t.Fatalf("Withdraw: %v", err)
+		}
+		if balance, _ := bs.GetBalance("SAV"); !balance.Equal(inr("65")) {
+			t.Fatalf("balance after withdrawal and fee = %s, want 65.00", balance)
+		}
+
+		if _, err := bs.ReverseTransaction(withdrawal.ID, "ATM did not dispense"); err != nil {
+			t.Fatalf("ReverseTransaction: %v", err)
+		}
+		if balance, _ := bs.GetBalance("SAV"); !balance.Equal(inr("100")) {
+			t.Errorf("balance after reversal = %s, want 100.00", balance)
+		}
+		summary, err := bs.GetAccountSummary("SAV")
+		if err != nil {
+			t.Fatalf("GetAccountSummary: %v", err)
+		}
+		if !summary.TotalWithdrawals.IsZero() || !summary.TotalFees.IsZero() {
+			t.Errorf("summary after reversal: withdrawals %s, fees %s; want both 0.00", summary.TotalWithdrawals, summary.TotalFees)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestHolds(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.March, 2)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil), bank.WithHoldDuration(7*24*time.Hour))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		openAccount(t, bs, "SAV", "Savings", user.ID)
+		if _, err := bs.Deposit("SAV", inr("100")); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+
+		assertBalances := func(when string, balance, available bank.Money) {
+			t.Helper()
+			if got, _ := bs.GetBalance("SAV"); !got.Equal(balance) {
+				t.Errorf("balance %s = %s, want %s", when, got, balance)
+			}
+			if got, _ := bs.GetAvailableBalance("SAV"); !got.Equal(available) {
+				t.Errorf("available balance %s = %s, want %s", when, got, available)
+			}
+		}
+
+		hold, err := bs.PlaceHold("SAV", inr("60"), "Hotel booking")
+		if err != nil {
+			t.Fatalf("PlaceHold: %v", err)
+		}
+		if hold.Status != bank.Pending || hold.Type != bank.Hold {
+			t.Errorf("hold = %s %s, want a PENDING HOLD", hold.Status, hold.Type)
+		}
+		assertBalances("with a hold", inr("100"), inr("40"))
+		if _, err := bs.Withdraw("SAV", inr("50")); !errors.Is(err, bank.ErrInsufficientFunds) {
+			t.Errorf("Withdraw of held money = %v, want ErrInsufficientFunds", err)
+		}
+
+		withdrawal, err := bs.CaptureHold(hold.ID, inr("45"))
+		if err != nil {
+			t.Fatalf("CaptureHold: %v", err)
+		}
+		if withdrawal.Type != bank.Withdrawal || withdrawal.LinkedTransactionID != hold.ID {
+			t.Errorf("capture = %s linked to %q, want a WITHDRAWAL linked to %s", withdrawal.Type, withdrawal.LinkedTransactionID, hold.ID)
+		}
+		assertBalances("after a partial capture", inr("55"), inr("55"))
+		if _, err := bs.CaptureHold(hold.ID, inr("15")); !errors.Is(err, bank.ErrHoldNotPending) {
+			t.Errorf("second capture = %v, want ErrHoldNotPending", err)
+		}
+
+		released, err := bs.PlaceHold("SAV", inr("20"), "Fuel")
+		if err != nil {
+			t.Fatalf("PlaceHold: %v", err)
+		}
+		if _, err := bs.ReleaseHold(released.ID); err != nil {
+			t.Fatalf("ReleaseHold: %v", err)
+		}
+		assertBalances("after a release", inr("55"), inr("55"))
+
+		lapsed, err := bs.PlaceHold("SAV", inr("30"), "Car hire")
+		if err != nil {
+			t.Fatalf("PlaceHold: %v", err)
+		}
+		clock.Set(date(2026, time.March, 9))
+		// Reading the balance counts the lapsed hold as released, but leaves
+		// marking it expired to ExpireHolds.
+		assertBalances("once the hold lapsed", inr("55"), inr("55"))
+		if stored, err := bs.GetTransaction(lapsed.ID); err != nil || stored.Status != bank.Pending {
+			t.Errorf("lapsed hold after reading the balance = %v, %v; want it still PENDING", stored, err)
+		}
+		if _, err := bs.CaptureHold(lapsed.ID, inr("30")); !errors.Is(err, bank.ErrHoldExpired) {
+			t.Errorf("capture of a lapsed hold = %v, want ErrHoldExpired", err)
+		}
+		expired, err := bs.ExpireHolds()
+		if err != nil {
+			t.Fatalf("ExpireHolds: %v", err)
+		}
+		if len(expired) != 1 || expired[0].ID != lapsed.ID || expired[0].Status != bank.Expired {
+			t.Errorf("ExpireHolds = %v, want %s expired", expired, lapsed.ID)
+		}
+		assertBalances("after the hold expired", inr("55"), inr("55"))
+
+		// Lapsed holds are released without waiting for ExpireHolds.
+		if _, err := bs.PlaceHold("SAV", inr("50"), "Deposit on a flat"); err != nil {
+			t.Fatalf("PlaceHold: %v", err)
+		}
+		clock.Set(date(2026, time.March, 17))
+		if _, err := bs.Withdraw("SAV", inr("50")); err != nil {
+			t.Errorf("Withdraw after the hold lapsed: %v", err)
+		}
+		assertBalances("after withdrawing money a lapsed hold reserved", inr("5"), inr("5"))
+
+		summary, err := bs.GetAccountSummary("SAV")
+		if err != nil {
+			t.Fatalf("GetAccountSummary: %v", err)
+		}
+		if !summary.TotalWithdrawals.Equal(inr("95")) {
+			t.Errorf("total withdrawals = %s, want 95.00", summary.TotalWithdrawals)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestStandingInstructions(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.March, 1).Add(9 * time.Hour)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil))
+		user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		for _, accountNumber := range []string{"SAV", "RENT"} {
+			openAccount(t, bs, accountNumber, "Savings", user.ID)
+		}
+		if _, err := bs.Deposit("SAV", inr("1000")); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+		scheduler := bank.NewScheduler(bs, bank.WithRetryPolicy(bank.RetryPolicy{MaxRetries: 2, Interval: 6 * time.Hour}))
+
+		runDue := func(when time.Time, want ...bank.AttemptOutcome) {
+			t.Helper()
+			clock.Set(when)
+			attempts, err := scheduler.RunDue()
+			if err != nil {
+				t.Fatalf("RunDue at %s: %v", when, err)
+			}
+			var got []bank.AttemptOutcome
+			for _, attempt := range attempts {
+				got = append(got, attempt.Outcome)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("RunDue at %s = %v, want %v", when, got, want)
+			}
+		}
+		assertBalance := func(want bank.Money) {
+			t.Helper()
+			if got, _ := bs.GetBalance("SAV"); !got.Equal(want) {
+				t.Errorf("balance = %s, want %s", got, want)
+			}
+		}
+
+		if _, err := bs.CreateStandingInstruction("SAV", "RENT", inr("10"), "", bank.Recurrence{Frequency: bank.Daily, Start: date(2026, time.February, 1)}); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("instruction starting in the past = %v, want ErrInvalidInput", err)
+		}
+		if _, err := bs.CreateStandingInstruction("SAV", "RENT", inr("10"), "", bank.Recurrence{Frequency: bank.Weekly, DayOfMonth: 5}); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("weekly instruction with a day of month = %v, want ErrInvalidInput", err)
+		}
+
+		// Rent on the last day of each month from March to June, at 10:00.
+		rent, err := bs.CreateStandingInstruction("SAV", "RENT", inr("400"), "Rent", bank.Recurrence{
+			Frequency:  bank.Monthly,
+			Start:      date(2026, time.March, 1).Add(10 * time.Hour),
+			DayOfMonth: 31,
+			End:        date(2026, time.July, 1),
+		})
+		if err != nil {
+			t.Fatalf("CreateStandingInstruction: %v", err)
+		}
+		if want := date(2026, time.March, 31).Add(10 * time.Hour); !rent.NextRun.Equal(want) {
+			t.Errorf("first run = %s, want %s", rent.NextRun, want)
+		}
+
+		runDue(date(2026, time.March, 2))
+		runDue(date(2026, time.March, 31).Add(11*time.Hour), bank.AttemptSucceeded)
+		assertBalance(inr("600"))
+
+		// Not enough money for April's rent until the second retry.
+		if _, err := bs.Withdraw("SAV", inr("500")); err != nil {
+			t.Fatalf("Withdraw: %v", err)
+		}
+		runDue(date(2026, time.April, 30).Add(10*time.Hour), bank.AttemptRetrying)
+		runDue(date(2026, time.April, 30).Add(12 * time.Hour))
+		runDue(date(2026, time.April, 30).Add(16*time.Hour), bank.AttemptRetrying)
+		if _, err := bs.Deposit("SAV", inr("1000")); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+		runDue(date(2026, time.April, 30).Add(22*time.Hour), bank.AttemptSucceeded)
+		assertBalance(inr("700"))
+
+		// May's run falls due while paused and is skipped.
+		if _, err := bs.PauseStandingInstruction(rent.ID); err != nil {
+			t.Fatalf("PauseStandingInstruction: %v", err)
+		}
+		runDue(date(2026, time.June, 1))
+		resumed, err := bs.ResumeStandingInstruction(rent.ID)
+		if err != nil {
+			t.Fatalf("ResumeStandingInstruction: %v", err)
+		}
+		if want := date(2026, time.June, 30).Add(10 * time.Hour); !resumed.NextRun.Equal(want) {
+			t.Errorf("next run after resuming = %s, want %s", resumed.NextRun, want)
+		}
+
+		// June's run is made late, and is the last.
+		runDue(date(2026, time.July, 15), bank.AttemptSucceeded)
+		assertBalance(inr("300"))
+		rent, err = bs.GetStandingInstruction(rent.ID)
+		if err != nil {
+			t.Fatalf("GetStandingInstruction: %v", err)
+		}
+		if rent.Status != bank.InstructionCompleted {
+			t.Errorf("status after the last run = %s, want COMPLETED", rent.Status)
+		}
+		if _, err := bs.PauseStandingInstruction(rent.ID); !errors.Is(err, bank.ErrInstructionStatus) {
+			t.Errorf("pausing a completed instruction = %v, want ErrInstructionStatus", err)
+		}
+
+		attempts, err := bs.ListInstructionAttempts(rent.ID)
+		if err != nil {
+			t.Fatalf("ListInstructionAttempts: %v", err)
+		}
+		if len(attempts) != 5 {
+			t.Fatalf("got %d attempts, want 5", len(attempts))
+		}
+		for _, attempt := range attempts {
+			if (attempt.Outcome == bank.AttemptSucceeded) != (attempt.TransactionID != "") {
+				t.Errorf("attempt %d is %s with transaction %q", attempt.Sequence, attempt.Outcome, attempt.TransactionID)
+			}
+		}
+		transfer, err := bs.GetTransaction(attempts[4].TransactionID)
+		if err != nil {
+			t.Fatalf("GetTransaction: %v", err)
+		}
+		if transfer.Type != bank.Transfer || transfer.Description != fmt.Sprintf("Standing instruction %d: Rent", rent.ID) {
+			t.Errorf("transfer = %s %q, want the rent TRANSFER", transfer.Type, transfer.Description)
+		}
+
+		// A daily run that cannot be paid is given up after its retries, and the
+		// next day's is tried.
+		daily, err := bs.CreateStandingInstruction("SAV", "RENT", inr("5000"), "", bank.Recurrence{Frequency: bank.Daily})
+		if err != nil {
+			t.Fatalf("CreateStandingInstruction: %v", err)
+		}
+		runDue(date(2026, time.July, 15), bank.AttemptRetrying)
+		runDue(date(2026, time.July, 15).Add(6*time.Hour), bank.AttemptRetrying)
+		runDue(date(2026, time.July, 15).Add(12*time.Hour), bank.AttemptFailed)
+		daily, err = bs.GetStandingInstruction(daily.ID)
+		if err != nil {
+			t.Fatalf("GetStandingInstruction: %v", err)
+		}
+		if want := date(2026, time.July, 16); !daily.NextRun.Equal(want) || daily.Retries != 0 {
+			t.Errorf("after giving up: next run %s with %d retries, want %s with none", daily.NextRun, daily.Retries, want)
+		}
+		if _, err := bs.CancelStandingInstruction(daily.ID); err != nil {
+			t.Fatalf("CancelStandingInstruction: %v", err)
+		}
+		runDue(date(2026, time.July, 20))
+		assertBalance(inr("300"))
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: date(2026, time.March, 1).Add(9 * time.Hour)}
+	open := func() *bank.BankingSystem {
+		t.Helper()
+		store, err := bank.OpenFileStore(dir, 0)
+		if err != nil {
+			t.Fatalf("OpenFileStore: %v", err)
+		}
+		return bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil),
+			bank.WithIdempotencyRetention(time.Hour))
+	}
+
+	bs := open()
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	for _, accountNumber := range []string{"ACC001", "ACC002"} {
+		openAccount(t, bs, accountNumber, "Savings", user.ID)
+	}
+
+	deposit, err := bs.Idempotent("deposit-1").Deposit("ACC001", inr("500"))
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	transfer, err := bs.Idempotent("transfer-1").Transfer("ACC001", "ACC002", inr("100"))
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	// A failed request does not use up its key.
+	if _, err := bs.Idempotent("withdraw-1").Withdraw("ACC002", inr("1000")); !errors.Is(err, bank.ErrInsufficientFunds) {
+		t.Fatalf("overdrawing Withdraw = %v, want ErrInsufficientFunds", err)
+	}
+	if _, err := bs.Idempotent("withdraw-1").Withdraw("ACC002", inr("40")); err != nil {
+		t.Fatalf("Withdraw after a failed attempt: %v", err)
+	}
+
+	// Keys survive a restart.
+	if err := bs.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	bs = open()
+	defer bs.Close()
+
+	again, err := bs.Idempotent("deposit-1").Deposit("ACC001", inr("500"))
+	if err != nil {
+		t.Fatalf("repeated Deposit: %v", err)
+	}
+	if again.ID != deposit.ID {
+		t.Errorf("repeated Deposit made transaction %s, want the original %s", again.ID, deposit.ID)
+	}
+	again, err = bs.Idempotent("transfer-1").Transfer("ACC001", "ACC002", inr("100"))
+	if err != nil {
+		t.Fatalf("repeated Transfer: %v", err)
+	}
+	if again.ID != transfer.ID {
+		t.Errorf("repeated Transfer made transaction %s, want the original %s", again.ID, transfer.ID)
+	}
+	if _, err := bs.Idempotent("transfer-1").Transfer("ACC001", "ACC002", inr("200")); !errors.Is(err, bank.ErrIdempotencyKeyReused) {
+		t.Errorf("Transfer with a different amount = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if _, err := bs.Idempotent("transfer-1").Deposit("ACC001", inr("100")); !errors.Is(err, bank.ErrIdempotencyKeyReused) {
+		t.Errorf("Deposit under a transfer's key = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if got, _ := bs.GetBalance("ACC001"); !got.Equal(inr("400")) {
+		t.Errorf("ACC001 balance = %s after repeated requests, want 400.00", got)
+	}
+
+	// Keys belong to whoever sent them.
+	other, err := bs.As(bank.Principal{UserID: user.ID, Email: user.Email}).Idempotent("deposit-1").Deposit("ACC001", inr("1"))
+	if err != nil {
+		t.Fatalf("Deposit by another actor: %v", err)
+	}
+	if other.ID == deposit.ID {
+		t.Errorf("another actor's key returned transaction %s", other.ID)
+	}
+
+	// Once the retention window has passed the key can be used again.
+	clock.Set(clock.Now().Add(time.Hour))
+	if n, err := bs.PurgeIdempotencyKeys(); err != nil || n != 4 {
+		t.Errorf("PurgeIdempotencyKeys = %d, %v, want 4 keys purged", n, err)
+	}
+	fresh, err := bs.Idempotent("deposit-1").Deposit("ACC001", inr("500"))
+	if err != nil {
+		t.Fatalf("Deposit after expiry: %v", err)
+	}
+	if fresh.ID == deposit.ID {
+		t.Errorf("Deposit after expiry returned the original transaction %s", deposit.ID)
+	}
+	if got, _ := bs.GetBalance("ACC001"); !got.Equal(inr("901")) {
+		t.Errorf("ACC001 balance = %s, want 901.00", got)
+	}
+	assertLedgerConsistent(t, bs)
+}
+
+func TestSnowflakeIDs(t *testing.T) {
+	if _, err := bank.NewSnowflakeGenerator(bank.MaxNodeID + 1); !errors.Is(err, bank.ErrInvalidInput) {
+		t.Errorf("NewSnowflakeGenerator(%d) = %v, want ErrInvalidInput", bank.MaxNodeID+1, err)
+	}
+
+	const (
+		workers   = 8
+		perWorker = 5000
+	)
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]bool)
+		wg   sync.WaitGroup
+	)
+	for node := range 2 {
+		ids, err := bank.NewSnowflakeGenerator(node)
+		if err != nil {
+			t.Fatalf("NewSnowflakeGenerator(%d): %v", node, err)
+		}
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				issued := make([]string, perWorker)
+				for i := range issued {
+					issued[i] = ids.NewTransactionID()
+				}
+				if !slices.IsSorted(issued) {
+					t.Errorf("node %d issued transaction IDs out of order", node)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range issued {
+					if seen[id] {
+						t.Errorf("transaction ID %s issued twice", id)
+					}
+					seen[id] = true
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	ids, _ := bank.NewSnowflakeGenerator(7)
+	reference := ids.NewReferenceNumber()
+	if !bank.ValidReferenceNumber(reference) {
+		t.Fatalf("ValidReferenceNumber(%q) = false", reference)
+	}
+	if typed := strings.ToLower(strings.ReplaceAll(reference, "-", "")); !bank.ValidReferenceNumber(typed) {
+		t.Errorf("ValidReferenceNumber(%q) = false, want case and hyphens ignored", typed)
+	}
+	for i := len("REF-"); i < len(reference); i++ {
+		if reference[i] == '-' {
+			continue
+		}
+		for _, c := range "0123456789ABCDEFGHJKMNPQRSTVWXYZ" {
+			if byte(c) == reference[i] {
+				continue
+			}
+			if mistyped := reference[:i] + string(c) + reference[i+1:]; bank.ValidReferenceNumber(mistyped) {
+				t.Errorf("ValidReferenceNumber(%q) = true for a mistyped %q", mistyped, reference)
+			}
+		}
+	}
+}
+
+// clashingIDs hands out the same transaction ID every time, as two nodes
+// sharing a node ID might.
+type clashingIDs struct{ bank.IDGenerator }
+
+func (clashingIDs) NewTransactionID() string { return "TXN-CLASH" }
+
+func TestDuplicateTransactionIDRefused(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		ids, _ := bank.NewSnowflakeGenerator(0)
+		bs, accounts := newTestBank(t, 1, inr("0"), bank.WithStore(store), bank.WithIDGenerator(clashingIDs{ids}))
+		deposit, err := bs.Deposit(accounts[0], inr("100"))
+		if err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+
+		// The second transaction must not overwrite the first, and its
+		// money must not move without it.
+		if _, err := bs.Deposit(accounts[0], inr("50")); !errors.Is(err, bank.ErrTransactionExists) {
+			t.Errorf("Deposit with a clashing ID = %v, want ErrTransactionExists", err)
+		}
+		if got, err := bs.GetTransaction(deposit.ID); err != nil || !got.Amount.Equal(inr("100")) {
+			t.Errorf("GetTransaction(%s) = %+v, %v; want the first deposit", deposit.ID, got, err)
+		}
+		if balance, _ := bs.GetBalance(accounts[0]); !balance.Equal(inr("100")) {
+			t.Errorf("balance = %s, want 100.00", balance)
+		}
+		assertLedgerConsistent(t, bs)
+	})
+}
+
+func TestQueryTransactions(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.May, 1).Add(10 * time.Hour)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil))
+		var users []*bank.User
+		for i, accountNumber := range []string{"ACC001", "ACC002"} {
+			user, err := bs.CreateUser("Test", "User", fmt.Sprintf("user%d@example.com", i), "S3cure!Passw0rd",
+				"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+			if err != nil {
+				t.Fatalf("CreateUser: %v", err)
+			}
+			openAccount(t, bs, accountNumber, "Savings", user.ID)
+			users = append(users, user)
+		}
+		openAccount(t, bs, "ACC003", "Savings", users[0].ID)
+
+		on := func(day int) { clock.Set(date(2026, time.May, day).Add(10 * time.Hour)) }
+		must := func(transaction *bank.Transaction, err error) *bank.Transaction {
+			t.Helper()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return transaction
+		}
+		on(1)
+		must(bs.Deposit("ACC001", inr("1000")))
+		on(2)
+		must(bs.Deposit("ACC003", inr("200")))
+		on(3)
+		transfer := must(bs.Transfer("ACC001", "ACC003", inr("300")))
+		on(4)
+		must(bs.Withdraw("ACC001", inr("50")))
+		on(5)
+		must(bs.Deposit("ACC002", inr("70")))
+		// Transactions at the same moment are ordered by ID.
+		on(6)
+		for _, amount := range []string{"1", "2", "3"} {
+			must(bs.Deposit("ACC001", inr(amount)))
+		}
+		all, err := bs.ListTransactions()
+		if err != nil {
+			t.Fatalf("ListTransactions: %v", err)
+		}
+
+		query := func(bs *bank.BankingSystem, filter bank.TransactionFilter) []*bank.Transaction {
+			t.Helper()
+			var found []*bank.Transaction
+			for {
+				page, err := bs.QueryTransactions(filter)
+				if err != nil {
+					t.Fatalf("QueryTransactions(%+v): %v", filter, err)
+				}
+				if page.Transactions == nil {
+					t.Fatalf("QueryTransactions(%+v) returned a nil page", filter)
+				}
+				found = append(found, page.Transactions...)
+				if page.NextCursor == "" {
+					return found
+				}
+				filter.Cursor = page.NextCursor
+			}
+		}
+		ids := func(transactions []*bank.Transaction) []string {
+			var ids []string
+			for _, transaction := range transactions {
+				ids = append(ids, transaction.ID)
+			}
+			return ids
+		}
+
+		oldestFirst := ids(query(bs, bank.TransactionFilter{Limit: 3}))
+		if want := ids(all); !slices.Equal(oldestFirst, want) {
+			t.Errorf("paging oldest first = %v, want %v", oldestFirst, want)
+		}
+		newestFirst := ids(query(bs, bank.TransactionFilter{Limit: 2, NewestFirst: true}))
+		slices.Reverse(newestFirst)
+		if !slices.Equal(newestFirst, oldestFirst) {
+			t.Errorf("paging newest first = %v, want the reverse of %v", newestFirst, oldestFirst)
+		}
+
+		low, high := inr("100"), inr("300")
+		for _, tc := range []struct {
+			name   string
+			filter bank.TransactionFilter
+			want   int
+		}{
+			{"account and type", bank.TransactionFilter{AccountNumber: "ACC001", Types: []bank.TransactionType{bank.Deposit}}, 4},
+			{"user", bank.TransactionFilter{UserID: users[0].ID}, 7},
+			{"user and account", bank.TransactionFilter{UserID: users[1].ID, AccountNumber: "ACC001"}, 0},
+			{"amount range", bank.TransactionFilter{MinAmount: &low, MaxAmount: &high}, 2},
+			{"date range", bank.TransactionFilter{Since: date(2026, time.May, 2), Until: date(2026, time.May, 4)}, 2},
+			{"reference", bank.TransactionFilter{Text: strings.ToLower(transfer.ReferenceNumber)}, 1},
+			{"description", bank.TransactionFilter{Text: "WITHDRAWAL", Statuses: []bank.TransactionStatus{bank.Completed}}, 1},
+			{"nothing", bank.TransactionFilter{Statuses: []bank.TransactionStatus{bank.Reversed}}, 0},
+		} {
+			if got := query(bs, tc.filter); len(got) != tc.want {
+				t.Errorf("%s: got %d transactions, want %d", tc.name, len(got), tc.want)
+			}
+		}
+
+		// Customers only find transactions on their own accounts.
+		customer := bs.As(bank.Principal{UserID: users[0].ID, Email: users[0].Email})
+		if got := query(customer, bank.TransactionFilter{Limit: 1}); len(got) != 7 {
+			t.Errorf("customer found %d transactions, want 7", len(got))
+		}
+		if _, err := customer.QueryTransactions(bank.TransactionFilter{UserID: users[1].ID}); !errors.Is(err, bank.ErrForbidden) {
+			t.Errorf("querying another user's transactions = %v, want ErrForbidden", err)
+		}
+
+		page, err := bs.QueryTransactions(bank.TransactionFilter{Limit: 1})
+		if err != nil {
+			t.Fatalf("QueryTransactions: %v", err)
+		}
+		for _, filter := range []bank.TransactionFilter{
+			{Cursor: "not a cursor"},
+			{Cursor: page.NextCursor, NewestFirst: true},
+		} {
+			if _, err := bs.QueryTransactions(filter); !errors.Is(err, bank.ErrInvalidCursor) {
+				t.Errorf("QueryTransactions with cursor %q = %v, want ErrInvalidCursor", filter.Cursor, err)
+			}
+		}
+		if _, err := bs.QueryTransactions(bank.TransactionFilter{Types: []bank.TransactionType{"GIFT"}}); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("querying an unknown type = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+// queryLog is a Store that records the transaction queries made of it.
+type queryLog struct {
+	bank.Store
+	queries *[]bank.TransactionQuery
+}
+
+func (s queryLog) Transactions() bank.TransactionRepository {
+	return loggedTransactions{s.Store.Transactions(), s.queries}
+}
+
+func (s queryLog) Update(fn func(tx bank.Store) error) error {
+	return s.Store.Update(func(tx bank.Store) error {
+		return fn(queryLog{tx, s.queries})
+	})
+}
+
+type loggedTransactions struct {
+	bank.TransactionRepository
+	queries *[]bank.TransactionQuery
+}
+
+func (r loggedTransactions) Query(q bank.TransactionQuery) ([]*bank.Transaction, error) {
+	*r.queries = append(*r.queries, q)
+	return r.TransactionRepository.Query(q)
+}
+
+func TestQueryTransactionsSearchesOnlyVisibleAccounts(t *testing.T) {
+	var queries []bank.TransactionQuery
+	bs, _ := newTestBank(t, 2, inr("100"), bank.WithStore(queryLog{bank.NewMemoryStore(), &queries}))
+	newUser := func(email string) *bank.User {
+		t.Helper()
+		user, err := bs.CreateUser("Other", "User", email, "S3cure!Passw0rd",
+			"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		return user
+	}
+	customer := newUser("customer@example.com")
+	openAccount(t, bs, "OWN001", "Savings", customer.ID)
+	if _, err := bs.Deposit("OWN001", inr("100")); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	teller := newUser("teller@example.com")
+	if err := bs.AssignRole(teller.ID, bank.RoleTeller); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		as       *bank.BankingSystem
+		accounts []string
+		found    int
+	}{
+		// A customer's search goes straight to their own account instead
+		// of paging through everyone's.
+		{"customer", bs.As(bank.Principal{UserID: customer.ID, Email: customer.Email}), []string{"OWN001"}, 1},
+		{"teller", bs.As(bank.Principal{UserID: teller.ID, Email: teller.Email}), nil, 3},
+	} {
+		queries = nil
+		page, err := tc.as.QueryTransactions(bank.TransactionFilter{})
+		if err != nil {
+			t.Fatalf("%s: QueryTransactions: %v", tc.name, err)
+		}
+		if len(page.Transactions) != tc.found {
+			t.Errorf("%s found %d transactions, want %d", tc.name, len(page.Transactions), tc.found)
+		}
+		for _, q := range queries {
+			if !slices.Equal(q.Accounts, tc.accounts) {
+				t.Errorf("%s's search queried accounts %q, want %q", tc.name, q.Accounts, tc.accounts)
+			}
+		}
+	}
+}
+
+func TestTransactionIndexFollowsChanges(t *testing.T) {
+	dir := t.TempDir()
+	open := func() *bank.BankingSystem {
+		t.Helper()
+		store, err := bank.OpenFileStore(dir, 0)
+		if err != nil {
+			t.Fatalf("OpenFileStore: %v", err)
+		}
+		return bank.NewBankingSystem(bank.WithStore(store), bank.WithFeeSchedules(nil))
+	}
+
+	bs := open()
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	openAccount(t, bs, "SAV", "Savings", user.ID)
+	deposit, err := bs.Deposit("SAV", inr("100"))
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	hold, err := bs.PlaceHold("SAV", inr("30"), "Hotel booking")
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	if _, err := bs.ReleaseHold(hold.ID); err != nil {
+		t.Fatalf("ReleaseHold: %v", err)
+	}
+	if _, err := bs.ReverseTransaction(deposit.ID, "Counterfeit notes"); err != nil {
+		t.Fatalf("ReverseTransaction: %v", err)
+	}
+
+	count := func(filter bank.TransactionFilter) int {
+		t.Helper()
+		page, err := bs.QueryTransactions(filter)
+		if err != nil {
+			t.Fatalf("QueryTransactions(%+v): %v", filter, err)
+		}
+		return len(page.Transactions)
+	}
+	check := func(when string) {
+		t.Helper()
+		for _, tc := range []struct {
+			filter bank.TransactionFilter
+			want   int
+		}{
+			{bank.TransactionFilter{Statuses: []bank.TransactionStatus{bank.Pending}}, 0},
+			{bank.TransactionFilter{Statuses: []bank.TransactionStatus{bank.Cancelled}}, 1},
+			{bank.TransactionFilter{Statuses: []bank.TransactionStatus{bank.Reversed}}, 1},
+			{bank.TransactionFilter{Statuses: []bank.TransactionStatus{bank.Completed}}, 1},
+			{bank.TransactionFilter{Types: []bank.TransactionType{bank.Hold}, AccountNumber: "SAV"}, 1},
+			{bank.TransactionFilter{ReferenceNumber: strings.ToLower(deposit.ReferenceNumber)}, 2},
+		} {
+			if got := count(tc.filter); got != tc.want {
+				t.Errorf("%s: %+v found %d transactions, want %d", when, tc.filter, got, tc.want)
+			}
+		}
+	}
+	check("before restart")
+
+	// The index is rebuilt from the write-ahead log.
+	if err := bs.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	bs = open()
+	defer bs.Close()
+	check("after restart")
+}
+
+func TestStatement(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.April, 20)}
+		bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithClock(clock), bank.WithFeeSchedules(nil))
+		var users []*bank.User
+		for i, accountNumber := range []string{"ACC001", "ACC002"} {
+			user, err := bs.CreateUser("Test", "User", fmt.Sprintf("user%d@example.com", i), "S3cure!Passw0rd",
+				"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+			if err != nil {
+				t.Fatalf("CreateUser: %v", err)
+			}
+			openAccount(t, bs, accountNumber, "Savings", user.ID)
+			users = append(users, user)
+		}
+
+		at := func(when time.Time) {
+			clock.Set(when)
+		}
+		must := func(_ *bank.Transaction, err error) {
+			t.Helper()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		must(bs.Deposit("ACC001", inr("1000")))
+		at(date(2026, time.May, 3))
+		must(bs.Transfer("ACC001", "ACC002", inr("300")))
+		at(date(2026, time.May, 10))
+		must(bs.Deposit("ACC001", inr("50")))
+		for range 70 {
+			must(bs.Deposit("ACC002", inr("1")))
+		}
+		at(date(2026, time.June, 1).Add(-time.Hour))
+		must(bs.Withdraw("ACC001", inr("100")))
+		at(date(2026, time.June, 1).Add(30 * time.Minute))
+		must(bs.Deposit("ACC001", inr("5")))
+
+		if _, err := bs.MonthlyStatement("ACC001", 2026, time.June); !errors.Is(err, bank.ErrInvalidInput) {
+			t.Errorf("statement for the current month = %v, want ErrInvalidInput", err)
+		}
+		customer := bs.As(bank.Principal{UserID: users[1].ID, Email: users[1].Email})
+		if _, err := customer.MonthlyStatement("ACC001", 2026, time.May); !errors.Is(err, bank.ErrForbidden) {
+			t.Errorf("statement for another customer's account = %v, want ErrForbidden", err)
+		}
+
+		st, err := bs.MonthlyStatement("ACC001", 2026, time.May)
+		if err != nil {
+			t.Fatalf("MonthlyStatement: %v", err)
+		}
+		if !st.OpeningBalance.Equal(inr("1000")) || !st.ClosingBalance.Equal(inr("650")) {
+			t.Errorf("opening %s, closing %s, want ₹1000.00 and ₹650.00", st.OpeningBalance, st.ClosingBalance)
+		}
+		if !st.TotalCredits.Equal(inr("50")) || !st.TotalDebits.Equal(inr("400")) {
+			t.Errorf("credits %s, debits %s, want ₹50.00 and ₹400.00", st.TotalCredits, st.TotalDebits)
+		}
+		want := []struct {
+			tType                  bank.TransactionType
+			debit, credit, balance string
+		}{
+			{bank.Transfer, "300", "0", "700"},
+			{bank.Deposit, "0", "50", "750"},
+			{bank.Withdrawal, "100", "0", "650"},
+		}
+		if len(st.Entries) != len(want) {
+			t.Fatalf("statement has %d entries, want %d", len(st.Entries), len(want))
+		}
+		for i, w := range want {
+			entry := st.Entries[i]
+			if entry.Type != w.tType || !entry.Credit.Equal(inr(w.credit)) || !entry.Debit.Equal(inr(w.debit)) || !entry.Balance.Equal(inr(w.balance)) {
+				t.Errorf("entry %d = %s credit %s debit %s balance %s, want %s credit %s debit %s balance %s", i,
+					entry.Type, entry.Credit, entry.Debit, entry.Balance, w.tType, w.credit, w.debit, w.balance)
+			}
+			if entry.ReferenceNumber == "" {
+				t.Errorf("entry %d has no reference number", i)
+			}
+		}
+
+		var text, html, pdf strings.Builder
+		if err := st.RenderText(&text); err != nil {
+			t.Fatalf("RenderText: %v", err)
+		}
+		for _, s := range []string{"Period:    2026-05-01 to 2026-05-31", "Opening balance", "Closing balance", "650.00"} {
+			if !strings.Contains(text.String(), s) {
+				t.Errorf("text statement lacks %q:\n%s", s, text.String())
+			}
+		}
+		if err := st.RenderHTML(&html); err != nil {
+			t.Fatalf("RenderHTML: %v", err)
+		}
+		if !strings.Contains(html.String(), `<td class="amount">650.00</td>`) {
+			t.Errorf("HTML statement lacks the closing balance:\n%s", html.String())
+		}
+
+		// A PDF's trailer points at its cross-reference table.
+		checkPDF := func(st *bank.Statement, pages int) {
+			t.Helper()
+			pdf.Reset()
+			if err := st.RenderPDF(&pdf); err != nil {
+				t.Fatalf("RenderPDF: %v", err)
+			}
+			doc := pdf.String()
+			if !strings.HasPrefix(doc, "%PDF-1.4\n") || !strings.HasSuffix(doc, "%%EOF\n") {
+				t.Fatalf("PDF is not framed by a header and an end marker")
+			}
+			_, startxref, _ := strings.Cut(doc[strings.LastIndex(doc, "startxref\n"):], "\n")
+			var offset int
+			if _, err := fmt.Sscanf(startxref, "%d", &offset); err != nil || !strings.HasPrefix(doc[offset:], "xref\n") {
+				t.Errorf("startxref %q does not point at the cross-reference table", startxref)
+			}
+			if !strings.Contains(doc, fmt.Sprintf("/Count %d", pages)) {
+				t.Errorf("PDF does not have %d pages", pages)
+			}
+		}
+		checkPDF(st, 1)
+		if !strings.Contains(pdf.String(), "(Account Statement ACC001)") {
+			t.Errorf("PDF lacks its title")
+		}
+
+		st, err = bs.MonthlyStatement("ACC002", 2026, time.May)
+		if err != nil {
+			t.Fatalf("MonthlyStatement: %v", err)
+		}
+		if len(st.Entries) != 71 || !st.ClosingBalance.Equal(inr("370")) {
+			t.Errorf("ACC002 has %d entries closing at %s, want 71 closing at ₹370.00", len(st.Entries), st.ClosingBalance)
+		}
+		checkPDF(st, 2)
+	})
+}
+
+func TestStatementOrdersByDate(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store bank.Store) {
+		clock := &fakeClock{now: date(2026, time.January, 5)}
+		bs, accounts := newTestBank(t, 1, inr("1000"), bank.WithStore(store), bank.WithClock(clock),
+			bank.WithInterestProducts(map[string]bank.InterestProduct{
+				"Savings": {
+					Method:    bank.SimpleInterest,
+					Crediting: bank.CreditMonthly,
+					Tiers:     []bank.InterestTier{{From: inr("0"), Rate: bank.MustParsePercent("36.5")}},
+				},
+			}))
+		account := accounts[0]
+
+		// January's interest is posted after the withdrawal but dated
+		// before it, at the end of January.
+		clock.Set(date(2026, time.February, 3))
+		if _, err := bs.Withdraw(account, inr("100")); err != nil {
+			t.Fatalf("Withdraw: %v", err)
+		}
+		clock.Set(date(2026, time.February, 10))
+		if _, err := bs.AccrueInterest(date(2026, time.February, 1)); err != nil {
+			t.Fatalf("AccrueInterest: %v", err)
+		}
+
+		clock.Set(date(2026, time.March, 2))
+		st, err := bs.MonthlyStatement(account, 2026, time.February)
+		if err != nil {
+			t.Fatalf("MonthlyStatement: %v", err)
+		}
+		var got []string
+		for _, entry := range st.Entries {
+			got = append(got, fmt.Sprintf("%s %s", entry.Type, entry.Balance.Amount()))
+		}
+		want := []string{fmt.Sprintf("%s 1027.00", bank.Interest), fmt.Sprintf("%s 927.00", bank.Withdrawal)}
+		if !slices.Equal(got, want) {
+			t.Errorf("statement entries %v, want %v", got, want)
+		}
+	})
+}
+
+// benchmarkTransactions is how many transactions the query benchmarks load.
+const benchmarkTransactions = 1_000_000
+
+var (
+	benchmarkStoreOnce sync.Once
+	benchmarkStore     *bank.MemoryStore
+)
+
+// loadBenchmarkStore fills a store with a year of transactions spread over
+// 1,000 accounts, one of them busy, with a few reversals and pending holds.
+func loadBenchmarkStore(b *testing.B) bank.TransactionRepository {
+	benchmarkStoreOnce.Do(func() {
+		store := bank.NewMemoryStore()
+		ids, _ := bank.NewSnowflakeGenerator(0)
+		start := date(2025, time.January, 1)
+		step := 365 * 24 * time.Hour / benchmarkTransactions
+		rng := rand.New(rand.NewPCG(1, 2))
+		types := []bank.TransactionType{bank.Deposit, bank.Withdrawal, bank.Transfer}
+		for i := range benchmarkTransactions {
+			account := fmt.Sprintf("ACC%04d", rng.IntN(1000))
+			if i%100 == 0 {
+				account = "BUSY"
+			}
+			t := bank.Transaction{
+				ID:              ids.NewTransactionID(),
+				Type:            types[rng.IntN(len(types))],
+				Status:          bank.Completed,
+				FromAccount:     account,
+				Amount:          bank.NewMoney(int64(rng.IntN(100_000)+1), bank.INR),
+				Timestamp:       start.Add(time.Duration(i) * step),
+				Description:     "Benchmark",
+				ReferenceNumber: ids.NewReferenceNumber(),
+			}
+			switch {
+			case i%10_000 == 0:
+				t.Type, t.ToAccount = bank.Reversal, account
+			case i%5_000 == 0:
+				t.Type, t.Status = bank.Hold, bank.Pending
+			}
+			if err := store.Transactions().Save(t); err != nil {
+				b.Fatalf("Save: %v", err)
+			}
+		}
+		benchmarkStore = store
+	})
+	b.ResetTimer()
+	return benchmarkStore.Transactions()
+}
+
+// BenchmarkQueryTransactions compares finding the latest page of matches
+// through the indexes with scanning every transaction, as lookups did
+// before there were indexes.
+func BenchmarkQueryTransactions(b *testing.B) {
+	all, err := loadBenchmarkStore(b).List()
+	if err != nil {
+		b.Fatalf("List: %v", err)
+	}
+	reference := all[len(all)/2].ReferenceNumber
+
+	for _, bc := range []struct {
+		name  string
+		query bank.TransactionQuery
+		match func(*bank.Transaction) bool
+	}{
+		{"account", bank.TransactionQuery{Accounts: []string{"BUSY"}},
+			func(t *bank.Transaction) bool { return t.FromAccount == "BUSY" || t.ToAccount == "BUSY" }},
+		{"type", bank.TransactionQuery{Types: []bank.TransactionType{bank.Reversal}},
+			func(t *bank.Transaction) bool { return t.Type == bank.Reversal }},
+		{"status", bank.TransactionQuery{Statuses: []bank.TransactionStatus{bank.Pending}},
+			func(t *bank.Transaction) bool { return t.Status == bank.Pending }},
+		{"reference", bank.TransactionQuery{ReferenceNumber: reference},
+			func(t *bank.Transaction) bool { return t.ReferenceNumber == reference }},
+	} {
+		bc.query.NewestFirst = true
+		bc.query.Limit = bank.DefaultPageSize
+
+		b.Run(bc.name+"/indexed", func(b *testing.B) {
+			repo := loadBenchmarkStore(b)
+			for b.Loop() {
+				if _, err := repo.Query(bc.query); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(bc.name+"/scan", func(b *testing.B) {
+			repo := loadBenchmarkStore(b)
+			for b.Loop() {
+				transactions, err := repo.List()
+				if err != nil {
+					b.Fatal(err)
+				}
+				var matched []*bank.Transaction
+				for _, t := range slices.Backward(transactions) {
+					if bc.match(t) {
+						if matched = append(matched, t); len(matched) == bank.DefaultPageSize {
+							break
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package bank
+
+import "time"
+
+// Clock tells the banking system what time it is. Timestamps on accounts,
+// transactions, journal entries and the audit trail all come from it.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the wall clock, and the default.
+var SystemClock Clock = systemClock{}
+
+// WithClock replaces SystemClock, for example to run batch jobs or tests at
+// a fixed time.
+func WithClock(clock Clock) Option {
+	return func(bs *BankingSystem) {
+		bs.clock = clock
+	}
+}
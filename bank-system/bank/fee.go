@@ -0,0 +1,259 @@
+package bank
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// TransactionFee is charged on each transaction of one type beyond a free
+// allowance per calendar month. The account the money leaves pays it, or
+// the receiving account for deposits.
+type TransactionFee struct {
+	FreePerMonth int
+	// Flat is charged on every chargeable transaction.
+	Flat Money
+	// Rate, if set, adds a share of the transaction amount, such as 0.005
+	// for 0.5%.
+	Rate *big.Rat
+}
+
+// FeeSchedule lists the charges on one type of account. Transaction fees
+// are charged with the transaction; monthly fees by ChargeMonthlyFees.
+type FeeSchedule struct {
+	Transactions map[TransactionType]TransactionFee
+	// MinimumBalance is the monthly average balance below which
+	// MinimumBalancePenalty is charged. The average is over the closing
+	// balances of each day of the month.
+	MinimumBalance        Money
+	MinimumBalancePenalty Money
+	// Maintenance is charged every month.
+	Maintenance Money
+}
+
+// DefaultFeeSchedules charges savings accounts for withdrawals and
+// transfers beyond five a month and for keeping a monthly average balance
+// under 1,000, and current accounts a monthly maintenance charge.
+var DefaultFeeSchedules = map[string]FeeSchedule{
+	"Savings": {
+		Transactions: map[TransactionType]TransactionFee{
+			Withdrawal: {FreePerMonth: 5, Flat: MustParseMoney("20", INR)},
+			Transfer:   {FreePerMonth: 5, Flat: MustParseMoney("5", INR)},
+		},
+		MinimumBalance:        MustParseMoney("1000", INR),
+		MinimumBalancePenalty: MustParseMoney("50", INR),
+	},
+	"Current": {
+		Maintenance: MustParseMoney("100", INR),
+	},
+}
+
+// WithFeeSchedules replaces DefaultFeeSchedules. Schedules are keyed by
+// Account.AccountType; other accounts are not charged.
+func WithFeeSchedules(schedules map[string]FeeSchedule) Option {
+	return func(bs *BankingSystem) {
+		bs.feeSchedules = schedules
+	}
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// transactionFee returns the fee due on a transaction about to be made
+// from payer, or zero if there is none. The transaction itself is not yet
+// counted against the free allowance.
+func (s *services) transactionFee(tType TransactionType, payer string, amount Money) (Money, error) {
+	account, err := s.accounts.GetAccountDetails(payer)
+	if err != nil {
+		return Money{}, err
+	}
+	fee, ok := s.feeSchedules[account.AccountType].Transactions[tType]
+	if !ok {
+		return Zero(amount.Currency()), nil
+	}
+
+	if fee.FreePerMonth > 0 {
+		monthStart := startOfMonth(s.clock.Now())
+		transactions, err := s.transactions.GetTransactionsByAccount(payer)
+		if err != nil {
+			return Money{}, err
+		}
+		used := 0
+		for _, t := range transactions {
+			if t.Type == tType && t.Status == Completed && feePayer(t) == payer && !t.Timestamp.Before(monthStart) {
+				used++
+			}
+		}
+		if used < fee.FreePerMonth {
+			return Zero(amount.Currency()), nil
+		}
+	}
+
+	total := fee.Flat
+	if fee.Rate != nil {
+		share, err := amount.MulRat(fee.Rate, RoundHalfUp)
+		if err != nil {
+			return Money{}, err
+		}
+		if total, err = total.Add(share); err != nil {
+			return Money{}, err
+		}
+	}
+	if total.IsZero() {
+		return Zero(amount.Currency()), nil
+	}
+	if total.Currency() != amount.Currency() {
+		return Money{}, fmt.Errorf("%w: %s fee in %s on a %s transaction", ErrCurrencyMismatch, tType, total.Currency(), amount.Currency())
+	}
+	return total, nil
+}
+
+func feePayer(t *Transaction) string {
+	if t.FromAccount != "" {
+		return t.FromAccount
+	}
+	return t.ToAccount
+}
+
+// chargeFee debits a fee from an account and records it as a Fee
+// transaction. If linkedTo is set, the fee is linked to that transaction
+// and added to its Fee.
+func (s *services) chargeFee(accountNumber string, amount Money, description, linkedTo string) (*Transaction, error) {
+	fee, err := s.post(Fee, accountNumber, "", amount, description, feeLines(accountNumber, amount))
+	if err != nil {
+		return nil, err
+	}
+	if linkedTo != "" {
+		if err := s.transactions.LinkFee(linkedTo, fee.ID); err != nil {
+			return nil, err
+		}
+		fee.LinkedTransactionID = linkedTo
+	}
+	return fee, nil
+}
+
+// ChargeMonthlyFees charges the maintenance fee and any minimum balance
+// penalty for each calendar month that ended by asOf, on every open
+// account with a fee schedule. Months run in asOf's location.
+//
+// Each account remembers the last month it was charged for, so running
+// again for the same month charges nothing. A charge larger than the
+// account's available balance is reduced to it.
+func (bs *BankingSystem) ChargeMonthlyFees(asOf time.Time) ([]*Transaction, error) {
+	if err := bs.authorize(ActionRunBatch, bankResource); err != nil {
+		return nil, err
+	}
+	if asOf.After(bs.clock.Now()) {
+		return nil, fmt.Errorf("%w: cannot charge fees for months that have not ended", ErrInvalidInput)
+	}
+
+	accounts, err := bs.accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var charged []*Transaction
+	for _, account := range accounts {
+		schedule, ok := bs.feeSchedules[account.AccountType]
+		if !ok || account.Status == StatusClosed {
+			continue
+		}
+
+		err := func() error {
+			unlock := bs.accountLocks.lock(account.AccountNumber)
+			defer unlock()
+
+			return bs.update(func(s *services) error {
+				fees, err := s.chargeMonthlyFees(account.AccountNumber, schedule, asOf)
+				if err != nil {
+					return err
+				}
+				charged = append(charged, fees...)
+				return nil
+			})
+		}()
+		if err != nil {
+			return charged, fmt.Errorf("charge fees on account %s: %w", account.AccountNumber, err)
+		}
+	}
+	return charged, nil
+}
+
+type monthlyCharge struct {
+	amount      Money
+	description string
+}
+
+// chargeMonthlyFees charges one account for each month since the last one
+// it was charged for that ended by asOf. The caller must hold the account's
+// lock.
+func (s *services) chargeMonthlyFees(accountNumber string, schedule FeeSchedule, asOf time.Time) ([]*Transaction, error) {
+	account, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := asOf.Location()
+	start := account.FeesChargedUntil
+	if start.IsZero() {
+		start = account.CreatedAt
+	}
+	day := startOfDay(start.In(loc))
+	end := startOfMonth(asOf)
+
+	entries, err := s.ledger.GetEntries(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	balances := newBalanceHistory(entries, accountNumber)
+
+	var charged []*Transaction
+	for day.Before(end) {
+		monthStart := day
+		monthEnd := CreditMonthly.periodEnd(day)
+
+		total, days := new(big.Rat), 0
+		for ; day.Before(monthEnd); day = day.AddDate(0, 0, 1) {
+			total.Add(total, balances.closing(day.AddDate(0, 0, 1)))
+			days++
+		}
+		average := total.Quo(total, big.NewRat(int64(days), 1))
+
+		var charges []monthlyCharge
+		month := monthStart.Format("Jan 2006")
+		if schedule.Maintenance.IsPositive() {
+			charges = append(charges, monthlyCharge{schedule.Maintenance, "Account maintenance charge for " + month})
+		}
+		if schedule.MinimumBalancePenalty.IsPositive() && average.Cmp(big.NewRat(schedule.MinimumBalance.MinorUnits(), 1)) < 0 {
+			charges = append(charges, monthlyCharge{schedule.MinimumBalancePenalty, "Minimum balance charge for " + month})
+		}
+
+		for _, charge := range charges {
+			available, err := s.accounts.AvailableBalance(accountNumber)
+			if err != nil {
+				return nil, err
+			}
+			amount := charge.amount
+			if c, err := amount.Cmp(available); err != nil {
+				return nil, err
+			} else if c > 0 {
+				amount = available
+			}
+			if !amount.IsPositive() {
+				continue
+			}
+
+			fee, err := s.chargeFee(accountNumber, amount, charge.description, "")
+			if err != nil {
+				return nil, err
+			}
+			charged = append(charged, fee)
+		}
+
+		if err := s.accounts.MarkFeesCharged(accountNumber, monthEnd); err != nil {
+			return nil, err
+		}
+	}
+	return charged, nil
+}
@@ -0,0 +1,294 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrHoldNotPending = errors.New("hold has already been captured, released or expired")
+	ErrHoldExpired    = errors.New("hold has expired")
+)
+
+// DefaultHoldDuration is how long a hold lasts unless it is captured or
+// released first.
+const DefaultHoldDuration = 7 * 24 * time.Hour
+
+// WithHoldDuration replaces DefaultHoldDuration.
+func WithHoldDuration(d time.Duration) Option {
+	return func(bs *BankingSystem) {
+		bs.holdDuration = d
+	}
+}
+
+// PlaceHold reserves money in an account for a payment that will be taken
+// later. The hold is a Pending Hold transaction: it reduces the available
+// balance but moves no money and posts nothing to the ledger until it is
+// captured. It lapses after the system's hold duration, and is released by
+// the next debit from the account or ExpireHolds, whichever comes first.
+func (bs *BankingSystem) PlaceHold(accountNumber string, amount Money, description string) (*Transaction, error) {
+	if err := bs.authorize(ActionWithdraw, accountResource(accountNumber)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	var hold *Transaction
+	err := bs.update(func(s *services) error {
+		if err := s.checkOperation(accountNumber, OperationWithdraw); err != nil {
+			return err
+		}
+		if err := s.expireHolds(accountNumber); err != nil {
+			return err
+		}
+		if err := s.accounts.Hold(accountNumber, amount); err != nil {
+			return err
+		}
+
+		var err error
+		hold, err = s.transactions.CreatePendingTransaction(Hold, accountNumber, "", amount, description, s.clock.Now().Add(bs.holdDuration))
+		if err != nil {
+			return err
+		}
+
+		s.audit.record(AuditPlaceHold, "transaction:"+hold.ID, nil, hold)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// CaptureHold takes some or all of the money a hold reserved, as a
+// withdrawal linked to the hold. Whatever is not captured is released.
+func (bs *BankingSystem) CaptureHold(holdID string, amount Money) (*Transaction, error) {
+	hold, err := bs.transactions.GetTransaction(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.authorize(ActionWithdraw, transactionResource(hold)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(hold.FromAccount)
+	defer unlock()
+
+	var withdrawal *Transaction
+	err = bs.update(func(s *services) error {
+		hold, err := s.pendingHold(holdID)
+		if err != nil {
+			return err
+		}
+		if s.lapsed(hold) {
+			return fmt.Errorf("%w: %s lapsed at %s", ErrHoldExpired, holdID, hold.ExpiresAt.Format(time.RFC3339))
+		}
+		if !amount.IsPositive() {
+			return ErrInvalidAmount
+		}
+		if c, err := amount.Cmp(hold.Amount); err != nil {
+			return err
+		} else if c > 0 {
+			return fmt.Errorf("%w: cannot capture %s of a %s hold", ErrInvalidAmount, amount, hold.Amount)
+		}
+
+		// Release the whole hold first so the money it reserved can be
+		// withdrawn.
+		if _, err := s.endHold(holdID, Completed, AuditCaptureHold); err != nil {
+			return err
+		}
+		withdrawal, err = s.move(Withdrawal, hold.FromAccount, "", amount, "Capture of "+holdID+": "+hold.Description, withdrawalLines(hold.FromAccount, amount))
+		if err != nil {
+			return err
+		}
+		if err := s.transactions.LinkCapture(holdID, withdrawal.ID); err != nil {
+			return err
+		}
+		withdrawal.LinkedTransactionID = holdID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withdrawal, nil
+}
+
+// ReleaseHold cancels a hold, making the money it reserved available again.
+func (bs *BankingSystem) ReleaseHold(holdID string) (*Transaction, error) {
+	hold, err := bs.transactions.GetTransaction(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.authorize(ActionWithdraw, transactionResource(hold)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(hold.FromAccount)
+	defer unlock()
+
+	var released *Transaction
+	err = bs.update(func(s *services) error {
+		released, err = s.endHold(holdID, Cancelled, AuditReleaseHold)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+// ExpireHolds releases every pending hold that has lapsed and marks it
+// Expired.
+func (bs *BankingSystem) ExpireHolds() ([]*Transaction, error) {
+	if err := bs.authorize(ActionRunBatch, bankResource); err != nil {
+		return nil, err
+	}
+
+	pending, err := bs.transactions.Query(TransactionQuery{Types: []TransactionType{Hold}, Statuses: []TransactionStatus{Pending}})
+	if err != nil {
+		return nil, err
+	}
+	var expired []*Transaction
+	for _, transaction := range pending {
+		if !bs.lapsed(transaction) {
+			continue
+		}
+
+		err := func() error {
+			unlock := bs.accountLocks.lock(transaction.FromAccount)
+			defer unlock()
+
+			return bs.update(func(s *services) error {
+				hold, err := s.endHold(transaction.ID, Expired, AuditExpireHold)
+				if errors.Is(err, ErrHoldNotPending) {
+					// Captured, released or expired meanwhile.
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				expired = append(expired, hold)
+				return nil
+			})
+		}()
+		if err != nil {
+			return expired, fmt.Errorf("expire hold %s: %w", transaction.ID, err)
+		}
+	}
+	return expired, nil
+}
+
+// GetAvailableBalance returns what can be taken from an account now: its
+// balance plus any unused overdraft, less the money held for pending
+// payments. Holds that have lapsed count as released, though they are only
+// marked expired by ExpireHolds or the next payment from the account.
+func (bs *BankingSystem) GetAvailableBalance(accountNumber string) (Money, error) {
+	if err := bs.authorize(ActionViewAccount, accountResource(accountNumber)); err != nil {
+		return Money{}, err
+	}
+
+	// The lock keeps the held amount and the holds that make it up in step.
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	account, err := bs.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return Money{}, err
+	}
+	holds, err := bs.lapsedHolds(account)
+	if err != nil {
+		return Money{}, err
+	}
+	for _, hold := range holds {
+		if account.HeldAmount, err = account.HeldAmount.Sub(hold.Amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return account.AvailableBalance()
+}
+
+func (s *services) lapsed(hold *Transaction) bool {
+	return !s.clock.Now().Before(hold.ExpiresAt)
+}
+
+// pendingHold returns a hold that has not been captured, released or
+// marked expired.
+func (s *services) pendingHold(holdID string) (*Transaction, error) {
+	hold, err := s.transactions.GetTransaction(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Type != Hold {
+		return nil, fmt.Errorf("%w: %s is not a hold", ErrInvalidInput, holdID)
+	}
+	if hold.Status != Pending {
+		return nil, fmt.Errorf("%w: %s is %s", ErrHoldNotPending, holdID, hold.Status)
+	}
+	return hold, nil
+}
+
+// endHold releases the money a pending hold reserved and gives the hold its
+// final status. The caller must hold the account's lock.
+func (s *services) endHold(holdID string, status TransactionStatus, action string) (*Transaction, error) {
+	hold, err := s.pendingHold(holdID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.accounts.ReleaseHold(hold.FromAccount, hold.Amount); err != nil {
+		return nil, err
+	}
+	if err := s.transactions.UpdateTransactionStatus(holdID, status); err != nil {
+		return nil, err
+	}
+	ended, err := s.transactions.GetTransaction(holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.record(action, "transaction:"+holdID, hold, ended)
+	return ended, nil
+}
+
+// expireHolds releases an account's lapsed holds. The caller must hold the
+// account's lock.
+func (s *services) expireHolds(accountNumber string) error {
+	account, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return err
+	}
+	holds, err := s.lapsedHolds(account)
+	if err != nil {
+		return err
+	}
+	for _, hold := range holds {
+		if _, err := s.endHold(hold.ID, Expired, AuditExpireHold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lapsedHolds returns an account's holds that have lapsed but are still
+// pending.
+func (s *services) lapsedHolds(account *Account) ([]*Transaction, error) {
+	if !account.HeldAmount.IsPositive() {
+		return nil, nil
+	}
+
+	transactions, err := s.transactions.GetTransactionsByAccount(account.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	var lapsed []*Transaction
+	for _, t := range transactions {
+		if t.Type == Hold && t.Status == Pending && t.FromAccount == account.AccountNumber && s.lapsed(t) {
+			lapsed = append(lapsed, t)
+		}
+	}
+	return lapsed, nil
+}
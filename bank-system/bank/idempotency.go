@@ -0,0 +1,119 @@
+package bank
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+// DefaultIdempotencyRetention is how long an idempotency key is remembered.
+const DefaultIdempotencyRetention = 24 * time.Hour
+
+// WithIdempotencyRetention replaces DefaultIdempotencyRetention.
+func WithIdempotencyRetention(d time.Duration) Option {
+	return func(bs *BankingSystem) {
+		bs.idempotencyRetention = d
+	}
+}
+
+// IdempotencyRecord remembers the transaction made by a request that
+// carried an idempotency key. Keys belong to the actor that sent them, so
+// two customers cannot collide.
+type IdempotencyRecord struct {
+	Actor string
+	Key   string
+	// RequestHash identifies the operation and its arguments.
+	RequestHash   string
+	TransactionID string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+}
+
+// Idempotent returns a view of bs whose deposits, withdrawals and transfers
+// take effect at most once for key. Repeating a request with the same key
+// and arguments returns the original transaction without moving money
+// again; repeating the key with different arguments fails with
+// ErrIdempotencyKeyReused. A request that fails does not use up its key.
+// Keys are forgotten after the system's retention window.
+func (bs *BankingSystem) Idempotent(key string) *BankingSystem {
+	keyed := *bs
+	keyed.idempotencyKey = key
+	return &keyed
+}
+
+// idempotencyLock is the lock table key that serializes requests with bs's
+// idempotency key. It is empty, and so ignored by lock, if there is none.
+func (bs *BankingSystem) idempotencyLock() string {
+	if bs.idempotencyKey == "" {
+		return ""
+	}
+	return "idempotency:" + bs.actor() + "/" + bs.idempotencyKey
+}
+
+// once runs move unless bs's idempotency key has already been used, in which
+// case it returns the transaction the key's first request made. request
+// describes the operation and its arguments. It must run inside a unit of
+// work, and the caller must hold idempotencyLock.
+func (bs *BankingSystem) once(s *services, request string, move func() (*Transaction, error)) (*Transaction, error) {
+	if bs.idempotencyKey == "" {
+		return move()
+	}
+
+	sum := sha256.Sum256([]byte(request))
+	hash := hex.EncodeToString(sum[:])
+	now := s.clock.Now()
+
+	record, err := s.idempotency.Get(bs.actor(), bs.idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && now.Before(record.ExpiresAt) {
+		if record.RequestHash != hash {
+			return nil, fmt.Errorf("%w: %q", ErrIdempotencyKeyReused, bs.idempotencyKey)
+		}
+		return s.transactions.GetTransaction(record.TransactionID)
+	}
+
+	transaction, err := move()
+	if err != nil {
+		return nil, err
+	}
+	err = s.idempotency.Save(IdempotencyRecord{
+		Actor:         bs.actor(),
+		Key:           bs.idempotencyKey,
+		RequestHash:   hash,
+		TransactionID: transaction.ID,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(bs.idempotencyRetention),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// idempotentRequest describes a money movement for once.
+func idempotentRequest(tType TransactionType, fromAccount, toAccount string, amount Money) string {
+	return fmt.Sprintf("%s from=%q to=%q amount=%s %s", tType, fromAccount, toAccount, amount.Amount(), amount.Currency())
+}
+
+// PurgeIdempotencyKeys forgets the idempotency keys whose retention window
+// has passed and returns how many there were. Expired keys are already
+// ignored; purging only reclaims their space.
+func (bs *BankingSystem) PurgeIdempotencyKeys() (int, error) {
+	if err := bs.authorize(ActionRunBatch, bankResource); err != nil {
+		return 0, err
+	}
+
+	var purged int
+	err := bs.update(func(s *services) error {
+		var err error
+		purged, err = s.idempotency.Purge(s.clock.Now())
+		return err
+	})
+	return purged, err
+}
@@ -0,0 +1,145 @@
+package bank
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDGenerator issues the IDs and reference numbers of new transactions.
+// Implementations must be safe for concurrent use and never issue the same
+// value twice.
+type IDGenerator interface {
+	NewTransactionID() string
+	NewReferenceNumber() string
+}
+
+// WithIDGenerator replaces the default generator, which is a snowflake
+// generator for node 0.
+func WithIDGenerator(ids IDGenerator) Option {
+	return func(bs *BankingSystem) {
+		bs.ids = ids
+	}
+}
+
+// Snowflake IDs are 64-bit numbers made of a millisecond timestamp, the node
+// that issued them and a per-millisecond sequence, in that order, so they
+// sort by the time they were issued.
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	// MaxNodeID is the largest node ID a snowflake generator accepts.
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+)
+
+// snowflakeEpoch is the start of snowflake time. 41 bits of milliseconds
+// last until 2094.
+var snowflakeEpoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type snowflakeGenerator struct {
+	mu   sync.Mutex
+	node int64
+	// last is the timestamp, in milliseconds since snowflakeEpoch, of the
+	// most recent ID, and sequence is how many IDs had been issued in it.
+	last     int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator returns a generator of snowflake IDs for one node.
+// Nodes that share a store must have different IDs, from 0 to MaxNodeID.
+//
+// IDs from one node always increase, even if the wall clock steps back or
+// more than 4096 are issued in a millisecond: the generator then carries on
+// from the last timestamp it used.
+func NewSnowflakeGenerator(node int) (IDGenerator, error) {
+	if node < 0 || node > MaxNodeID {
+		return nil, fmt.Errorf("%w: node ID %d is not between 0 and %d", ErrInvalidInput, node, MaxNodeID)
+	}
+	return &snowflakeGenerator{node: int64(node)}, nil
+}
+
+func (g *snowflakeGenerator) next() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Since(snowflakeEpoch).Milliseconds()
+	switch {
+	case now > g.last:
+		g.last, g.sequence = now, 0
+	case g.sequence < maxSequence:
+		g.sequence++
+	default:
+		g.last, g.sequence = g.last+1, 0
+	}
+	return uint64(g.last)<<(nodeBits+sequenceBits) | uint64(g.node)<<sequenceBits | uint64(g.sequence)
+}
+
+// NewTransactionID returns "TXN" and 13 base32 digits, which sort in the
+// order the IDs were issued.
+func (g *snowflakeGenerator) NewTransactionID() string {
+	return "TXN" + encodeBase32(g.next())
+}
+
+// NewReferenceNumber returns a reference number meant to be read out and
+// typed by people, such as "REF-01JQ-8Z2K-3M4N-0P": base32 digits that
+// avoid the easily confused letters I, L, O and U, in groups of four, with
+// a final check digit. See ValidReferenceNumber.
+func (g *snowflakeGenerator) NewReferenceNumber() string {
+	digits := encodeBase32(g.next())
+	digits += string(base32Alphabet[checkDigit(digits)])
+
+	var b strings.Builder
+	b.WriteString("REF")
+	for i := 0; i < len(digits); i += 4 {
+		b.WriteByte('-')
+		b.WriteString(digits[i:min(i+4, len(digits))])
+	}
+	return b.String()
+}
+
+// base32Alphabet is Crockford's base32 alphabet.
+const base32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// encodeBase32 writes n as 13 base32 digits, most significant first.
+func encodeBase32(n uint64) string {
+	var digits [13]byte
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = base32Alphabet[n&31]
+		n >>= 5
+	}
+	return string(digits[:])
+}
+
+// checkDigit computes the Luhn mod 32 check digit of base32 digits, which
+// catches any single mistyped digit and most swaps of adjacent digits.
+func checkDigit(digits string) int {
+	sum, factor := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(base32Alphabet, digits[i])
+		sum += addend/32 + addend%32
+		factor = 3 - factor
+	}
+	return (32 - sum%32) % 32
+}
+
+// ValidReferenceNumber reports whether s is a well-formed reference number
+// whose check digit is right. Case and hyphens are ignored, and I, L and O
+// are read as 1, 1 and 0, as people often type them.
+func ValidReferenceNumber(s string) bool {
+	s, ok := strings.CutPrefix(strings.ToUpper(s), "REF")
+	if !ok {
+		return false
+	}
+	s = strings.NewReplacer("-", "", "I", "1", "L", "1", "O", "0").Replace(s)
+	if len(s) != 14 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(base32Alphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return checkDigit(s[:13]) == strings.IndexByte(base32Alphabet, s[13])
+}
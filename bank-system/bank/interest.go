@@ -0,0 +1,363 @@
+package bank
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// InterestMethod decides whether interest earns interest before it is
+// credited.
+type InterestMethod int
+
+const (
+	// SimpleInterest accrues on each day's closing balance only. Interest
+	// earns interest once it has been credited to the account.
+	SimpleInterest InterestMethod = iota
+	// CompoundInterest also accrues on the interest accrued earlier in the
+	// crediting period, compounding daily.
+	CompoundInterest
+)
+
+// CreditingFrequency is how often accrued interest is paid into the
+// account. Periods follow the calendar, so quarterly interest is credited
+// at the end of March, June, September and December.
+type CreditingFrequency int
+
+const (
+	CreditMonthly CreditingFrequency = iota
+	CreditQuarterly
+)
+
+// InterestTier is one balance slab of an interest product.
+type InterestTier struct {
+	// From is the balance at which the slab starts.
+	From Money
+	// Rate is the annual rate, such as 0.027 for 2.7%.
+	Rate *big.Rat
+}
+
+// InterestProduct describes the interest paid on one type of account.
+type InterestProduct struct {
+	Method    InterestMethod
+	Crediting CreditingFrequency
+	// Tiers are in ascending order of From, starting at zero. Each slab's
+	// part of the balance earns the slab's rate, so with slabs at 0 and 10
+	// lakh a balance of 12 lakh earns the first rate on 10 lakh and the
+	// second on 2 lakh.
+	Tiers []InterestTier
+	// OverdraftRate, if set, is the annual rate charged on a negative
+	// balance. It accrues and is debited alongside the credit interest.
+	OverdraftRate *big.Rat
+}
+
+// interestDayCount is the number of days in the year used to turn annual
+// rates into daily ones.
+const interestDayCount = 365
+
+// DefaultInterestProducts pays interest on savings accounts and charges it
+// monthly on overdrawn current accounts.
+var DefaultInterestProducts = map[string]InterestProduct{
+	"Savings": {
+		Method:    SimpleInterest,
+		Crediting: CreditQuarterly,
+		Tiers: []InterestTier{
+			{From: Zero(INR), Rate: MustParsePercent("2.70")},
+			{From: MustParseMoney("1000000", INR), Rate: MustParsePercent("3.00")},
+		},
+	},
+	"Current": {
+		Method:        SimpleInterest,
+		Crediting:     CreditMonthly,
+		OverdraftRate: MustParsePercent("12.00"),
+	},
+}
+
+// WithInterestProducts replaces DefaultInterestProducts. Products are keyed
+// by Account.AccountType; other accounts earn no interest.
+func WithInterestProducts(products map[string]InterestProduct) Option {
+	return func(bs *BankingSystem) {
+		bs.interestProducts = products
+	}
+}
+
+// MustParsePercent parses a percentage such as "2.70" or "2.70%" into a
+// rate. It panics on error and is intended for constants and tests.
+func MustParsePercent(s string) *big.Rat {
+	rate, ok := new(big.Rat).SetString(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if !ok {
+		panic(fmt.Sprintf("bank: invalid percentage %q", s))
+	}
+	return rate.Quo(rate, big.NewRat(100, 1))
+}
+
+// dailyInterest returns the interest, in minor units, that balance earns
+// in one day.
+func (p InterestProduct) dailyInterest(balance *big.Rat, currency Currency) (*big.Rat, error) {
+	interest := new(big.Rat)
+	if balance.Sign() <= 0 {
+		return interest, nil
+	}
+
+	for i, tier := range p.Tiers {
+		if c := tier.From.Currency(); c != "" && c != currency {
+			return nil, fmt.Errorf("%w: interest tier in %s for a %s account", ErrCurrencyMismatch, c, currency)
+		}
+
+		portion := new(big.Rat).Sub(balance, big.NewRat(tier.From.MinorUnits(), 1))
+		if portion.Sign() <= 0 {
+			break
+		}
+		if i+1 < len(p.Tiers) {
+			width := big.NewRat(p.Tiers[i+1].From.MinorUnits()-tier.From.MinorUnits(), 1)
+			if portion.Cmp(width) > 0 {
+				portion = width
+			}
+		}
+
+		portion.Mul(portion, tier.Rate)
+		interest.Add(interest, portion)
+	}
+	return interest.Quo(interest, big.NewRat(interestDayCount, 1)), nil
+}
+
+// dailyOverdraftInterest returns the interest, in minor units, charged on
+// an overdrawn amount for one day.
+func (p InterestProduct) dailyOverdraftInterest(overdrawn *big.Rat) *big.Rat {
+	if p.OverdraftRate == nil || overdrawn.Sign() <= 0 {
+		return new(big.Rat)
+	}
+	interest := new(big.Rat).Mul(overdrawn, p.OverdraftRate)
+	return interest.Quo(interest, big.NewRat(interestDayCount, 1))
+}
+
+// periodEnd returns the start of the crediting period after the one that
+// contains day.
+func (f CreditingFrequency) periodEnd(day time.Time) time.Time {
+	month := day.Month() + 1
+	if f == CreditQuarterly {
+		month = (day.Month()-1)/3*3 + 4
+	}
+	return time.Date(day.Year(), month, 1, 0, 0, 0, 0, day.Location())
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// InterestRun reports what AccrueInterest did.
+type InterestRun struct {
+	AsOf time.Time
+	// Credits are the interest payments the run posted, and Charges the
+	// overdraft interest it debited.
+	Credits []*Transaction
+	Charges []*Transaction
+	// Accrued is the interest each account has earned in its current
+	// crediting period, and AccruedCharges the overdraft interest it owes.
+	// Both are posted by the first run after the period ends.
+	Accrued        map[string]Money
+	AccruedCharges map[string]Money
+}
+
+// AccrueInterest accrues interest on every open account with an interest
+// product for each day that ended by asOf, and posts it for each crediting
+// period that ended by asOf. Overdraft interest is debited even if that
+// takes the account beyond its limit. Days run midnight to midnight in
+// asOf's location.
+//
+// Each period's interest is posted as of the period's end, so a run that
+// catches up on several periods gives the same result as one run per
+// period. Each account remembers the end of the last period it was credited
+// for, so running again for the same period posts nothing. Interest that
+// rounds to zero is forfeited.
+func (bs *BankingSystem) AccrueInterest(asOf time.Time) (*InterestRun, error) {
+	if err := bs.authorize(ActionRunBatch, bankResource); err != nil {
+		return nil, err
+	}
+	if asOf.After(bs.clock.Now()) {
+		return nil, fmt.Errorf("%w: cannot accrue interest for days that have not ended", ErrInvalidInput)
+	}
+
+	accounts, err := bs.accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	run := &InterestRun{AsOf: asOf, Accrued: make(map[string]Money), AccruedCharges: make(map[string]Money)}
+	for _, account := range accounts {
+		product, ok := bs.interestProducts[account.AccountType]
+		if !ok || account.Status == StatusClosed {
+			continue
+		}
+
+		err := func() error {
+			unlock := bs.accountLocks.lock(account.AccountNumber)
+			defer unlock()
+
+			return bs.update(func(s *services) error {
+				posted, accrued, owed, err := s.accrueInterest(account.AccountNumber, product, asOf)
+				if err != nil {
+					return err
+				}
+				for _, transaction := range posted {
+					if transaction.FromAccount != "" {
+						run.Charges = append(run.Charges, transaction)
+					} else {
+						run.Credits = append(run.Credits, transaction)
+					}
+				}
+				if accrued.IsPositive() {
+					run.Accrued[account.AccountNumber] = accrued
+				}
+				if owed.IsPositive() {
+					run.AccruedCharges[account.AccountNumber] = owed
+				}
+				return nil
+			})
+		}()
+		if err != nil {
+			return run, fmt.Errorf("accrue interest on account %s: %w", account.AccountNumber, err)
+		}
+	}
+	return run, nil
+}
+
+// accrueInterest accrues interest on one account from the end of its last
+// credited period up to asOf. It posts the interest for each period that
+// has ended and returns those transactions, along with the interest accrued
+// and the overdraft interest owed in the period that has not. The caller
+// must hold the account's lock.
+func (s *services) accrueInterest(accountNumber string, product InterestProduct, asOf time.Time) ([]*Transaction, Money, Money, error) {
+	account, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return nil, Money{}, Money{}, err
+	}
+
+	loc := asOf.Location()
+	start := account.InterestCreditedUntil
+	if start.IsZero() {
+		start = account.CreatedAt
+	}
+	day := startOfDay(start.In(loc))
+	end := startOfDay(asOf)
+
+	entries, err := s.ledger.GetEntries(accountNumber)
+	if err != nil {
+		return nil, Money{}, Money{}, err
+	}
+	balances := newBalanceHistory(entries, accountNumber)
+	round := func(r *big.Rat) (Money, error) {
+		return NewMoney(1, account.Currency).MulRat(r, RoundHalfUp)
+	}
+
+	var posted []*Transaction
+	for day.Before(end) {
+		periodStart := day
+		periodEnd := product.Crediting.periodEnd(day)
+
+		accrued, owed := new(big.Rat), new(big.Rat)
+		for ; day.Before(periodEnd) && day.Before(end); day = day.AddDate(0, 0, 1) {
+			balance := balances.closing(day.AddDate(0, 0, 1))
+			overdrawn := new(big.Rat).Neg(balance)
+			if product.Method == CompoundInterest {
+				balance.Add(balance, accrued)
+				overdrawn.Add(overdrawn, owed)
+			}
+
+			interest, err := product.dailyInterest(balance, account.Currency)
+			if err != nil {
+				return nil, Money{}, Money{}, err
+			}
+			accrued.Add(accrued, interest)
+			owed.Add(owed, product.dailyOverdraftInterest(overdrawn))
+		}
+
+		credit, err := round(accrued)
+		if err != nil {
+			return nil, Money{}, Money{}, err
+		}
+		charge, err := round(owed)
+		if err != nil {
+			return nil, Money{}, Money{}, err
+		}
+		if day.Before(periodEnd) {
+			// The period is still running.
+			return posted, credit, charge, nil
+		}
+
+		period := fmt.Sprintf("%s to %s", periodStart.Format("02 Jan 2006"), periodEnd.AddDate(0, 0, -1).Format("02 Jan 2006"))
+		// The next period accrues on the balance including this one's
+		// interest.
+		if credit.IsPositive() {
+			transaction, err := s.postAt(periodEnd, Interest, "", accountNumber, credit, "Interest "+period, interestLines(accountNumber, credit))
+			if err != nil {
+				return nil, Money{}, Money{}, err
+			}
+			posted = append(posted, transaction)
+			balances.add(credit.MinorUnits())
+		}
+		if charge.IsPositive() {
+			transaction, err := s.postAt(periodEnd, Interest, accountNumber, "", charge, "Overdraft interest "+period, overdraftInterestLines(accountNumber, charge))
+			if err != nil {
+				return nil, Money{}, Money{}, err
+			}
+			posted = append(posted, transaction)
+			balances.add(-charge.MinorUnits())
+		}
+		if err := s.accounts.MarkInterestCredited(accountNumber, periodEnd); err != nil {
+			return nil, Money{}, Money{}, err
+		}
+	}
+	return posted, Zero(account.Currency), Zero(account.Currency), nil
+}
+
+// balanceHistory replays an account's journal lines to find its balance at
+// successive times.
+type balanceHistory struct {
+	changes []balanceChange
+	next    int
+	balance int64
+}
+
+type balanceChange struct {
+	at     time.Time
+	amount int64
+}
+
+func newBalanceHistory(entries []JournalEntry, account string) *balanceHistory {
+	h := &balanceHistory{}
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			if line.Account != account {
+				continue
+			}
+			amount := line.Amount.MinorUnits()
+			if line.Side != NormalSide(account) {
+				amount = -amount
+			}
+			h.changes = append(h.changes, balanceChange{at: entry.Timestamp, amount: amount})
+		}
+	}
+	slices.SortStableFunc(h.changes, func(a, b balanceChange) int {
+		return a.at.Compare(b.at)
+	})
+	return h
+}
+
+// closing returns the balance, in minor units, just before t. Successive
+// calls must not go back in time.
+func (h *balanceHistory) closing(t time.Time) *big.Rat {
+	for h.next < len(h.changes) && h.changes[h.next].at.Before(t) {
+		h.balance += h.changes[h.next].amount
+		h.next++
+	}
+	return big.NewRat(h.balance, 1)
+}
+
+// add applies a change posted after the history was read, as of the last
+// time passed to closing.
+func (h *balanceHistory) add(amount int64) {
+	h.balance += amount
+}
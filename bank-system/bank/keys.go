@@ -0,0 +1,202 @@
+package bank
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	ErrUnknownKey    = errors.New("unknown encryption key")
+	ErrNoKeyProvider = errors.New("data is encrypted but no key provider is configured")
+)
+
+// KeyProvider holds the key-encryption keys that protect the per-user data
+// keys used for field-level encryption. Implementations may keep the keys
+// locally or delegate to a key management service.
+type KeyProvider interface {
+	// CurrentKeyID names the key that Wrap uses.
+	CurrentKeyID() string
+	// Wrap encrypts a data key under the current key and returns that
+	// key's ID along with the result.
+	Wrap(dataKey []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap decrypts a data key that was wrapped under keyID. It returns
+	// ErrUnknownKey if the provider does not hold that key.
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyRotator is a KeyProvider that can create a new current key itself.
+// Old keys stay available to Unwrap until every record has been
+// re-encrypted.
+type KeyRotator interface {
+	KeyProvider
+	Rotate() (keyID string, err error)
+}
+
+// KeyFile is a KeyProvider that keeps its keys in a local JSON file. It is
+// meant for development and tests; production deployments should use a key
+// management service.
+type KeyFile struct {
+	mu   sync.RWMutex
+	path string
+	data keyFileData
+}
+
+type keyFileData struct {
+	Current string            `json:"current"`
+	Keys    map[string][]byte `json:"keys"`
+}
+
+// OpenKeyFile loads the keys in path, creating the file with a fresh key if
+// it does not exist.
+func OpenKeyFile(path string) (*KeyFile, error) {
+	kf := &KeyFile{path: path}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		kf.data.Keys = make(map[string][]byte)
+		if _, err := kf.Rotate(); err != nil {
+			return nil, err
+		}
+		return kf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &kf.data); err != nil {
+		return nil, fmt.Errorf("read key file %s: %w", path, err)
+	}
+	if len(kf.data.Keys[kf.data.Current]) != 32 {
+		return nil, fmt.Errorf("read key file %s: %w %q", path, ErrUnknownKey, kf.data.Current)
+	}
+	return kf, nil
+}
+
+func (kf *KeyFile) CurrentKeyID() string {
+	kf.mu.RLock()
+	defer kf.mu.RUnlock()
+
+	return kf.data.Current
+}
+
+func (kf *KeyFile) Wrap(dataKey []byte) (string, []byte, error) {
+	kf.mu.RLock()
+	keyID, key := kf.data.Current, kf.data.Keys[kf.data.Current]
+	kf.mu.RUnlock()
+
+	wrapped, err := seal(key, dataKey, []byte(keyID))
+	if err != nil {
+		return "", nil, err
+	}
+	return keyID, wrapped, nil
+}
+
+func (kf *KeyFile) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	kf.mu.RLock()
+	key, ok := kf.data.Keys[keyID]
+	kf.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	return unseal(key, wrapped, []byte(keyID))
+}
+
+// Rotate adds a new key, makes it current and saves the file.
+func (kf *KeyFile) Rotate() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	kf.mu.Lock()
+	defer kf.mu.Unlock()
+
+	keyID := fmt.Sprintf("k%d", len(kf.data.Keys)+1)
+	kf.data.Keys[keyID] = key
+	previous := kf.data.Current
+	kf.data.Current = keyID
+
+	if err := kf.save(); err != nil {
+		delete(kf.data.Keys, keyID)
+		kf.data.Current = previous
+		return "", err
+	}
+	return keyID, nil
+}
+
+// save replaces the key file atomically, so a crash never leaves it
+// without the keys that existing records need.
+func (kf *KeyFile) save() error {
+	raw, err := json.MarshalIndent(kf.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(kf.path), ".keys-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), kf.path)
+}
+
+// seal encrypts plaintext with AES-256-GCM and returns the nonce followed by
+// the ciphertext.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func unseal(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
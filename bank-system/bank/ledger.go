@@ -0,0 +1,292 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type EntrySide string
+
+const (
+	Debit  EntrySide = "DEBIT"
+	Credit EntrySide = "CREDIT"
+)
+
+// Internal general-ledger accounts. Customer accounts are liabilities of the
+// bank and therefore carry credit balances; these carry whatever balance is
+// natural for them.
+const (
+	GLCashInVault     = "GL-CASH-IN-VAULT"
+	GLFeeIncome       = "GL-FEE-INCOME"
+	GLInterestExpense = "GL-INTEREST-EXPENSE"
+	GLInterestIncome  = "GL-INTEREST-INCOME"
+)
+
+var glNormalSides = map[string]EntrySide{
+	GLCashInVault:     Debit,
+	GLFeeIncome:       Credit,
+	GLInterestExpense: Debit,
+	GLInterestIncome:  Credit,
+}
+
+// NormalSide returns the side on which the account's balance increases.
+func NormalSide(account string) EntrySide {
+	if side, ok := glNormalSides[account]; ok {
+		return side
+	}
+	return Credit
+}
+
+type JournalLine struct {
+	Account string
+	Side    EntrySide
+	Amount  Money
+}
+
+type JournalEntry struct {
+	ID            string
+	Sequence      int64
+	TransactionID string
+	Timestamp     time.Time
+	Description   string
+	Lines         []JournalLine
+}
+
+type Ledger interface {
+	Post(entry JournalEntry) (*JournalEntry, error)
+	GetBalance(account string) (Money, error)
+	GetEntries(account string) ([]JournalEntry, error)
+	GetAllEntries() ([]JournalEntry, error)
+	TrialBalance() (*TrialBalance, error)
+}
+
+type TrialBalanceRow struct {
+	Account string
+	Debits  Money
+	Credits Money
+}
+
+type TrialBalance struct {
+	Rows         []TrialBalanceRow
+	TotalDebits  Money
+	TotalCredits Money
+	GeneratedAt  time.Time
+}
+
+var (
+	ErrUnbalancedEntry = errors.New("journal entry is unbalanced")
+	ErrEmptyEntry      = errors.New("journal entry has no lines")
+)
+
+type ledger struct {
+	repo  JournalRepository
+	clock Clock
+}
+
+func NewLedger(repo JournalRepository, clock Clock) Ledger {
+	return &ledger{
+		repo:  repo,
+		clock: clock,
+	}
+}
+
+func validateEntry(entry JournalEntry) error {
+	if len(entry.Lines) < 2 {
+		return ErrEmptyEntry
+	}
+
+	var debits, credits Money
+	for _, line := range entry.Lines {
+		if line.Account == "" || !line.Amount.IsPositive() {
+			return fmt.Errorf("%w: invalid line %+v", ErrInvalidInput, line)
+		}
+
+		var err error
+		switch line.Side {
+		case Debit:
+			debits, err = debits.Add(line.Amount)
+		case Credit:
+			credits, err = credits.Add(line.Amount)
+		default:
+			err = fmt.Errorf("%w: unknown side %q", ErrInvalidInput, line.Side)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if c, err := debits.Cmp(credits); err != nil || c != 0 {
+		return fmt.Errorf("%w: debits %s, credits %s", ErrUnbalancedEntry, debits, credits)
+	}
+	return nil
+}
+
+// Post validates that the entry's debits equal its credits and appends it to
+// the journal.
+func (l *ledger) Post(entry JournalEntry) (*JournalEntry, error) {
+	if err := validateEntry(entry); err != nil {
+		return nil, err
+	}
+
+	// Make sure no account total would overflow before writing anything.
+	for _, line := range entry.Lines {
+		totals, err := l.repo.Totals(line.Account)
+		if err != nil {
+			return nil, err
+		}
+		if line.Side == Debit {
+			_, err = totals.Debits.Add(line.Amount)
+		} else {
+			_, err = totals.Credits.Add(line.Amount)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seq, err := l.repo.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Sequence = seq
+	entry.ID = fmt.Sprintf("JE%08d", entry.Sequence)
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = l.clock.Now()
+	}
+	entry.Lines = slices.Clone(entry.Lines)
+
+	if err := l.repo.Append(entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetBalance returns the account's balance on its normal side, so a funded
+// customer account and a full cash vault both report positive amounts.
+func (l *ledger) GetBalance(account string) (Money, error) {
+	totals, err := l.repo.Totals(account)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if NormalSide(account) == Debit {
+		return totals.Debits.Sub(totals.Credits)
+	}
+	return totals.Credits.Sub(totals.Debits)
+}
+
+// GetEntries returns the entries with a line on account, in posting order.
+func (l *ledger) GetEntries(account string) ([]JournalEntry, error) {
+	return l.repo.ListByAccount(account)
+}
+
+func (l *ledger) GetAllEntries() ([]JournalEntry, error) {
+	return l.repo.List()
+}
+
+func (l *ledger) TrialBalance() (*TrialBalance, error) {
+	allTotals, err := l.repo.AllTotals()
+	if err != nil {
+		return nil, err
+	}
+
+	tb := &TrialBalance{GeneratedAt: l.clock.Now()}
+	for account, totals := range allTotals {
+		tb.Rows = append(tb.Rows, TrialBalanceRow{
+			Account: account,
+			Debits:  totals.Debits,
+			Credits: totals.Credits,
+		})
+		if tb.TotalDebits, err = tb.TotalDebits.Add(totals.Debits); err != nil {
+			return nil, err
+		}
+		if tb.TotalCredits, err = tb.TotalCredits.Add(totals.Credits); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(tb.Rows, func(a, b TrialBalanceRow) int {
+		return strings.Compare(a.Account, b.Account)
+	})
+
+	return tb, nil
+}
+
+// IsBalanced reports whether total debits equal total credits.
+func (tb TrialBalance) IsBalanced() bool {
+	return tb.TotalDebits.Equal(tb.TotalCredits)
+}
+
+func (tb TrialBalance) DisplayTrialBalance() {
+	fmt.Println("\n=== Trial Balance ===")
+	fmt.Printf("%-22s %18s %18s\n", "Account", "Debits", "Credits")
+	for _, row := range tb.Rows {
+		fmt.Printf("%-22s %18s %18s\n", row.Account, row.Debits, row.Credits)
+	}
+	fmt.Printf("%-22s %18s %18s\n", "TOTAL", tb.TotalDebits, tb.TotalCredits)
+	if tb.IsBalanced() {
+		fmt.Println("Ledger is balanced.")
+	} else {
+		fmt.Println("WARNING: Ledger is NOT balanced!")
+	}
+	fmt.Println("---------------------------")
+}
+
+func depositLines(accountNumber string, amount Money) []JournalLine {
+	return []JournalLine{
+		{Account: GLCashInVault, Side: Debit, Amount: amount},
+		{Account: accountNumber, Side: Credit, Amount: amount},
+	}
+}
+
+func withdrawalLines(accountNumber string, amount Money) []JournalLine {
+	return []JournalLine{
+		{Account: accountNumber, Side: Debit, Amount: amount},
+		{Account: GLCashInVault, Side: Credit, Amount: amount},
+	}
+}
+
+func feeLines(accountNumber string, amount Money) []JournalLine {
+	return []JournalLine{
+		{Account: accountNumber, Side: Debit, Amount: amount},
+		{Account: GLFeeIncome, Side: Credit, Amount: amount},
+	}
+}
+
+func interestLines(accountNumber string, amount Money) []JournalLine {
+	return []JournalLine{
+		{Account: GLInterestExpense, Side: Debit, Amount: amount},
+		{Account: accountNumber, Side: Credit, Amount: amount},
+	}
+}
+
+func overdraftInterestLines(accountNumber string, amount Money) []JournalLine {
+	return []JournalLine{
+		{Account: accountNumber, Side: Debit, Amount: amount},
+		{Account: GLInterestIncome, Side: Credit, Amount: amount},
+	}
+}
+
+// reversalLines returns lines that undo lines.
+func reversalLines(lines []JournalLine) []JournalLine {
+	reversed := make([]JournalLine, len(lines))
+	for i, line := range lines {
+		reversed[i] = line
+		reversed[i].Side = Credit
+		if line.Side == Credit {
+			reversed[i].Side = Debit
+		}
+	}
+	return reversed
+}
+
+func transferLines(fromAccount, toAccount string, amount Money) []JournalLine {
+	return []JournalLine{
+		{Account: fromAccount, Side: Debit, Amount: amount},
+		{Account: toAccount, Side: Credit, Amount: amount},
+	}
+}
@@ -0,0 +1,184 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid account status")
+	ErrInvalidTransition = errors.New("account status change not allowed")
+)
+
+// AccountStatus is where an account is in its lifecycle. It decides which
+// operations the account allows.
+type AccountStatus string
+
+const (
+	// StatusPendingKYC accounts are waiting for the holder's identity to
+	// be verified. They accept deposits but nothing leaves them.
+	StatusPendingKYC AccountStatus = "PendingKYC"
+	StatusActive     AccountStatus = "Active"
+	// StatusFrozen accounts allow no customer operations at all.
+	StatusFrozen AccountStatus = "Frozen"
+	// StatusDebitFrozen accounts accept money but do not pay it out.
+	StatusDebitFrozen AccountStatus = "DebitFrozen"
+	// StatusDormant accounts have not been used for a long time. Money may
+	// come in, but must not go out until the holder reactivates the account.
+	StatusDormant AccountStatus = "Dormant"
+	// StatusClosed is final.
+	StatusClosed AccountStatus = "Closed"
+)
+
+// AccountStatuses lists every account status.
+var AccountStatuses = []AccountStatus{StatusPendingKYC, StatusActive, StatusFrozen, StatusDebitFrozen, StatusDormant, StatusClosed}
+
+// WithInitialAccountStatus sets the status accounts open in, which must be
+// StatusActive, the default, or StatusPendingKYC for a bank that verifies
+// the holder's identity after opening the account.
+func WithInitialAccountStatus(status AccountStatus) Option {
+	return func(bs *BankingSystem) {
+		bs.initialStatus = status
+	}
+}
+
+func ParseAccountStatus(s string) (AccountStatus, error) {
+	for _, status := range AccountStatuses {
+		if strings.EqualFold(s, string(status)) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidStatus, s)
+}
+
+// Operation is a customer-initiated movement of money as seen by one of
+// the accounts it touches.
+type Operation string
+
+const (
+	OperationDeposit     Operation = "deposit"
+	OperationWithdraw    Operation = "withdrawal"
+	OperationTransferIn  Operation = "incoming transfer"
+	OperationTransferOut Operation = "outgoing transfer"
+)
+
+// accountTransitions lists the statuses each status may change to. Closing
+// an account also requires a zero balance.
+var accountTransitions = map[AccountStatus][]AccountStatus{
+	StatusPendingKYC:  {StatusActive, StatusClosed},
+	StatusActive:      {StatusFrozen, StatusDebitFrozen, StatusDormant, StatusClosed},
+	StatusFrozen:      {StatusActive, StatusDebitFrozen},
+	StatusDebitFrozen: {StatusActive, StatusFrozen, StatusClosed},
+	StatusDormant:     {StatusActive, StatusFrozen, StatusClosed},
+	StatusClosed:      {},
+}
+
+// accountOperations lists the operations each status allows. Interest and
+// charges the bank levies are posted to any account that is not closed.
+var accountOperations = map[AccountStatus][]Operation{
+	StatusPendingKYC:  {OperationDeposit},
+	StatusActive:      {OperationDeposit, OperationWithdraw, OperationTransferIn, OperationTransferOut},
+	StatusFrozen:      {},
+	StatusDebitFrozen: {OperationDeposit, OperationTransferIn},
+	StatusDormant:     {OperationDeposit, OperationTransferIn},
+	StatusClosed:      {},
+}
+
+// CanBecome reports whether an account may change from s to status.
+func (s AccountStatus) CanBecome(status AccountStatus) bool {
+	return slices.Contains(accountTransitions[s], status)
+}
+
+// Allows reports whether an account with status s allows op.
+func (s AccountStatus) Allows(op Operation) bool {
+	return slices.Contains(accountOperations[s], op)
+}
+
+// debitOperations and creditOperations say which operation each transaction
+// type is for the account money leaves and the account it goes to.
+var (
+	debitOperations = map[TransactionType]Operation{
+		Withdrawal: OperationWithdraw,
+		Transfer:   OperationTransferOut,
+	}
+	creditOperations = map[TransactionType]Operation{
+		Deposit:  OperationDeposit,
+		Transfer: OperationTransferIn,
+	}
+)
+
+// checkOperation returns ErrAccountNotActive if the account's status does
+// not allow op.
+func (s *services) checkOperation(accountNumber string, op Operation) error {
+	account, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return err
+	}
+	if !account.Status.Allows(op) {
+		return fmt.Errorf("account %s is %s and does not allow a %s: %w", accountNumber, account.Status, op, ErrAccountNotActive)
+	}
+	return nil
+}
+
+// ChangeAccountStatus moves an account to another status, recording why
+// and who made the change. The change must be in the transition table.
+func (bs *BankingSystem) ChangeAccountStatus(accountNumber string, status AccountStatus, reason string) error {
+	if err := bs.authorize(ActionChangeAccountStatus, accountResource(accountNumber)); err != nil {
+		return err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	return bs.update(func(s *services) error {
+		return s.changeStatus(accountNumber, status, reason, AuditChangeAccountStatus)
+	})
+}
+
+// FreezeAccount stops all customer operations on an account.
+func (bs *BankingSystem) FreezeAccount(accountNumber, reason string) error {
+	return bs.ChangeAccountStatus(accountNumber, StatusFrozen, reason)
+}
+
+// UnfreezeAccount makes a frozen or debit-frozen account active again.
+func (bs *BankingSystem) UnfreezeAccount(accountNumber, reason string) error {
+	if err := bs.authorize(ActionChangeAccountStatus, accountResource(accountNumber)); err != nil {
+		return err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	return bs.update(func(s *services) error {
+		account, err := s.accounts.GetAccountDetails(accountNumber)
+		if err != nil {
+			return err
+		}
+		if account.Status != StatusFrozen && account.Status != StatusDebitFrozen {
+			return fmt.Errorf("%w: account %s is %s, not frozen", ErrInvalidTransition, accountNumber, account.Status)
+		}
+		return s.changeStatus(accountNumber, StatusActive, reason, AuditChangeAccountStatus)
+	})
+}
+
+// changeStatus changes an account's status on behalf of the unit of work's
+// actor and records it in the audit trail under action. The caller must
+// hold the account's lock.
+func (s *services) changeStatus(accountNumber string, status AccountStatus, reason, action string) error {
+	before, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return err
+	}
+	if err := s.accounts.ChangeStatus(accountNumber, status, reason, s.audit.actor); err != nil {
+		return err
+	}
+	after, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	s.audit.record(action, "account:"+accountNumber, before, after)
+	return nil
+}
@@ -0,0 +1,84 @@
+package bank
+
+import (
+	"slices"
+	"sync"
+)
+
+// lockTable hands out one mutex per key. Callers that need several keys must
+// go through lock so that every goroutine acquires them in the same order.
+//
+// An entry lives only while some goroutine holds or waits for its lock, so
+// keys that are used once, such as idempotency keys, do not accumulate.
+type lockTable struct {
+	mu    sync.Mutex
+	locks map[string]*lockEntry
+}
+
+type lockEntry struct {
+	mu sync.Mutex
+	// refs counts the goroutines holding or waiting for mu. It is guarded
+	// by the table's mutex.
+	refs int
+}
+
+func newLockTable() *lockTable {
+	return &lockTable{
+		locks: make(map[string]*lockEntry),
+	}
+}
+
+// acquire returns the entry for key, creating it if needed, and counts the
+// caller as one of its users.
+func (lt *lockTable) acquire(key string) *lockEntry {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	e, exists := lt.locks[key]
+	if !exists {
+		e = &lockEntry{}
+		lt.locks[key] = e
+	}
+	e.refs++
+	return e
+}
+
+// release unlocks the entry for key and removes it once nobody else holds
+// or waits for it.
+func (lt *lockTable) release(key string, e *lockEntry) {
+	e.mu.Unlock()
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	e.refs--
+	if e.refs == 0 {
+		delete(lt.locks, key)
+	}
+}
+
+// lock acquires the locks for all keys in sorted order and returns a function
+// that releases them. Empty and duplicate keys are ignored.
+func (lt *lockTable) lock(keys ...string) func() {
+	sorted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			sorted = append(sorted, key)
+		}
+	}
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	held := make([]*lockEntry, 0, len(sorted))
+	for _, key := range sorted {
+		e := lt.acquire(key)
+		e.mu.Lock()
+		held = append(held, e)
+	}
+
+	return func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			lt.release(sorted[i], held[i])
+		}
+	}
+}
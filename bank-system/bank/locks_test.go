@@ -0,0 +1,92 @@
+package bank
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// size returns how many keys have an entry.
+func (lt *lockTable) size() int {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return len(lt.locks)
+}
+
+func TestLockTableExcludesAndForgetsKeys(t *testing.T) {
+	lt := newLockTable()
+
+	// Each counter is only ever touched under its key's lock, so the race
+	// detector and the final counts catch a lock that does not exclude.
+	counters := make([]int, 8)
+	var wg sync.WaitGroup
+	for g := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				a, b := (g+i)%len(counters), (g*7+i)%len(counters)
+				unlock := lt.lock(fmt.Sprint(a), fmt.Sprint(b), "", fmt.Sprint(a))
+				counters[a]++
+				if b != a {
+					counters[b]++
+				}
+				unlock()
+
+				// A key used once, like an idempotency key.
+				lt.lock(fmt.Sprintf("once-%d-%d", g, i))()
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range counters {
+		total += n
+	}
+	want := 0
+	for g := range 32 {
+		for i := range 500 {
+			if (g+i)%len(counters) == (g*7+i)%len(counters) {
+				want++
+			} else {
+				want += 2
+			}
+		}
+	}
+	if total != want {
+		t.Errorf("counters total %d, want %d", total, want)
+	}
+	if n := lt.size(); n != 0 {
+		t.Errorf("lock table still has %d entries after every lock was released", n)
+	}
+}
+
+func TestLockTableKeepsEntryWhileWaiting(t *testing.T) {
+	lt := newLockTable()
+	unlock := lt.lock("a")
+
+	acquired := make(chan func())
+	go func() { acquired <- lt.lock("a") }()
+
+	// Wait until the second caller has registered for the entry.
+	for {
+		lt.mu.Lock()
+		refs := lt.locks["a"].refs
+		lt.mu.Unlock()
+		if refs == 2 {
+			break
+		}
+		runtime.Gosched()
+	}
+	unlock()
+	unlockSecond := <-acquired
+	if n := lt.size(); n != 1 {
+		t.Errorf("lock table has %d entries while a is held, want 1", n)
+	}
+	unlockSecond()
+	if n := lt.size(); n != 0 {
+		t.Errorf("lock table has %d entries after a was released, want 0", n)
+	}
+}
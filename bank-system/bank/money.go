@@ -0,0 +1,430 @@
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const (
+	INR Currency = "INR"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	JPY Currency = "JPY"
+)
+
+// DefaultCurrency is used for accounts that do not specify one.
+const DefaultCurrency = INR
+
+type currencyInfo struct {
+	exponent int
+	symbol   string
+}
+
+var currencies = map[Currency]currencyInfo{
+	INR: {exponent: 2, symbol: "₹"},
+	USD: {exponent: 2, symbol: "$"},
+	EUR: {exponent: 2, symbol: "€"},
+	GBP: {exponent: 2, symbol: "£"},
+	JPY: {exponent: 0, symbol: "¥"},
+}
+
+var (
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrMoneyOverflow    = errors.New("money amount overflow")
+)
+
+func (c Currency) Valid() bool {
+	_, ok := currencies[c]
+	return ok
+}
+
+// Exponent returns the number of minor-unit digits, e.g. 2 for INR (paise).
+func (c Currency) Exponent() int {
+	return currencies[c].exponent
+}
+
+func (c Currency) Symbol() string {
+	if info, ok := currencies[c]; ok {
+		return info.symbol
+	}
+	if c == "" {
+		return ""
+	}
+	return string(c) + " "
+}
+
+// RoundingMode selects how an amount with more precision than the currency
+// allows is brought back to whole minor units.
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota
+	RoundHalfUp
+	RoundHalfDown
+	RoundDown
+	RoundUp
+	RoundFloor
+	RoundCeiling
+)
+
+func (r RoundingMode) String() string {
+	switch r {
+	case RoundHalfEven:
+		return "HALF_EVEN"
+	case RoundHalfUp:
+		return "HALF_UP"
+	case RoundHalfDown:
+		return "HALF_DOWN"
+	case RoundDown:
+		return "DOWN"
+	case RoundUp:
+		return "UP"
+	case RoundFloor:
+		return "FLOOR"
+	case RoundCeiling:
+		return "CEILING"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(r))
+}
+
+// Money is an exact amount held as integer minor units of a currency.
+// The zero value is a zero amount with no currency and can be combined
+// with money of any currency.
+type Money struct {
+	units    int64
+	currency Currency
+}
+
+func NewMoney(minorUnits int64, currency Currency) Money {
+	return Money{units: minorUnits, currency: currency}
+}
+
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// MustParseMoney is like ParseMoney but panics on error. It is intended for
+// constants and tests.
+func MustParseMoney(s string, currency Currency) Money {
+	m, err := ParseMoney(s, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// ParseMoney parses a decimal string such as "1250.50" or "-3". It rejects
+// input with more fractional digits than the currency has minor units.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	return parseMoney(s, currency, nil)
+}
+
+// ParseMoneyRound parses a decimal string, rounding any excess fractional
+// digits with the given mode.
+func ParseMoneyRound(s string, currency Currency, mode RoundingMode) (Money, error) {
+	return parseMoney(s, currency, &mode)
+}
+
+func parseMoney(s string, currency Currency, mode *RoundingMode) (Money, error) {
+	if !currency.Valid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, currency.Symbol())
+	s = strings.ReplaceAll(s, ",", "")
+
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	exp := currency.Exponent()
+	if len(frac) > exp && mode == nil {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidMoney, s, exp)
+	}
+
+	digits := whole + frac
+	if digits == "" {
+		digits = "0"
+	}
+	num, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	if negative {
+		num.Neg(num)
+	}
+
+	// num is scaled by 10^len(frac); rescale to 10^exp.
+	r := new(big.Rat).SetFrac(num, pow10(len(frac)))
+	return NewMoney(1, currency).MulRat(r.Mul(r, new(big.Rat).SetInt(pow10(exp))), roundingOrDefault(mode))
+}
+
+func roundingOrDefault(mode *RoundingMode) RoundingMode {
+	if mode == nil {
+		return RoundHalfEven
+	}
+	return *mode
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (m Money) MinorUnits() int64 {
+	return m.units
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.units > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+// Sign returns -1, 0 or +1.
+func (m Money) Sign() int {
+	switch {
+	case m.units < 0:
+		return -1
+	case m.units > 0:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) resolve(other Money) (Currency, error) {
+	switch {
+	case m.currency == other.currency:
+		return m.currency, nil
+	case m.currency == "" && m.units == 0:
+		return other.currency, nil
+	case other.currency == "" && other.units == 0:
+		return m.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.resolve(other)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.units + other.units
+	if (other.units > 0 && sum < m.units) || (other.units < 0 && sum > m.units) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{units: sum, currency: currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	neg, err := other.Neg()
+	if err != nil {
+		return Money{}, err
+	}
+	return m.Add(neg)
+}
+
+func (m Money) Neg() (Money, error) {
+	if m.units == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{units: -m.units, currency: m.currency}, nil
+}
+
+func (m Money) Abs() (Money, error) {
+	if m.units < 0 {
+		return m.Neg()
+	}
+	return m, nil
+}
+
+// Mul multiplies the amount by an integer factor.
+func (m Money) Mul(factor int64) (Money, error) {
+	if m.units == 0 || factor == 0 {
+		return Money{currency: m.currency}, nil
+	}
+	product := m.units * factor
+	if product/factor != m.units || (m.units == -1 && factor == math.MinInt64) || (factor == -1 && m.units == math.MinInt64) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{units: product, currency: m.currency}, nil
+}
+
+// MulRat multiplies the amount by an exact rational factor, such as an
+// interest rate, rounding the result to whole minor units.
+func (m Money) MulRat(factor *big.Rat, mode RoundingMode) (Money, error) {
+	num := new(big.Int).Mul(big.NewInt(m.units), factor.Num())
+	den := factor.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 && roundAway(quo, rem, den, num.Sign(), mode) {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	if !quo.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{units: quo.Int64(), currency: m.currency}, nil
+}
+
+// Div divides the amount by an integer divisor, rounding with mode.
+func (m Money) Div(divisor int64, mode RoundingMode) (Money, error) {
+	if divisor == 0 {
+		return Money{}, fmt.Errorf("%w: division by zero", ErrInvalidMoney)
+	}
+	return m.MulRat(big.NewRat(1, divisor), mode)
+}
+
+// roundAway reports whether a truncated quotient should move one unit away
+// from zero given its remainder.
+func roundAway(quo, rem, den *big.Int, sign int, mode RoundingMode) bool {
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(den)
+
+	switch mode {
+	case RoundDown:
+		return false
+	case RoundUp:
+		return true
+	case RoundFloor:
+		return sign < 0
+	case RoundCeiling:
+		return sign > 0
+	case RoundHalfUp:
+		return half >= 0
+	case RoundHalfDown:
+		return half > 0
+	default:
+		if half == 0 {
+			return quo.Bit(0) == 1
+		}
+		return half > 0
+	}
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1.
+func (m Money) Cmp(other Money) (int, error) {
+	if _, err := m.resolve(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.units < other.units:
+		return -1, nil
+	case m.units > other.units:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) Equal(other Money) bool {
+	c, err := m.Cmp(other)
+	return err == nil && c == 0
+}
+
+// Amount formats the value as a plain decimal string, e.g. "-1250.50".
+func (m Money) Amount() string {
+	exp := m.currency.Exponent()
+	units := m.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+
+	digits := strconv.FormatUint(absUnits(units), 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+func absUnits(units int64) uint64 {
+	if units < 0 {
+		return uint64(-(units + 1)) + 1
+	}
+	return uint64(units)
+}
+
+func (m Money) String() string {
+	amount := m.Amount()
+	if strings.HasPrefix(amount, "-") {
+		return "-" + m.currency.Symbol() + amount[1:]
+	}
+	return m.currency.Symbol() + amount
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount(), Currency: m.currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Currency == "" && (raw.Amount == "" || raw.Amount == "0") {
+		*m = Money{}
+		return nil
+	}
+	parsed, err := ParseMoney(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,189 @@
+package bank_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"bank-system/bank"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency bank.Currency
+		units    int64
+		err      error
+	}{
+		{"1250.50", bank.INR, 125050, nil},
+		{"1250.5", bank.INR, 125050, nil},
+		{"-3", bank.INR, -300, nil},
+		{"+3", bank.INR, 300, nil},
+		{" 7 ", bank.INR, 700, nil},
+		{"0.01", bank.INR, 1, nil},
+		{".5", bank.INR, 50, nil},
+		{"5.", bank.INR, 500, nil},
+		{"-0", bank.INR, 0, nil},
+		{"₹1,000.25", bank.INR, 100025, nil},
+		{"$12.34", bank.USD, 1234, nil},
+		{"1500", bank.JPY, 1500, nil},
+		{"92233720368547758.07", bank.INR, math.MaxInt64, nil},
+
+		{"", bank.INR, 0, bank.ErrInvalidMoney},
+		{".", bank.INR, 0, bank.ErrInvalidMoney},
+		{"-", bank.INR, 0, bank.ErrInvalidMoney},
+		{"abc", bank.INR, 0, bank.ErrInvalidMoney},
+		{"1.2.3", bank.INR, 0, bank.ErrInvalidMoney},
+		{"1e3", bank.INR, 0, bank.ErrInvalidMoney},
+		{"--1", bank.INR, 0, bank.ErrInvalidMoney},
+		{"1 000", bank.INR, 0, bank.ErrInvalidMoney},
+		{"0x10", bank.INR, 0, bank.ErrInvalidMoney},
+		{"1.001", bank.INR, 0, bank.ErrInvalidMoney},
+		{"1.5", bank.JPY, 0, bank.ErrInvalidMoney},
+		{"92233720368547758.08", bank.INR, 0, bank.ErrMoneyOverflow},
+		{"1", "XYZ", 0, bank.ErrUnknownCurrency},
+		{"1", "", 0, bank.ErrUnknownCurrency},
+	}
+	for _, tc := range tests {
+		m, err := bank.ParseMoney(tc.in, tc.currency)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("ParseMoney(%q, %s) = %v, %v; want %v", tc.in, tc.currency, m, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || m.MinorUnits() != tc.units || m.Currency() != tc.currency {
+			t.Errorf("ParseMoney(%q, %s) = %d %s, %v; want %d", tc.in, tc.currency, m.MinorUnits(), m.Currency(), err, tc.units)
+		}
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	inputs := []string{"1.005", "1.015", "1.006", "1.004", "-1.005", "-1.015", "-1.006"}
+	tests := []struct {
+		mode bank.RoundingMode
+		want []int64
+	}{
+		{bank.RoundHalfEven, []int64{100, 102, 101, 100, -100, -102, -101}},
+		{bank.RoundHalfUp, []int64{101, 102, 101, 100, -101, -102, -101}},
+		{bank.RoundHalfDown, []int64{100, 101, 101, 100, -100, -101, -101}},
+		{bank.RoundDown, []int64{100, 101, 100, 100, -100, -101, -100}},
+		{bank.RoundUp, []int64{101, 102, 101, 101, -101, -102, -101}},
+		{bank.RoundFloor, []int64{100, 101, 100, 100, -101, -102, -101}},
+		{bank.RoundCeiling, []int64{101, 102, 101, 101, -100, -101, -100}},
+	}
+	for _, tc := range tests {
+		for i, in := range inputs {
+			m, err := bank.ParseMoneyRound(in, bank.INR, tc.mode)
+			if err != nil || m.MinorUnits() != tc.want[i] {
+				t.Errorf("ParseMoneyRound(%q, %s) = %d, %v; want %d", in, tc.mode, m.MinorUnits(), err, tc.want[i])
+			}
+		}
+	}
+
+	// Div rounds its quotient the same way.
+	third, err := inr("1").Div(3, bank.RoundUp)
+	if err != nil || third.MinorUnits() != 34 {
+		t.Errorf("₹1 / 3 rounded up = %d, %v; want 34", third.MinorUnits(), err)
+	}
+	if _, err := inr("1").Div(0, bank.RoundHalfEven); !errors.Is(err, bank.ErrInvalidMoney) {
+		t.Errorf("dividing by zero = %v, want ErrInvalidMoney", err)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	largest := bank.NewMoney(math.MaxInt64, bank.INR)
+	smallest := bank.NewMoney(math.MinInt64, bank.INR)
+	one := bank.NewMoney(1, bank.INR)
+
+	if _, err := largest.Add(one); !errors.Is(err, bank.ErrMoneyOverflow) {
+		t.Errorf("max + 1 = %v, want ErrMoneyOverflow", err)
+	}
+	if _, err := smallest.Sub(one); !errors.Is(err, bank.ErrMoneyOverflow) {
+		t.Errorf("min - 1 = %v, want ErrMoneyOverflow", err)
+	}
+	if _, err := one.Sub(smallest); !errors.Is(err, bank.ErrMoneyOverflow) {
+		t.Errorf("1 - min = %v, want ErrMoneyOverflow", err)
+	}
+	if _, err := smallest.Neg(); !errors.Is(err, bank.ErrMoneyOverflow) {
+		t.Errorf("-min = %v, want ErrMoneyOverflow", err)
+	}
+	for _, factor := range []int64{2, -2, math.MaxInt64, math.MinInt64} {
+		if _, err := largest.Mul(factor); !errors.Is(err, bank.ErrMoneyOverflow) {
+			t.Errorf("max * %d = %v, want ErrMoneyOverflow", factor, err)
+		}
+	}
+	if _, err := smallest.Mul(-1); !errors.Is(err, bank.ErrMoneyOverflow) {
+		t.Errorf("min * -1 = %v, want ErrMoneyOverflow", err)
+	}
+
+	sum, err := largest.Add(smallest)
+	if err != nil || sum.MinorUnits() != -1 {
+		t.Errorf("max + min = %d, %v; want -1", sum.MinorUnits(), err)
+	}
+	product, err := inr("12.50").Mul(-3)
+	if err != nil || !product.Equal(inr("-37.50")) {
+		t.Errorf("₹12.50 * -3 = %s, %v; want -₹37.50", product, err)
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	rupees, dollars := inr("1"), bank.MustParseMoney("1", bank.USD)
+
+	if _, err := rupees.Add(dollars); !errors.Is(err, bank.ErrCurrencyMismatch) {
+		t.Errorf("₹1 + $1 = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := rupees.Sub(dollars); !errors.Is(err, bank.ErrCurrencyMismatch) {
+		t.Errorf("₹1 - $1 = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := rupees.Cmp(dollars); !errors.Is(err, bank.ErrCurrencyMismatch) {
+		t.Errorf("comparing ₹1 with $1 = %v, want ErrCurrencyMismatch", err)
+	}
+	if rupees.Equal(dollars) {
+		t.Error("₹1 equals $1")
+	}
+
+	// The zero value has no currency and combines with any.
+	sum, err := bank.Money{}.Add(dollars)
+	if err != nil || sum.Currency() != bank.USD {
+		t.Errorf("zero + $1 = %s, %v; want $1.00", sum, err)
+	}
+	if _, err := bank.Zero(bank.INR).Add(dollars); !errors.Is(err, bank.ErrCurrencyMismatch) {
+		t.Errorf("₹0 + $1 = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		m    bank.Money
+		json string
+	}{
+		{inr("1250.50"), `{"amount":"1250.50","currency":"INR"}`},
+		{inr("-0.05"), `{"amount":"-0.05","currency":"INR"}`},
+		{bank.MustParseMoney("1500", bank.JPY), `{"amount":"1500","currency":"JPY"}`},
+		{bank.Zero(bank.USD), `{"amount":"0.00","currency":"USD"}`},
+		{bank.Money{}, `{"amount":"0","currency":""}`},
+	}
+	for _, tc := range tests {
+		data, err := json.Marshal(tc.m)
+		if err != nil || string(data) != tc.json {
+			t.Errorf("Marshal(%s) = %s, %v; want %s", tc.m, data, err, tc.json)
+			continue
+		}
+		var back bank.Money
+		if err := json.Unmarshal(data, &back); err != nil || back != tc.m {
+			t.Errorf("Unmarshal(%s) = %#v, %v; want %#v", data, back, err, tc.m)
+		}
+	}
+
+	var m bank.Money
+	if err := json.Unmarshal([]byte(`{"amount":"","currency":""}`), &m); err != nil || m != (bank.Money{}) {
+		t.Errorf("Unmarshal of an empty amount = %#v, %v; want the zero value", m, err)
+	}
+	for _, bad := range []string{`{"amount":"1","currency":""}`, `{"amount":"1.001","currency":"INR"}`, `{"amount":"x","currency":"INR"}`, `[]`} {
+		if err := json.Unmarshal([]byte(bad), &m); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", bad)
+		}
+	}
+}
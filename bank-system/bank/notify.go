@@ -0,0 +1,53 @@
+package bank
+
+import "time"
+
+// NotificationKind says what a Notification is about.
+type NotificationKind string
+
+const (
+	// NotifyOverdraftBreached is sent when an account is overdrawn beyond
+	// its sanctioned limit, for example by interest or a reduced limit.
+	NotifyOverdraftBreached NotificationKind = "overdraft_breached"
+)
+
+// Notification tells the bank or a customer about something that needs
+// their attention.
+type Notification struct {
+	Kind          NotificationKind
+	AccountNumber string
+	Message       string
+	Time          time.Time
+}
+
+// Notifier delivers notifications, for example by email or SMS. Notify is
+// called after the change that caused the notification has been committed,
+// and must not call back into the banking system.
+type Notifier interface {
+	Notify(n Notification)
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(n Notification)
+
+func (f NotifierFunc) Notify(n Notification) {
+	f(n)
+}
+
+// WithNotifier sets where notifications go. Without one they are dropped.
+func WithNotifier(notifier Notifier) Option {
+	return func(bs *BankingSystem) {
+		bs.notifier = notifier
+	}
+}
+
+// notify queues a notification to be delivered when the unit of work
+// commits.
+func (s *services) notify(kind NotificationKind, accountNumber, message string) {
+	s.notifications = append(s.notifications, Notification{
+		Kind:          kind,
+		AccountNumber: accountNumber,
+		Message:       message,
+		Time:          s.clock.Now(),
+	})
+}
@@ -0,0 +1,61 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrOverdraftNotAllowed = errors.New("overdrafts are only available on current accounts")
+
+// SetOverdraftLimit sanctions an overdraft on a current account, letting
+// its balance go as far below zero as limit. A zero limit withdraws the
+// facility. Lowering the limit below what is already overdrawn is allowed
+// and sends a breach notification.
+func (bs *BankingSystem) SetOverdraftLimit(accountNumber string, limit Money) error {
+	if err := bs.authorize(ActionSetOverdraft, accountResource(accountNumber)); err != nil {
+		return err
+	}
+
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	return bs.update(func(s *services) error {
+		before, err := s.accounts.GetAccountDetails(accountNumber)
+		if err != nil {
+			return err
+		}
+		if err := s.accounts.SetOverdraftLimit(accountNumber, limit); err != nil {
+			return err
+		}
+		after, err := s.accounts.GetAccountDetails(accountNumber)
+		if err != nil {
+			return err
+		}
+
+		s.audit.record(AuditSetOverdraft, "account:"+accountNumber, before, after)
+		return s.checkOverdraft(accountNumber)
+	})
+}
+
+// checkOverdraft sends a breach notification if the account is overdrawn
+// beyond its limit.
+func (s *services) checkOverdraft(accountNumber string) error {
+	account, err := s.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return err
+	}
+
+	available, err := account.AvailableBalance()
+	if err != nil {
+		return err
+	}
+	if available.IsNegative() {
+		overdrawn, err := account.Balance.Abs()
+		if err != nil {
+			return err
+		}
+		s.notify(NotifyOverdraftBreached, accountNumber,
+			fmt.Sprintf("Account %s is overdrawn by %s against a limit of %s", accountNumber, overdrawn, account.OverdraftLimit))
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package bank_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"bank-system/bank"
+)
+
+// cheapParams keep hashing fast in tests.
+var cheapParams = bank.PasswordParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}
+
+func TestPasswordHashAndVerify(t *testing.T) {
+	hasher := bank.NewPasswordHasher(cheapParams)
+
+	hash, err := hasher.Hash("S3cure!Passw0rd")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("hash %q is not an argon2id PHC string with the hasher's parameters", hash)
+	}
+	if again, _ := hasher.Hash("S3cure!Passw0rd"); again == hash {
+		t.Error("two hashes of the same password are equal; the salt is not random")
+	}
+
+	if ok, needsRehash, err := hasher.Verify(hash, "S3cure!Passw0rd"); !ok || needsRehash || err != nil {
+		t.Errorf("Verify of the right password = %v, %v, %v; want true, false, nil", ok, needsRehash, err)
+	}
+	for _, wrong := range []string{"s3cure!Passw0rd", "S3cure!Passw0rd ", ""} {
+		if ok, _, err := hasher.Verify(hash, wrong); ok || err != nil {
+			t.Errorf("Verify(%q) = %v, %v; want false, nil", wrong, ok, err)
+		}
+	}
+	if ok, _, err := hasher.Verify("", "anything"); ok || err != nil {
+		t.Errorf("Verify against no hash = %v, %v; want false, nil", ok, err)
+	}
+
+	// Anything but an argon2id PHC string is refused, plaintext included.
+	salt, key := "c2FsdHNhbHQ", "a2V5a2V5a2V5a2V5a2V5aw"
+	for _, bad := range []string{
+		"S3cure!Passw0rd",
+		"$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+		"$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=one,p=1$" + salt + "$" + key,
+		"$argon2id$v=19$m=64,t=1,p=1$not base64!$" + key,
+		"$argon2id$v=19$m=64,t=1,p=1$" + salt + "$not base64!",
+		"$argon2id$v=19$m=64,t=1,p=1$" + salt,
+	} {
+		if ok, _, err := hasher.Verify(bad, "S3cure!Passw0rd"); ok || err == nil {
+			t.Errorf("Verify against %q = %v, %v; want an error", bad, ok, err)
+		}
+	}
+}
+
+func TestPasswordRehash(t *testing.T) {
+	old := bank.NewPasswordHasher(cheapParams)
+	hash, err := old.Hash("S3cure!Passw0rd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stronger := cheapParams
+	stronger.Iterations = 2
+	for name, params := range map[string]bank.PasswordParams{
+		"iterations": stronger,
+		"memory":     {Memory: 128, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16},
+		"salt":       {Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 16},
+		"key":        {Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 32},
+	} {
+		ok, needsRehash, err := bank.NewPasswordHasher(params).Verify(hash, "S3cure!Passw0rd")
+		if !ok || !needsRehash || err != nil {
+			t.Errorf("%s changed: Verify = %v, %v, %v; want true, true, nil", name, ok, needsRehash, err)
+		}
+	}
+
+	// A login with the new parameters replaces the old hash, and only a
+	// successful one does.
+	store := bank.NewMemoryStore()
+	bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithPasswordParams(cheapParams))
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	storedHash := func() string {
+		t.Helper()
+		stored, err := store.Users().Get(user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stored.PasswordHash
+	}
+	before := storedHash()
+
+	upgraded := bank.NewBankingSystem(bank.WithStore(store), bank.WithPasswordParams(stronger))
+	if _, err := upgraded.VerifyPassword("test.user@example.com", "wrong"); !errors.Is(err, bank.ErrInvalidCredentials) {
+		t.Fatalf("VerifyPassword with the wrong password = %v, want ErrInvalidCredentials", err)
+	}
+	if storedHash() != before {
+		t.Error("a failed login rewrote the hash")
+	}
+	if _, err := upgraded.VerifyPassword("test.user@example.com", "S3cure!Passw0rd"); err != nil {
+		t.Fatalf("VerifyPassword: %v", err)
+	}
+	after := storedHash()
+	if !strings.HasPrefix(after, "$argon2id$v=19$m=64,t=2,p=1$") {
+		t.Errorf("hash after login %q, want one with t=2", after)
+	}
+	if _, err := upgraded.VerifyPassword("test.user@example.com", "S3cure!Passw0rd"); err != nil || storedHash() != after {
+		t.Errorf("a second login changed the upgraded hash (%v)", err)
+	}
+}
+
+func TestPlaintextPasswordIsRefused(t *testing.T) {
+	store := bank.NewMemoryStore()
+	bs := bank.NewBankingSystem(bank.WithStore(store), bank.WithPasswordParams(cheapParams))
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	stored, err := store.Users().Get(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.PasswordHash = "S3cure!Passw0rd"
+	if err := store.Users().Save(*stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bs.VerifyPassword("test.user@example.com", "S3cure!Passw0rd"); !errors.Is(err, bank.ErrInternal) {
+		t.Errorf("VerifyPassword against a plaintext password = %v, want ErrInternal", err)
+	}
+	if _, err := bs.Login("test.user@example.com", "S3cure!Passw0rd"); err == nil {
+		t.Error("Login succeeded against a plaintext password")
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		// broken lists the rules the password breaks, as Check names them.
+		broken []string
+	}{
+		{"strong", "S3cure!Passw0rd", nil},
+		{"exactly the minimum length", "S3cure!Pas", nil},
+		{"too short", "S3cure!Pa", []string{"at least 10 characters"}},
+		{"too long", "S3cure!Pa" + strings.Repeat("s", 120), []string{"at most 128 characters"}},
+		{"length counts characters, not bytes", "Ünïcö!9xé", []string{"at least 10 characters"}},
+		{"no uppercase", "s3cure!passw0rd", []string{"an uppercase letter"}},
+		{"no lowercase", "S3CURE!PASSW0RD", []string{"a lowercase letter"}},
+		{"no digit", "Secure!Password", []string{"a digit"}},
+		{"no symbol", "S3curePassw0rd", []string{"a symbol"}},
+		{"symbols include currency signs", "S3curePassw0rd₹", nil},
+		{"a space is not a symbol", "S3cure Passw0rd", []string{"a symbol"}},
+		{"everything wrong", "", []string{"at least 10 characters", "an uppercase letter", "a lowercase letter", "a digit", "a symbol"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := bank.DefaultPasswordPolicy.Check(tc.password)
+			if len(tc.broken) == 0 {
+				if err != nil {
+					t.Errorf("Check = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, bank.ErrWeakPassword) {
+				t.Fatalf("Check = %v, want ErrWeakPassword", err)
+			}
+			if want := "needs " + strings.Join(tc.broken, ", "); !strings.HasSuffix(err.Error(), want) {
+				t.Errorf("Check = %q, want it to end %q", err, want)
+			}
+		})
+	}
+
+	// A policy only checks the rules it sets.
+	lenient := bank.PasswordPolicy{MinLength: 4}
+	if err := lenient.Check("abcd" + strings.Repeat("e", 500)); err != nil {
+		t.Errorf("lenient Check = %v, want nil", err)
+	}
+
+	// The configured policy applies to new users and to changed passwords.
+	bs := bank.NewBankingSystem(bank.WithPasswordParams(cheapParams), bank.WithPasswordPolicy(bank.PasswordPolicy{MinLength: 20}))
+	if _, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346"); !errors.Is(err, bank.ErrWeakPassword) {
+		t.Fatalf("CreateUser with a short password = %v, want ErrWeakPassword", err)
+	}
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "a very long passphrase",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if err := bs.ChangePassword(user.ID, "a very long passphrase", "too short"); !errors.Is(err, bank.ErrWeakPassword) {
+		t.Errorf("ChangePassword to a short password = %v, want ErrWeakPassword", err)
+	}
+	if err := bs.ChangePassword(user.ID, "wrong", "another long passphrase"); !errors.Is(err, bank.ErrInvalidCredentials) {
+		t.Errorf("ChangePassword with the wrong current password = %v, want ErrInvalidCredentials", err)
+	}
+	if err := bs.ChangePassword(user.ID, "a very long passphrase", "another long passphrase"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := bs.VerifyPassword("test.user@example.com", "another long passphrase"); err != nil {
+		t.Errorf("VerifyPassword with the new password: %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package bank
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix marks a field value that is ciphertext rather than
+// plaintext written before encryption was enabled.
+const encryptedPrefix = "enc:"
+
+// piiFields are the User fields encrypted at rest.
+func piiFields(u *User) map[string]*string {
+	return map[string]*string{
+		"address":            &u.Address,
+		"phone":              &u.Phone,
+		"pan_card_number":    &u.PanCardNumber,
+		"aadhar_card_number": &u.AadharCardNumber,
+	}
+}
+
+// encryptedUserRepository is a UserRepository that encrypts each user's
+// PII with a data key of its own, kept in User.DataKey wrapped by the key
+// provider (envelope encryption). Other fields, such as the email that
+// users are looked up by, stay in plaintext.
+//
+// Without a key provider, users are stored in plaintext and encrypted
+// records cannot be read.
+type encryptedUserRepository struct {
+	UserRepository
+	keys KeyProvider
+}
+
+func newEncryptedUserRepository(repo UserRepository, keys KeyProvider) UserRepository {
+	return encryptedUserRepository{UserRepository: repo, keys: keys}
+}
+
+func (r encryptedUserRepository) Get(id int) (*User, error) {
+	user, err := r.UserRepository.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return user, r.decrypt(user)
+}
+
+func (r encryptedUserRepository) GetByEmail(email string) (*User, error) {
+	user, err := r.UserRepository.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return user, r.decrypt(user)
+}
+
+func (r encryptedUserRepository) List() ([]User, error) {
+	users, err := r.UserRepository.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if err := r.decrypt(&users[i]); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// Save encrypts the user under their existing data key, or under a new one
+// if they have none yet or theirs is wrapped by a key that is no longer
+// current.
+func (r encryptedUserRepository) Save(user User) error {
+	if r.keys == nil {
+		return r.UserRepository.Save(user)
+	}
+
+	if keyID, _, _ := strings.Cut(user.DataKey, ":"); keyID != r.keys.CurrentKeyID() {
+		user.DataKey = ""
+	}
+
+	dataKey, err := r.dataKey(&user)
+	if err != nil {
+		return err
+	}
+
+	for field, value := range piiFields(&user) {
+		if *value == "" {
+			continue
+		}
+		sealed, err := seal(dataKey, []byte(*value), fieldAAD(user.ID, field))
+		if err != nil {
+			return fmt.Errorf("encrypt user %d %s: %w", user.ID, field, err)
+		}
+		*value = encryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed)
+	}
+	return r.UserRepository.Save(user)
+}
+
+// dataKey returns the user's data key, creating and wrapping a new one if
+// the user has none.
+func (r encryptedUserRepository) dataKey(user *User) ([]byte, error) {
+	if user.DataKey != "" {
+		return r.unwrap(user)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	keyID, wrapped, err := r.keys.Wrap(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key for user %d: %w", user.ID, err)
+	}
+	user.DataKey = keyID + ":" + base64.RawStdEncoding.EncodeToString(wrapped)
+	return dataKey, nil
+}
+
+func (r encryptedUserRepository) unwrap(user *User) ([]byte, error) {
+	if r.keys == nil {
+		return nil, ErrNoKeyProvider
+	}
+
+	keyID, encoded, _ := strings.Cut(user.DataKey, ":")
+	wrapped, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("data key of user %d: %w", user.ID, err)
+	}
+
+	dataKey, err := r.keys.Unwrap(keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key of user %d: %w", user.ID, err)
+	}
+	return dataKey, nil
+}
+
+// decrypt replaces the user's encrypted fields with their plaintext.
+// Plaintext fields from before encryption was enabled are left as they are
+// and get encrypted the next time the user is saved.
+func (r encryptedUserRepository) decrypt(user *User) error {
+	if user.DataKey == "" {
+		return nil
+	}
+
+	dataKey, err := r.unwrap(user)
+	if err != nil {
+		return err
+	}
+
+	for field, value := range piiFields(user) {
+		encoded, ok := strings.CutPrefix(*value, encryptedPrefix)
+		if !ok {
+			continue
+		}
+
+		sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("decrypt user %d %s: %w", user.ID, field, err)
+		}
+		plaintext, err := unseal(dataKey, sealed, fieldAAD(user.ID, field))
+		if err != nil {
+			return fmt.Errorf("decrypt user %d %s: %w", user.ID, field, err)
+		}
+		*value = string(plaintext)
+	}
+	return nil
+}
+
+// fieldAAD binds a ciphertext to its user and field, so it cannot be moved
+// to another record or column.
+func fieldAAD(userID int, field string) []byte {
+	return fmt.Appendf(nil, "user/%d/%s", userID, field)
+}
+
+// Masked returns a copy of u with its PII masked for display, for example
+// Aadhaar "XXXX-XXXX-2346" and PAN "XXXXXX234F".
+func (u User) Masked() User {
+	u.Phone = maskAllButLast(u.Phone, 4)
+	u.PanCardNumber = maskAllButLast(u.PanCardNumber, 4)
+	if len(u.AadharCardNumber) == 12 {
+		u.AadharCardNumber = "XXXX-XXXX-" + u.AadharCardNumber[8:]
+	} else {
+		u.AadharCardNumber = maskAllButLast(u.AadharCardNumber, 4)
+	}
+
+	// Keep the last part of the address, usually the state, and hide the
+	// rest.
+	if i := strings.LastIndex(u.Address, ","); i >= 0 {
+		u.Address = "XXXX" + u.Address[i:]
+	} else if u.Address != "" {
+		u.Address = "XXXX"
+	}
+
+	u.unmasked = false
+	return u
+}
+
+func maskAllButLast(s string, n int) string {
+	if len(s) <= n {
+		return strings.Repeat("X", len(s))
+	}
+	return strings.Repeat("X", len(s)-n) + s[len(s)-n:]
+}
+
+// forDisplay is u as DisplayUserInfo and String render it.
+func (u User) forDisplay() User {
+	if u.unmasked {
+		return u
+	}
+	return u.Masked()
+}
+
+// revealPII returns users as the caller may see them: in full if they hold
+// the unmask permission for that user and masked otherwise.
+func (bs *BankingSystem) revealPII(users ...User) ([]User, error) {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, user := range users {
+		if allowed(ActionUnmaskPII, userResource(user.ID)) == nil {
+			users[i].unmasked = true
+		} else {
+			users[i] = user.Masked()
+		}
+	}
+	return users, nil
+}
+
+func (bs *BankingSystem) revealUser(user *User) (*User, error) {
+	users, err := bs.revealPII(*user)
+	if err != nil {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// RotateEncryptionKey makes a new key current if the key provider can
+// rotate keys itself, then re-encrypts every user whose data key is wrapped
+// by an older key, or who is not encrypted yet. It returns the number of
+// users re-encrypted. Old keys must stay available until it succeeds.
+func (bs *BankingSystem) RotateEncryptionKey() (int, error) {
+	if err := bs.authorize(ActionRotateKeys, bankResource); err != nil {
+		return 0, err
+	}
+	if bs.keys == nil {
+		return 0, ErrNoKeyProvider
+	}
+
+	if rotator, ok := bs.keys.(KeyRotator); ok {
+		if _, err := rotator.Rotate(); err != nil {
+			return 0, fmt.Errorf("rotate key: %w", err)
+		}
+	}
+	current := bs.keys.CurrentKeyID()
+
+	users, err := bs.users.List()
+	if err != nil {
+		return 0, err
+	}
+
+	reencrypted := 0
+	for _, user := range users {
+		if keyID, _, _ := strings.Cut(user.DataKey, ":"); keyID == current {
+			continue
+		}
+
+		err := func() error {
+			unlock := bs.accountLocks.lock(userLockKey(user.ID))
+			defer unlock()
+
+			return bs.update(func(s *services) error {
+				user, err := s.users.Get(user.ID)
+				if err != nil {
+					return err
+				}
+				return s.users.Update(*user)
+			})
+		}()
+		if err != nil {
+			return reencrypted, fmt.Errorf("re-encrypt user %d: %w", user.ID, err)
+		}
+		reencrypted++
+	}
+	return reencrypted, nil
+}
@@ -0,0 +1,324 @@
+package bank
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+const (
+	// DefaultPageSize is how many transactions QueryTransactions returns
+	// when the filter sets no limit.
+	DefaultPageSize = 50
+	// MaxPageSize caps the limit of a filter.
+	MaxPageSize = 500
+)
+
+// TransactionFilter selects transactions for QueryTransactions. Zero fields
+// match every transaction; the rest must all match.
+type TransactionFilter struct {
+	// AccountNumber matches transactions from or to the account.
+	AccountNumber string
+	// UserID matches transactions from or to any of the user's accounts.
+	UserID   int
+	Types    []TransactionType
+	Statuses []TransactionStatus
+	// MinAmount and MaxAmount bound the amount, inclusively. Transactions
+	// in other currencies do not match.
+	MinAmount *Money
+	MaxAmount *Money
+	// Since and Until bound the timestamp: Since inclusively, Until
+	// exclusively.
+	Since time.Time
+	Until time.Time
+	// Text matches a case-insensitive substring of the description or the
+	// reference number.
+	Text string
+	// ReferenceNumber matches the transactions with this reference number,
+	// ignoring case: the original and any reversal of it.
+	ReferenceNumber string
+
+	// NewestFirst reverses the order, which is otherwise oldest first.
+	// Transactions with the same timestamp are ordered by ID.
+	NewestFirst bool
+	// Limit is the largest number of transactions to return. Zero means
+	// DefaultPageSize.
+	Limit int
+	// Cursor continues from the page that returned it.
+	Cursor string
+}
+
+// TransactionPage is one page of the result of QueryTransactions.
+type TransactionPage struct {
+	Transactions []*Transaction
+	// NextCursor fetches the next page when set as the filter's Cursor. It
+	// is empty on the last page.
+	NextCursor string
+}
+
+// TransactionQuery is a TransactionFilter as a TransactionRepository runs it,
+// with users resolved to their accounts and the cursor decoded.
+type TransactionQuery struct {
+	// Accounts, if not empty, matches transactions from or to any of them.
+	Accounts  []string
+	Types     []TransactionType
+	Statuses  []TransactionStatus
+	MinAmount *Money
+	MaxAmount *Money
+	Since     time.Time
+	Until     time.Time
+	Text      string
+	// ReferenceNumber is upper case.
+	ReferenceNumber string
+	NewestFirst     bool
+	// After, if set, skips the transactions up to and including this one
+	// in the query's order.
+	After *TransactionPosition
+	// Limit is the largest number of transactions to return; zero means no
+	// limit.
+	Limit int
+}
+
+// TransactionPosition is where a transaction falls in the order of a query.
+type TransactionPosition struct {
+	Timestamp time.Time
+	ID        string
+}
+
+func positionOf(t *Transaction) TransactionPosition {
+	return TransactionPosition{Timestamp: t.Timestamp, ID: t.ID}
+}
+
+// Compare orders positions oldest first, then by ID.
+func (p TransactionPosition) Compare(other TransactionPosition) int {
+	if c := p.Timestamp.Compare(other.Timestamp); c != 0 {
+		return c
+	}
+	return strings.Compare(p.ID, other.ID)
+}
+
+// Matches reports whether a transaction is selected by the query's filters.
+// It ignores After and Limit.
+func (q TransactionQuery) Matches(t *Transaction) bool {
+	switch {
+	case len(q.Accounts) > 0 && !slices.Contains(q.Accounts, t.FromAccount) && !slices.Contains(q.Accounts, t.ToAccount):
+		return false
+	case len(q.Types) > 0 && !slices.Contains(q.Types, t.Type):
+		return false
+	case len(q.Statuses) > 0 && !slices.Contains(q.Statuses, t.Status):
+		return false
+	case !q.Since.IsZero() && t.Timestamp.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !t.Timestamp.Before(q.Until):
+		return false
+	case q.ReferenceNumber != "" && t.ReferenceNumber != q.ReferenceNumber:
+		return false
+	}
+	if q.MinAmount != nil {
+		if c, err := t.Amount.Cmp(*q.MinAmount); err != nil || c < 0 {
+			return false
+		}
+	}
+	if q.MaxAmount != nil {
+		if c, err := t.Amount.Cmp(*q.MaxAmount); err != nil || c > 0 {
+			return false
+		}
+	}
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		return strings.Contains(strings.ToLower(t.Description), text) || strings.Contains(strings.ToLower(t.ReferenceNumber), text)
+	}
+	return true
+}
+
+// Follows reports whether a transaction comes after the query's After
+// position, or true if it has none.
+func (q TransactionQuery) Follows(t *Transaction) bool {
+	if q.After == nil {
+		return true
+	}
+	c := positionOf(t).Compare(*q.After)
+	if q.NewestFirst {
+		return c < 0
+	}
+	return c > 0
+}
+
+// QueryTransactions returns one page of the transactions that match filter
+// and that the caller may see. A filter that matches nothing returns an
+// empty page, not an error.
+func (bs *BankingSystem) QueryTransactions(filter TransactionFilter) (*TransactionPage, error) {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := bs.transactionQuery(filter, allowed)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	page := &TransactionPage{Transactions: []*Transaction{}}
+	if q == nil {
+		return page, nil
+	}
+	if q.Accounts == nil {
+		// Search only the caller's accounts, rather than the whole bank for
+		// the few transactions they may see.
+		accounts, all, err := bs.accountScope(ActionViewTransaction)
+		if err != nil {
+			return nil, err
+		}
+		if !all {
+			if len(accounts) == 0 {
+				return page, nil
+			}
+			q.Accounts = accounts
+		}
+	}
+
+	// Fetch one more than a page to learn whether there is another, and
+	// keep fetching while the caller may not see what was found.
+	q.Limit = limit + 1
+	for {
+		batch, err := bs.transactions.Query(*q)
+		if err != nil {
+			return nil, err
+		}
+		for _, transaction := range batch {
+			if allowed(ActionViewTransaction, transactionResource(transaction)) == nil {
+				page.Transactions = append(page.Transactions, transaction)
+			}
+		}
+		if len(page.Transactions) > limit {
+			page.Transactions = page.Transactions[:limit]
+			page.NextCursor = encodeCursor(filter.NewestFirst, positionOf(page.Transactions[limit-1]))
+			return page, nil
+		}
+		if len(batch) < q.Limit {
+			return page, nil
+		}
+		after := positionOf(batch[len(batch)-1])
+		q.After = &after
+	}
+}
+
+// transactionQuery checks a filter and turns it into a query. It returns nil
+// if the filter cannot match anything.
+func (bs *BankingSystem) transactionQuery(filter TransactionFilter, allowed func(Action, resource) error) (*TransactionQuery, error) {
+	if filter.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidInput)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidInput)
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil {
+		c, err := filter.MinAmount.Cmp(*filter.MaxAmount)
+		if err != nil {
+			return nil, err
+		}
+		if c > 0 {
+			return nil, fmt.Errorf("%w: minimum amount is above the maximum", ErrInvalidInput)
+		}
+	}
+	for _, status := range filter.Statuses {
+		if !slices.Contains(transactionStatuses, status) {
+			return nil, fmt.Errorf("%w: unknown transaction status %q", ErrInvalidInput, status)
+		}
+	}
+	for _, tType := range filter.Types {
+		if !slices.Contains(transactionTypes, tType) {
+			return nil, fmt.Errorf("%w: unknown transaction type %q", ErrInvalidInput, tType)
+		}
+	}
+
+	q := &TransactionQuery{
+		Types:           filter.Types,
+		Statuses:        filter.Statuses,
+		MinAmount:       filter.MinAmount,
+		MaxAmount:       filter.MaxAmount,
+		Since:           filter.Since,
+		Until:           filter.Until,
+		Text:            strings.TrimSpace(filter.Text),
+		ReferenceNumber: strings.ToUpper(strings.TrimSpace(filter.ReferenceNumber)),
+		NewestFirst:     filter.NewestFirst,
+	}
+	if filter.Cursor != "" {
+		after, err := decodeCursor(filter.Cursor, filter.NewestFirst)
+		if err != nil {
+			return nil, err
+		}
+		q.After = &after
+	}
+
+	if filter.AccountNumber != "" {
+		if err := allowed(ActionViewAccount, accountResource(filter.AccountNumber)); err != nil {
+			return nil, err
+		}
+		if _, err := bs.accounts.GetAccountDetails(filter.AccountNumber); err != nil {
+			return nil, err
+		}
+		q.Accounts = []string{filter.AccountNumber}
+	}
+	if filter.UserID != 0 {
+		if err := allowed(ActionViewUser, userResource(filter.UserID)); err != nil {
+			return nil, err
+		}
+		user, err := bs.users.Get(filter.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if q.Accounts == nil {
+			q.Accounts = user.Accounts
+		} else if !slices.Contains(user.Accounts, filter.AccountNumber) {
+			return nil, nil
+		}
+		if len(q.Accounts) == 0 {
+			return nil, nil
+		}
+	}
+	return q, nil
+}
+
+// encodeCursor writes a position as an opaque string. The order is part of
+// it so a cursor cannot be replayed against the opposite order.
+func encodeCursor(newestFirst bool, after TransactionPosition) string {
+	order := "asc"
+	if newestFirst {
+		order = "desc"
+	}
+	raw := order + "|" + after.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + after.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string, newestFirst bool) (TransactionPosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return TransactionPosition{}, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return TransactionPosition{}, ErrInvalidCursor
+	}
+	if parts[0] != "asc" && parts[0] != "desc" {
+		return TransactionPosition{}, ErrInvalidCursor
+	}
+	if (parts[0] == "desc") != newestFirst {
+		return TransactionPosition{}, fmt.Errorf("%w: it was issued for the opposite order", ErrInvalidCursor)
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return TransactionPosition{}, ErrInvalidCursor
+	}
+	return TransactionPosition{Timestamp: timestamp, ID: parts[2]}, nil
+}
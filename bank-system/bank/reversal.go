@@ -0,0 +1,110 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrAlreadyReversed = errors.New("transaction has already been reversed")
+	ErrNotReversible   = errors.New("transaction cannot be reversed")
+)
+
+// ReverseTransaction undoes a completed transaction by posting a Reversal
+// that moves the money back and reverses its journal entry. Any fee charged
+// for the transaction is refunded the same way. The reversal shares the
+// original's reference number and links to it, and the original is marked
+// Reversed so it drops out of the account's summary.
+//
+// A reversal must not take an account beyond its overdraft limit, and a
+// transaction can only be reversed once.
+func (bs *BankingSystem) ReverseTransaction(transactionID, reason string) (*Transaction, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: a reason is required to reverse a transaction", ErrInvalidInput)
+	}
+
+	original, err := bs.transactions.GetTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.authorize(ActionReverseTransaction, transactionResource(original)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(original.FromAccount, original.ToAccount)
+	defer unlock()
+
+	var reversal *Transaction
+	err = bs.update(func(s *services) error {
+		var err error
+		reversal, err = s.reverse(transactionID, reason)
+		if err != nil {
+			return err
+		}
+
+		// Refund the fees charged for it.
+		transactions, err := s.transactions.GetTransactionsByAccount(feePayer(original))
+		if err != nil {
+			return err
+		}
+		for _, t := range transactions {
+			if t.Type == Fee && t.LinkedTransactionID == transactionID && t.Status == Completed {
+				if _, err := s.reverse(t.ID, reason); err != nil {
+					return fmt.Errorf("failed to refund fee %s: %w", t.ID, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// reverse posts the Reversal of one transaction. The caller must hold the
+// locks of the accounts it touches.
+func (s *services) reverse(transactionID, reason string) (*Transaction, error) {
+	original, err := s.transactions.GetTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case original.Status == Reversed:
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyReversed, transactionID)
+	case original.Type == Reversal:
+		return nil, fmt.Errorf("%w: %s is itself a reversal", ErrNotReversible, transactionID)
+	case original.Status != Completed:
+		return nil, fmt.Errorf("%w: %s is %s", ErrNotReversible, transactionID, original.Status)
+	}
+
+	entries, err := s.ledger.GetEntries(feePayer(original))
+	if err != nil {
+		return nil, err
+	}
+	var entry *JournalEntry
+	for _, e := range entries {
+		if e.TransactionID == transactionID {
+			entry = &e
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: no journal entry for %s", ErrNotReversible, transactionID)
+	}
+
+	description := fmt.Sprintf("Reversal of %s: %s", transactionID, reason)
+	reversal, err := s.post(Reversal, original.ToAccount, original.FromAccount, original.Amount, description, reversalLines(entry.Lines))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transactions.MarkReversed(transactionID, reversal.ID); err != nil {
+		return nil, err
+	}
+	reversal.ReferenceNumber = original.ReferenceNumber
+	reversal.LinkedTransactionID = transactionID
+	return reversal, nil
+}
@@ -0,0 +1,181 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RetryPolicy says how a standing instruction run that fails for lack of
+// funds is retried. A retry that would fall on or after the instruction's
+// next run is not made.
+type RetryPolicy struct {
+	// MaxRetries is how many times the run is tried again before it is
+	// given up.
+	MaxRetries int
+	Interval   time.Duration
+}
+
+// DefaultRetryPolicy retries a run up to three times, four hours apart.
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, Interval: 4 * time.Hour}
+
+type SchedulerOption func(*Scheduler)
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.retry = policy
+	}
+}
+
+// Scheduler runs standing instructions when they fall due. It takes the
+// time from the banking system's Clock, so WithClock makes its runs
+// deterministic.
+type Scheduler struct {
+	bank  *BankingSystem
+	retry RetryPolicy
+	// mu keeps runs of RunDue from overlapping.
+	mu sync.Mutex
+}
+
+// NewScheduler returns a scheduler that runs instructions through bs, which
+// must be allowed to run batch jobs.
+func NewScheduler(bs *BankingSystem, opts ...SchedulerOption) *Scheduler {
+	sc := &Scheduler{
+		bank:  bs,
+		retry: DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	return sc
+}
+
+// Run calls RunDue every interval until ctx is done. Errors are passed to
+// onError, if set, and the next tick tries again.
+func (sc *Scheduler) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := sc.RunDue(); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue transfers the money for every active standing instruction that is
+// due, catching up on runs missed while the scheduler was not running, and
+// returns the attempts it made. A failed transfer is recorded as an attempt,
+// not returned as an error.
+func (sc *Scheduler) RunDue() ([]InstructionAttempt, error) {
+	if err := sc.bank.authorize(ActionRunBatch, bankResource); err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	instructions, err := sc.bank.instructions.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []InstructionAttempt
+	for _, instruction := range instructions {
+		for {
+			attempt, err := sc.runNext(instruction.ID)
+			if err != nil {
+				return attempts, fmt.Errorf("run standing instruction %d: %w", instruction.ID, err)
+			}
+			if attempt == nil {
+				break
+			}
+			attempts = append(attempts, *attempt)
+		}
+	}
+	return attempts, nil
+}
+
+// runNext makes one attempt at an instruction if it is active and due, and
+// returns nil otherwise.
+func (sc *Scheduler) runNext(id int) (*InstructionAttempt, error) {
+	bs := sc.bank
+	unlock := bs.accountLocks.lock(instructionKey(id))
+	defer unlock()
+
+	instruction, err := bs.instructions.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	now := bs.clock.Now()
+	if instruction.Status != InstructionActive || instruction.dueAt().After(now) {
+		return nil, nil
+	}
+
+	description := fmt.Sprintf("Standing instruction %d", id)
+	if instruction.Description != "" {
+		description += ": " + instruction.Description
+	}
+	attempt := InstructionAttempt{InstructionID: id, ScheduledFor: instruction.NextRun, AttemptedAt: now}
+
+	_, err = bs.transfer(instruction.FromAccount, instruction.ToAccount, instruction.Amount, description, func(s *services, t *Transaction) error {
+		attempt.TransactionID = t.ID
+		return s.recordAttempt(*instruction, &attempt, nil, sc.retry)
+	})
+	if err == nil {
+		return &attempt, nil
+	}
+
+	cause := err
+	attempt.TransactionID = ""
+	err = bs.update(func(s *services) error {
+		return s.recordAttempt(*instruction, &attempt, cause, sc.retry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// recordAttempt adds an attempt that ended in cause to an instruction's
+// history and schedules what comes next: a retry if the run failed for lack
+// of funds and the policy allows one, or else the next run. The caller must
+// hold the instruction's lock.
+func (s *services) recordAttempt(instruction StandingInstruction, attempt *InstructionAttempt, cause error, retry RetryPolicy) error {
+	attempts, err := s.instructions.ListAttempts(instruction.ID)
+	if err != nil {
+		return err
+	}
+	attempt.Sequence = len(attempts) + 1
+
+	retryAt := attempt.AttemptedAt.Add(retry.Interval)
+	switch {
+	case cause == nil:
+		attempt.Outcome = AttemptSucceeded
+		instruction.advance()
+	case errors.Is(cause, ErrInsufficientFunds) && instruction.Retries < retry.MaxRetries &&
+		retryAt.Before(instruction.Recurrence.after(instruction.NextRun)):
+		attempt.Outcome = AttemptRetrying
+		attempt.Error = cause.Error()
+		instruction.RetryAt = retryAt
+		instruction.Retries++
+	default:
+		attempt.Outcome = AttemptFailed
+		attempt.Error = cause.Error()
+		instruction.advance()
+	}
+	instruction.UpdatedAt = s.clock.Now()
+
+	if err := s.instructions.AppendAttempt(*attempt); err != nil {
+		return err
+	}
+	return s.instructions.Save(instruction)
+}
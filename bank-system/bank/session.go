@@ -0,0 +1,159 @@
+package bank
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnauthenticated = errors.New("not logged in or session expired")
+	ErrForbidden       = errors.New("not allowed")
+)
+
+// DefaultSessionTTL is how long a session stays valid without being used.
+const DefaultSessionTTL = 30 * time.Minute
+
+// Principal is the customer on whose behalf a BankingSystem acts.
+type Principal struct {
+	UserID int
+	Email  string
+}
+
+// Session is a logged-in principal. Token is the bearer credential and is
+// only known to the caller of Login; the system keeps a hash of it.
+type Session struct {
+	Token     string
+	Principal Principal
+	ExpiresAt time.Time
+}
+
+type sessionEntry struct {
+	principal Principal
+	expiresAt time.Time
+}
+
+// sessionStore keeps sessions in memory, so they end when the process does.
+type sessionStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	clock    Clock
+	sessions map[[sha256.Size]byte]*sessionEntry
+}
+
+func newSessionStore(ttl time.Duration, clock Clock) *sessionStore {
+	return &sessionStore{
+		ttl:      ttl,
+		clock:    clock,
+		sessions: make(map[[sha256.Size]byte]*sessionEntry),
+	}
+}
+
+func (ss *sessionStore) create(principal Principal) (*Session, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	now := ss.clock.Now()
+	for key, entry := range ss.sessions {
+		if now.After(entry.expiresAt) {
+			delete(ss.sessions, key)
+		}
+	}
+
+	entry := &sessionEntry{principal: principal, expiresAt: now.Add(ss.ttl)}
+	ss.sessions[sha256.Sum256([]byte(token))] = entry
+
+	return &Session{Token: token, Principal: principal, ExpiresAt: entry.expiresAt}, nil
+}
+
+// lookup returns the session's principal and extends its lifetime.
+func (ss *sessionStore) lookup(token string) (Principal, error) {
+	key := sha256.Sum256([]byte(token))
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	entry, ok := ss.sessions[key]
+	if !ok {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	now := ss.clock.Now()
+	if now.After(entry.expiresAt) {
+		delete(ss.sessions, key)
+		return Principal{}, ErrUnauthenticated
+	}
+
+	entry.expiresAt = now.Add(ss.ttl)
+	return entry.principal, nil
+}
+
+func (ss *sessionStore) delete(token string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	delete(ss.sessions, sha256.Sum256([]byte(token)))
+}
+
+// WithSessionTTL sets how long a session stays valid without being used.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(bs *BankingSystem) {
+		bs.sessionTTL = ttl
+	}
+}
+
+// Login checks a customer's credentials and starts a session.
+func (bs *BankingSystem) Login(email, password string) (*Session, error) {
+	user, err := bs.VerifyPassword(email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs.sessions.create(Principal{UserID: user.ID, Email: user.Email})
+}
+
+// Logout ends a session. Ending an unknown or expired session is not an
+// error.
+func (bs *BankingSystem) Logout(token string) {
+	bs.sessions.delete(token)
+}
+
+// Authenticate returns a view of the banking system acting for the owner of
+// a session token.
+func (bs *BankingSystem) Authenticate(token string) (*BankingSystem, error) {
+	principal, err := bs.sessions.lookup(token)
+	if err != nil {
+		return nil, err
+	}
+	return bs.As(principal), nil
+}
+
+// As returns a view of the banking system that acts for principal: each
+// operation is checked against the policy for the principal's role. The view
+// shares all state with bs.
+//
+// A BankingSystem that is not acting for anyone, such as the one returned
+// by NewBankingSystem, has unrestricted access and is meant for trusted
+// callers only.
+func (bs *BankingSystem) As(principal Principal) *BankingSystem {
+	scoped := *bs
+	scoped.principal = &principal
+	return &scoped
+}
+
+// Principal returns who bs acts for, or false if it is unrestricted.
+func (bs *BankingSystem) Principal() (Principal, bool) {
+	if bs.principal == nil {
+		return Principal{}, false
+	}
+	return *bs.principal, true
+}
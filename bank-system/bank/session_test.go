@@ -0,0 +1,95 @@
+package bank_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bank-system/bank"
+)
+
+func TestSessions(t *testing.T) {
+	clock := &fakeClock{now: date(2026, time.February, 10).Add(12 * time.Hour)}
+	bs := bank.NewBankingSystem(bank.WithClock(clock), bank.WithSessionTTL(10*time.Minute), bank.WithPasswordParams(cheapParams))
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	if _, err := bs.Login("test.user@example.com", "wrong"); !errors.Is(err, bank.ErrInvalidCredentials) {
+		t.Errorf("Login with the wrong password = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := bs.Login("nobody@example.com", "S3cure!Passw0rd"); !errors.Is(err, bank.ErrInvalidCredentials) {
+		t.Errorf("Login of an unknown user = %v, want ErrInvalidCredentials", err)
+	}
+
+	session, err := bs.Login("test.user@example.com", "S3cure!Passw0rd")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if want := (bank.Principal{UserID: user.ID, Email: user.Email}); session.Principal != want {
+		t.Errorf("session principal %+v, want %+v", session.Principal, want)
+	}
+	if want := clock.Now().Add(10 * time.Minute); !session.ExpiresAt.Equal(want) {
+		t.Errorf("session expires at %v, want %v", session.ExpiresAt, want)
+	}
+
+	as, err := bs.Authenticate(session.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if principal, ok := as.Principal(); !ok || principal.UserID != user.ID {
+		t.Errorf("authenticated principal %+v, %v; want user %d", principal, ok, user.ID)
+	}
+	if _, ok := bs.Principal(); ok {
+		t.Error("Authenticate gave the unrestricted system a principal")
+	}
+
+	// Every use pushes expiry back by the TTL, so a session in use stays
+	// alive well past its first deadline.
+	for range 3 {
+		clock.Set(clock.Now().Add(9 * time.Minute))
+		if _, err := bs.Authenticate(session.Token); err != nil {
+			t.Fatalf("Authenticate after %v idle: %v", 9*time.Minute, err)
+		}
+	}
+
+	clock.Set(clock.Now().Add(10*time.Minute + time.Second))
+	if _, err := bs.Authenticate(session.Token); !errors.Is(err, bank.ErrUnauthenticated) {
+		t.Errorf("Authenticate of an idle session = %v, want ErrUnauthenticated", err)
+	}
+	// The expired session is gone, not just refused this once.
+	clock.Set(clock.Now().Add(-time.Hour))
+	if _, err := bs.Authenticate(session.Token); !errors.Is(err, bank.ErrUnauthenticated) {
+		t.Errorf("Authenticate of an expired session = %v, want ErrUnauthenticated", err)
+	}
+
+	first, err := bs.Login("test.user@example.com", "S3cure!Passw0rd")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	second, err := bs.Login("test.user@example.com", "S3cure!Passw0rd")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if first.Token == second.Token {
+		t.Fatal("two logins got the same token")
+	}
+	bs.Logout(first.Token)
+	if _, err := bs.Authenticate(first.Token); !errors.Is(err, bank.ErrUnauthenticated) {
+		t.Errorf("Authenticate after Logout = %v, want ErrUnauthenticated", err)
+	}
+	if _, err := bs.Authenticate(second.Token); err != nil {
+		t.Errorf("Logout of one session ended another: %v", err)
+	}
+	// Logging out twice, or with a token that was never issued, is harmless.
+	bs.Logout(first.Token)
+	bs.Logout("never issued")
+
+	for _, token := range []string{"", "never issued", second.Token + "x", second.Token[1:]} {
+		if _, err := bs.Authenticate(token); !errors.Is(err, bank.ErrUnauthenticated) {
+			t.Errorf("Authenticate(%q) = %v, want ErrUnauthenticated", token, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package sqlitestore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migrations are applied in order and never edited once released; schema
+// changes go in a new entry at the end.
+var migrations = [][]string{
+	{
+		`CREATE TABLE users (
+			id         INTEGER PRIMARY KEY,
+			first_name TEXT NOT NULL,
+			last_name  TEXT NOT NULL,
+			email      TEXT NOT NULL,
+			password   TEXT NOT NULL DEFAULT '',
+			address    TEXT NOT NULL DEFAULT '',
+			phone      TEXT NOT NULL DEFAULT '',
+			pan        TEXT NOT NULL DEFAULT '',
+			aadhaar    TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE UNIQUE INDEX idx_users_email ON users (email)`,
+
+		`CREATE TABLE accounts (
+			account_number TEXT PRIMARY KEY,
+			holder_name    TEXT NOT NULL,
+			account_type   TEXT NOT NULL,
+			currency       TEXT NOT NULL,
+			balance_minor  INTEGER NOT NULL,
+			status         TEXT NOT NULL,
+			created_at     TEXT NOT NULL,
+			updated_at     TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE user_accounts (
+			user_id        INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+			position       INTEGER NOT NULL,
+			account_number TEXT NOT NULL,
+			PRIMARY KEY (user_id, position)
+		)`,
+		`CREATE UNIQUE INDEX idx_user_accounts_account_number ON user_accounts (account_number)`,
+
+		`CREATE TABLE transactions (
+			id                  TEXT PRIMARY KEY,
+			type                TEXT NOT NULL,
+			status              TEXT NOT NULL,
+			from_account        TEXT NOT NULL DEFAULT '',
+			to_account          TEXT NOT NULL DEFAULT '',
+			currency            TEXT NOT NULL,
+			amount_minor        INTEGER NOT NULL,
+			fee_minor           INTEGER NOT NULL DEFAULT 0,
+			balance_after_minor INTEGER NOT NULL DEFAULT 0,
+			timestamp           TEXT NOT NULL,
+			description         TEXT NOT NULL DEFAULT '',
+			reference_number    TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX idx_transactions_timestamp ON transactions (timestamp, id)`,
+		`CREATE INDEX idx_transactions_from_account ON transactions (from_account, timestamp)`,
+		`CREATE INDEX idx_transactions_to_account ON transactions (to_account, timestamp)`,
+		`CREATE INDEX idx_transactions_reference_number ON transactions (reference_number)`,
+
+		`CREATE TABLE journal_entries (
+			id             TEXT PRIMARY KEY,
+			sequence       INTEGER NOT NULL UNIQUE,
+			transaction_id TEXT NOT NULL DEFAULT '',
+			timestamp      TEXT NOT NULL,
+			description    TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX idx_journal_entries_transaction_id ON journal_entries (transaction_id)`,
+
+		`CREATE TABLE journal_lines (
+			entry_id     TEXT NOT NULL REFERENCES journal_entries (id),
+			line_no      INTEGER NOT NULL,
+			account      TEXT NOT NULL,
+			side         TEXT NOT NULL CHECK (side IN ('DEBIT', 'CREDIT')),
+			currency     TEXT NOT NULL,
+			amount_minor INTEGER NOT NULL CHECK (amount_minor > 0),
+			PRIMARY KEY (entry_id, line_no)
+		)`,
+		`CREATE INDEX idx_journal_lines_account ON journal_lines (account)`,
+
+		`CREATE TABLE sequences (
+			name  TEXT PRIMARY KEY,
+			value INTEGER NOT NULL
+		)`,
+	},
+	{
+		// The column holds an argon2id hash in PHC form; see
+		// bank.PasswordHasher.
+		`ALTER TABLE users RENAME COLUMN password TO password_hash`,
+	},
+	{
+		`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'customer'`,
+	},
+	{
+		`ALTER TABLE users ADD COLUMN data_key TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`CREATE TABLE audit_log (
+			sequence  INTEGER PRIMARY KEY,
+			timestamp TEXT NOT NULL,
+			actor     TEXT NOT NULL,
+			action    TEXT NOT NULL,
+			resource  TEXT NOT NULL,
+			before    TEXT NOT NULL,
+			after     TEXT NOT NULL,
+			prev_hash TEXT NOT NULL,
+			hash      TEXT NOT NULL
+		)`,
+		// The hash chain detects tampering; these stop it by accident.
+		`CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END`,
+		`CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+			BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END`,
+	},
+	{
+		// Empty until interest is first credited.
+		`ALTER TABLE accounts ADD COLUMN interest_credited_until TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`ALTER TABLE accounts ADD COLUMN fees_charged_until TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE transactions ADD COLUMN linked_transaction_id TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`ALTER TABLE accounts ADD COLUMN overdraft_limit_minor INTEGER NOT NULL DEFAULT 0`,
+	},
+	{
+		`ALTER TABLE accounts ADD COLUMN status_reason TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE accounts ADD COLUMN status_changed_by TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE accounts ADD COLUMN status_changed_at TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`ALTER TABLE accounts ADD COLUMN held_minor INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE transactions ADD COLUMN expires_at TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		`CREATE TABLE standing_instructions (
+			id           INTEGER PRIMARY KEY,
+			from_account TEXT NOT NULL,
+			to_account   TEXT NOT NULL,
+			currency     TEXT NOT NULL,
+			amount_minor INTEGER NOT NULL CHECK (amount_minor > 0),
+			description  TEXT NOT NULL DEFAULT '',
+			frequency    TEXT NOT NULL,
+			start_at     TEXT NOT NULL,
+			day_of_month INTEGER NOT NULL DEFAULT 0,
+			end_at       TEXT NOT NULL DEFAULT '',
+			status       TEXT NOT NULL,
+			next_run     TEXT NOT NULL DEFAULT '',
+			retry_at     TEXT NOT NULL DEFAULT '',
+			retries      INTEGER NOT NULL DEFAULT 0,
+			created_by   TEXT NOT NULL DEFAULT '',
+			created_at   TEXT NOT NULL,
+			updated_at   TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE instruction_attempts (
+			instruction_id INTEGER NOT NULL REFERENCES standing_instructions (id),
+			sequence       INTEGER NOT NULL,
+			scheduled_for  TEXT NOT NULL,
+			attempted_at   TEXT NOT NULL,
+			outcome        TEXT NOT NULL,
+			transaction_id TEXT NOT NULL DEFAULT '',
+			error          TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (instruction_id, sequence)
+		)`,
+	},
+	{
+		`CREATE TABLE idempotency_keys (
+			actor           TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			request_hash    TEXT NOT NULL,
+			transaction_id  TEXT NOT NULL,
+			created_at      TEXT NOT NULL,
+			expires_at      TEXT NOT NULL,
+			PRIMARY KEY (actor, idempotency_key)
+		)`,
+		`CREATE INDEX idempotency_keys_expires_at ON idempotency_keys (expires_at)`,
+	},
+}
+
+// migrate brings the schema up to date, applying each pending migration in
+// its own transaction.
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this program supports (%d)", current, len(migrations))
+	}
+
+	for version := current + 1; version <= len(migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range migrations[version-1] {
+			if _, err := tx.Exec(stmt); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d: %w", version, err)
+			}
+		}
+
+		_, err = tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+			version, formatTime(time.Now()))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
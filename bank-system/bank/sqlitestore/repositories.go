@@ -0,0 +1,807 @@
+package sqlitestore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bank-system/bank"
+)
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+type userRepository struct {
+	view
+}
+
+func (r userRepository) NextID() (int, error) {
+	n, err := r.nextSequence("user")
+	return int(n), err
+}
+
+const userColumns = `id, first_name, last_name, email, password_hash, address, phone, pan, aadhaar, role, data_key`
+
+func scanUser(row scanner) (*bank.User, error) {
+	var u bank.User
+	err := row.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.PasswordHash,
+		&u.Address, &u.Phone, &u.PanCardNumber, &u.AadharCardNumber, &u.Role, &u.DataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r userRepository) loadAccounts(user *bank.User) error {
+	rows, err := r.query(`SELECT account_number FROM user_accounts WHERE user_id = ? ORDER BY position`, user.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	user.Accounts = []string{}
+	for rows.Next() {
+		var accountNumber string
+		if err := rows.Scan(&accountNumber); err != nil {
+			return err
+		}
+		user.Accounts = append(user.Accounts, accountNumber)
+	}
+	return rows.Err()
+}
+
+func (r userRepository) getOne(query string, arg any) (*bank.User, error) {
+	user, err := scanUser(r.queryRow(query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, bank.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadAccounts(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r userRepository) Get(id int) (*bank.User, error) {
+	return r.getOne(`SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+}
+
+func (r userRepository) GetByEmail(email string) (*bank.User, error) {
+	return r.getOne(`SELECT `+userColumns+` FROM users WHERE email = ?`, email)
+}
+
+func (r userRepository) Save(user bank.User) error {
+	return r.atomic(func(v view) error {
+		err := v.exec(`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				first_name = excluded.first_name,
+				last_name = excluded.last_name,
+				email = excluded.email,
+				password_hash = excluded.password_hash,
+				address = excluded.address,
+				phone = excluded.phone,
+				pan = excluded.pan,
+				aadhaar = excluded.aadhaar,
+				role = excluded.role,
+				data_key = excluded.data_key`,
+			user.ID, user.FirstName, user.LastName, user.Email, user.PasswordHash,
+			user.Address, user.Phone, user.PanCardNumber, user.AadharCardNumber, user.Role, user.DataKey)
+		if err != nil {
+			return err
+		}
+
+		if err := v.exec(`DELETE FROM user_accounts WHERE user_id = ?`, user.ID); err != nil {
+			return err
+		}
+		for i, accountNumber := range user.Accounts {
+			err := v.exec(`INSERT INTO user_accounts (user_id, position, account_number) VALUES (?, ?, ?)`,
+				user.ID, i, accountNumber)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r userRepository) Delete(id int) error {
+	res, err := r.execResult(`DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return bank.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r userRepository) List() ([]bank.User, error) {
+	rows, err := r.query(`SELECT ` + userColumns + ` FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []bank.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		if err := r.loadAccounts(&users[i]); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+type accountRepository struct {
+	view
+}
+
+const accountColumns = `account_number, holder_name, account_type, currency, balance_minor, status, created_at, updated_at,
+	interest_credited_until, fees_charged_until, overdraft_limit_minor, status_reason, status_changed_by, status_changed_at, held_minor`
+
+func scanAccount(row scanner) (*bank.Account, error) {
+	var (
+		a                    bank.Account
+		balance, overdraft   int64
+		held                 int64
+		createdAt, updatedAt string
+		interestUntil        string
+		feesUntil            string
+		statusChangedAt      string
+	)
+	err := row.Scan(&a.AccountNumber, &a.HolderName, &a.AccountType, &a.Currency, &balance,
+		&a.Status, &createdAt, &updatedAt, &interestUntil, &feesUntil, &overdraft,
+		&a.StatusReason, &a.StatusChangedBy, &statusChangedAt, &held)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Balance = bank.NewMoney(balance, a.Currency)
+	a.OverdraftLimit = bank.NewMoney(overdraft, a.Currency)
+	a.HeldAmount = bank.NewMoney(held, a.Currency)
+	if a.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if a.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	if interestUntil != "" {
+		if a.InterestCreditedUntil, err = parseTime(interestUntil); err != nil {
+			return nil, err
+		}
+	}
+	if feesUntil != "" {
+		if a.FeesChargedUntil, err = parseTime(feesUntil); err != nil {
+			return nil, err
+		}
+	}
+	if statusChangedAt != "" {
+		if a.StatusChangedAt, err = parseTime(statusChangedAt); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+func (r accountRepository) Get(accountNumber string) (*bank.Account, error) {
+	account, err := scanAccount(r.queryRow(`SELECT `+accountColumns+` FROM accounts WHERE account_number = ?`, accountNumber))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, bank.ErrAccountNotFound
+	}
+	return account, err
+}
+
+func (r accountRepository) Save(account bank.Account) error {
+	if account.Balance.Currency() != "" && account.Balance.Currency() != account.Currency {
+		return fmt.Errorf("%w: account %s holds %s", bank.ErrCurrencyMismatch, account.AccountNumber, account.Balance.Currency())
+	}
+
+	return r.exec(`INSERT INTO accounts (`+accountColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (account_number) DO UPDATE SET
+			holder_name = excluded.holder_name,
+			account_type = excluded.account_type,
+			currency = excluded.currency,
+			balance_minor = excluded.balance_minor,
+			status = excluded.status,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at,
+			interest_credited_until = excluded.interest_credited_until,
+			fees_charged_until = excluded.fees_charged_until,
+			overdraft_limit_minor = excluded.overdraft_limit_minor,
+			status_reason = excluded.status_reason,
+			status_changed_by = excluded.status_changed_by,
+			status_changed_at = excluded.status_changed_at,
+			held_minor = excluded.held_minor`,
+		account.AccountNumber, account.HolderName, account.AccountType, account.Currency,
+		account.Balance.MinorUnits(), account.Status, formatTime(account.CreatedAt), formatTime(account.UpdatedAt),
+		formatOptionalTime(account.InterestCreditedUntil), formatOptionalTime(account.FeesChargedUntil),
+		account.OverdraftLimit.MinorUnits(), account.StatusReason, account.StatusChangedBy,
+		formatOptionalTime(account.StatusChangedAt), account.HeldAmount.MinorUnits())
+}
+
+func (r accountRepository) List() ([]bank.Account, error) {
+	rows, err := r.query(`SELECT ` + accountColumns + ` FROM accounts ORDER BY account_number`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []bank.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, rows.Err()
+}
+
+type transactionRepository struct {
+	view
+}
+
+const transactionColumns = `id, type, status, from_account, to_account, currency, amount_minor,
+	fee_minor, balance_after_minor, timestamp, description, reference_number, linked_transaction_id, expires_at`
+
+func scanTransaction(row scanner) (*bank.Transaction, error) {
+	var (
+		t                         bank.Transaction
+		currency                  bank.Currency
+		amount, fee, balanceAfter int64
+		timestamp, expiresAt      string
+	)
+	err := row.Scan(&t.ID, &t.Type, &t.Status, &t.FromAccount, &t.ToAccount, &currency, &amount,
+		&fee, &balanceAfter, &timestamp, &t.Description, &t.ReferenceNumber, &t.LinkedTransactionID, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Amount = bank.NewMoney(amount, currency)
+	t.Fee = bank.NewMoney(fee, currency)
+	t.BalanceAfter = bank.NewMoney(balanceAfter, currency)
+	if t.Timestamp, err = parseTime(timestamp); err != nil {
+		return nil, err
+	}
+	if expiresAt != "" {
+		if t.ExpiresAt, err = parseTime(expiresAt); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func (r transactionRepository) Get(transactionID string) (*bank.Transaction, error) {
+	transaction, err := scanTransaction(r.queryRow(`SELECT `+transactionColumns+` FROM transactions WHERE id = ?`, transactionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, bank.ErrTransactionNotFound
+	}
+	return transaction, err
+}
+
+func (r transactionRepository) Create(t bank.Transaction) error {
+	args, err := transactionArgs(t)
+	if err != nil {
+		return err
+	}
+	res, err := r.execResult(`INSERT INTO transactions (`+transactionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("%w: %s", bank.ErrTransactionExists, t.ID)
+	}
+	return nil
+}
+
+func (r transactionRepository) Save(t bank.Transaction) error {
+	args, err := transactionArgs(t)
+	if err != nil {
+		return err
+	}
+	return r.exec(`INSERT INTO transactions (`+transactionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			type = excluded.type,
+			status = excluded.status,
+			from_account = excluded.from_account,
+			to_account = excluded.to_account,
+			currency = excluded.currency,
+			amount_minor = excluded.amount_minor,
+			fee_minor = excluded.fee_minor,
+			balance_after_minor = excluded.balance_after_minor,
+			timestamp = excluded.timestamp,
+			description = excluded.description,
+			reference_number = excluded.reference_number,
+			linked_transaction_id = excluded.linked_transaction_id,
+			expires_at = excluded.expires_at`, args...)
+}
+
+// transactionArgs returns the values of transactionColumns for t.
+func transactionArgs(t bank.Transaction) ([]any, error) {
+	currency := t.Amount.Currency()
+	for _, m := range []bank.Money{t.Fee, t.BalanceAfter} {
+		if m.Currency() != "" && m.Currency() != currency {
+			return nil, fmt.Errorf("%w: transaction %s mixes %s and %s", bank.ErrCurrencyMismatch, t.ID, currency, m.Currency())
+		}
+	}
+	return []any{t.ID, t.Type, t.Status, t.FromAccount, t.ToAccount, currency, t.Amount.MinorUnits(),
+		t.Fee.MinorUnits(), t.BalanceAfter.MinorUnits(), formatTime(t.Timestamp), t.Description, t.ReferenceNumber,
+		t.LinkedTransactionID, formatOptionalTime(t.ExpiresAt)}, nil
+}
+
+func (r transactionRepository) List() ([]*bank.Transaction, error) {
+	return r.Query(bank.TransactionQuery{})
+}
+
+func (r transactionRepository) Query(q bank.TransactionQuery) ([]*bank.Transaction, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if len(q.Accounts) > 0 {
+		in := placeholders(len(q.Accounts))
+		where = append(where, `(from_account IN (`+in+`) OR to_account IN (`+in+`))`)
+		for range 2 {
+			for _, account := range q.Accounts {
+				args = append(args, account)
+			}
+		}
+	}
+	if len(q.Types) > 0 {
+		where = append(where, `type IN (`+placeholders(len(q.Types))+`)`)
+		for _, tType := range q.Types {
+			args = append(args, tType)
+		}
+	}
+	if len(q.Statuses) > 0 {
+		where = append(where, `status IN (`+placeholders(len(q.Statuses))+`)`)
+		for _, status := range q.Statuses {
+			args = append(args, status)
+		}
+	}
+	if q.MinAmount != nil {
+		where = append(where, `currency = ? AND amount_minor >= ?`)
+		args = append(args, q.MinAmount.Currency(), q.MinAmount.MinorUnits())
+	}
+	if q.MaxAmount != nil {
+		where = append(where, `currency = ? AND amount_minor <= ?`)
+		args = append(args, q.MaxAmount.Currency(), q.MaxAmount.MinorUnits())
+	}
+	if !q.Since.IsZero() {
+		where = append(where, `timestamp >= ?`)
+		args = append(args, formatTime(q.Since))
+	}
+	if !q.Until.IsZero() {
+		where = append(where, `timestamp < ?`)
+		args = append(args, formatTime(q.Until))
+	}
+	if q.ReferenceNumber != "" {
+		where = append(where, `reference_number = ?`)
+		args = append(args, q.ReferenceNumber)
+	}
+	if q.Text != "" {
+		where = append(where, `(instr(lower(description), lower(?)) > 0 OR instr(lower(reference_number), lower(?)) > 0)`)
+		args = append(args, q.Text, q.Text)
+	}
+	order := `ORDER BY timestamp, id`
+	if q.After != nil {
+		where = append(where, `(timestamp > ? OR (timestamp = ? AND id > ?))`)
+		if q.NewestFirst {
+			where[len(where)-1] = `(timestamp < ? OR (timestamp = ? AND id < ?))`
+		}
+		after := formatTime(q.After.Timestamp)
+		args = append(args, after, after, q.After.ID)
+	}
+	if q.NewestFirst {
+		order = `ORDER BY timestamp DESC, id DESC`
+	}
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ` + order
+	if q.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, q.Limit)
+	}
+
+	rows, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []*bank.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rows.Err()
+}
+
+type journalRepository struct {
+	view
+}
+
+func (r journalRepository) NextSequence() (int64, error) {
+	return r.nextSequence("journal")
+}
+
+func (r journalRepository) Append(entry bank.JournalEntry) error {
+	return r.atomic(func(v view) error {
+		err := v.exec(`INSERT INTO journal_entries (id, sequence, transaction_id, timestamp, description) VALUES (?, ?, ?, ?, ?)`,
+			entry.ID, entry.Sequence, entry.TransactionID, formatTime(entry.Timestamp), entry.Description)
+		if err != nil {
+			return err
+		}
+
+		for i, line := range entry.Lines {
+			err := v.exec(`INSERT INTO journal_lines (entry_id, line_no, account, side, currency, amount_minor) VALUES (?, ?, ?, ?, ?, ?)`,
+				entry.ID, i, line.Account, line.Side, line.Amount.Currency(), line.Amount.MinorUnits())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r journalRepository) List() ([]bank.JournalEntry, error) {
+	return r.list(``)
+}
+
+func (r journalRepository) ListByAccount(account string) ([]bank.JournalEntry, error) {
+	return r.list(`WHERE e.id IN (SELECT entry_id FROM journal_lines WHERE account = ?)`, account)
+}
+
+func (r journalRepository) list(where string, args ...any) ([]bank.JournalEntry, error) {
+	rows, err := r.query(`SELECT e.id, e.sequence, e.transaction_id, e.timestamp, e.description,
+			l.account, l.side, l.currency, l.amount_minor
+		FROM journal_entries e JOIN journal_lines l ON l.entry_id = e.id
+		`+where+`
+		ORDER BY e.sequence, l.line_no`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []bank.JournalEntry
+	for rows.Next() {
+		var (
+			e         bank.JournalEntry
+			line      bank.JournalLine
+			timestamp string
+			currency  bank.Currency
+			amount    int64
+		)
+		err := rows.Scan(&e.ID, &e.Sequence, &e.TransactionID, &timestamp, &e.Description,
+			&line.Account, &line.Side, &currency, &amount)
+		if err != nil {
+			return nil, err
+		}
+		line.Amount = bank.NewMoney(amount, currency)
+
+		if n := len(entries); n > 0 && entries[n-1].ID == e.ID {
+			entries[n-1].Lines = append(entries[n-1].Lines, line)
+			continue
+		}
+		if e.Timestamp, err = parseTime(timestamp); err != nil {
+			return nil, err
+		}
+		e.Lines = []bank.JournalLine{line}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r journalRepository) totals(where string, args ...any) (map[string]bank.AccountTotals, error) {
+	rows, err := r.query(`SELECT account, currency,
+			COALESCE(SUM(CASE side WHEN 'DEBIT' THEN amount_minor END), 0),
+			COALESCE(SUM(CASE side WHEN 'CREDIT' THEN amount_minor END), 0)
+		FROM journal_lines `+where+`
+		GROUP BY account, currency`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]bank.AccountTotals)
+	for rows.Next() {
+		var (
+			account         string
+			currency        bank.Currency
+			debits, credits int64
+		)
+		if err := rows.Scan(&account, &currency, &debits, &credits); err != nil {
+			return nil, err
+		}
+		if _, dup := totals[account]; dup {
+			return nil, fmt.Errorf("%w: ledger account %s holds several currencies", bank.ErrCurrencyMismatch, account)
+		}
+		totals[account] = bank.AccountTotals{
+			Debits:  bank.NewMoney(debits, currency),
+			Credits: bank.NewMoney(credits, currency),
+		}
+	}
+	return totals, rows.Err()
+}
+
+func (r journalRepository) Totals(account string) (bank.AccountTotals, error) {
+	totals, err := r.totals(`WHERE account = ?`, account)
+	if err != nil {
+		return bank.AccountTotals{}, err
+	}
+	return totals[account], nil
+}
+
+func (r journalRepository) AllTotals() (map[string]bank.AccountTotals, error) {
+	return r.totals(``)
+}
+
+type auditRepository struct {
+	view
+}
+
+const auditColumns = `sequence, timestamp, actor, action, resource, before, after, prev_hash, hash`
+
+func (r auditRepository) Append(entry bank.AuditEntry) error {
+	return r.exec(`INSERT INTO audit_log (`+auditColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Sequence, formatTime(entry.Timestamp), entry.Actor, entry.Action, entry.Resource,
+		string(entry.Before), string(entry.After), entry.PrevHash, entry.Hash)
+}
+
+func scanAuditEntry(row scanner) (*bank.AuditEntry, error) {
+	var (
+		e             bank.AuditEntry
+		timestamp     string
+		before, after string
+	)
+	err := row.Scan(&e.Sequence, &timestamp, &e.Actor, &e.Action, &e.Resource, &before, &after, &e.PrevHash, &e.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if e.Timestamp, err = parseTime(timestamp); err != nil {
+		return nil, err
+	}
+	if before != "" {
+		e.Before = json.RawMessage(before)
+	}
+	if after != "" {
+		e.After = json.RawMessage(after)
+	}
+	return &e, nil
+}
+
+func (r auditRepository) Last() (*bank.AuditEntry, error) {
+	entry, err := scanAuditEntry(r.queryRow(`SELECT ` + auditColumns + ` FROM audit_log ORDER BY sequence DESC LIMIT 1`))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return entry, err
+}
+
+func (r auditRepository) List() ([]bank.AuditEntry, error) {
+	rows, err := r.query(`SELECT ` + auditColumns + ` FROM audit_log ORDER BY sequence`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []bank.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+type instructionRepository struct {
+	view
+}
+
+func (r instructionRepository) NextID() (int, error) {
+	n, err := r.nextSequence("standing_instruction")
+	return int(n), err
+}
+
+const instructionColumns = `id, from_account, to_account, currency, amount_minor, description, frequency, start_at,
+	day_of_month, end_at, status, next_run, retry_at, retries, created_by, created_at, updated_at`
+
+func scanInstruction(row scanner) (*bank.StandingInstruction, error) {
+	var (
+		i                    bank.StandingInstruction
+		currency             bank.Currency
+		amount               int64
+		start, end           string
+		nextRun, retryAt     string
+		createdAt, updatedAt string
+	)
+	err := row.Scan(&i.ID, &i.FromAccount, &i.ToAccount, &currency, &amount, &i.Description, &i.Recurrence.Frequency, &start,
+		&i.Recurrence.DayOfMonth, &end, &i.Status, &nextRun, &retryAt, &i.Retries, &i.CreatedBy, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	i.Amount = bank.NewMoney(amount, currency)
+	if i.Recurrence.Start, err = parseTime(start); err != nil {
+		return nil, err
+	}
+	if i.Recurrence.End, err = parseOptionalTime(end); err != nil {
+		return nil, err
+	}
+	if i.NextRun, err = parseOptionalTime(nextRun); err != nil {
+		return nil, err
+	}
+	if i.RetryAt, err = parseOptionalTime(retryAt); err != nil {
+		return nil, err
+	}
+	if i.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if i.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r instructionRepository) Get(id int) (*bank.StandingInstruction, error) {
+	instruction, err := scanInstruction(r.queryRow(`SELECT `+instructionColumns+` FROM standing_instructions WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, bank.ErrInstructionNotFound
+	}
+	return instruction, err
+}
+
+func (r instructionRepository) Save(i bank.StandingInstruction) error {
+	return r.exec(`INSERT INTO standing_instructions (`+instructionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			from_account = excluded.from_account,
+			to_account = excluded.to_account,
+			currency = excluded.currency,
+			amount_minor = excluded.amount_minor,
+			description = excluded.description,
+			frequency = excluded.frequency,
+			start_at = excluded.start_at,
+			day_of_month = excluded.day_of_month,
+			end_at = excluded.end_at,
+			status = excluded.status,
+			next_run = excluded.next_run,
+			retry_at = excluded.retry_at,
+			retries = excluded.retries,
+			created_by = excluded.created_by,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at`,
+		i.ID, i.FromAccount, i.ToAccount, i.Amount.Currency(), i.Amount.MinorUnits(), i.Description,
+		i.Recurrence.Frequency, formatTime(i.Recurrence.Start), i.Recurrence.DayOfMonth, formatOptionalTime(i.Recurrence.End),
+		i.Status, formatOptionalTime(i.NextRun), formatOptionalTime(i.RetryAt), i.Retries, i.CreatedBy,
+		formatTime(i.CreatedAt), formatTime(i.UpdatedAt))
+}
+
+func (r instructionRepository) List() ([]bank.StandingInstruction, error) {
+	rows, err := r.query(`SELECT ` + instructionColumns + ` FROM standing_instructions ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instructions []bank.StandingInstruction
+	for rows.Next() {
+		instruction, err := scanInstruction(rows)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, *instruction)
+	}
+	return instructions, rows.Err()
+}
+
+const attemptColumns = `instruction_id, sequence, scheduled_for, attempted_at, outcome, transaction_id, error`
+
+func (r instructionRepository) AppendAttempt(a bank.InstructionAttempt) error {
+	return r.exec(`INSERT INTO instruction_attempts (`+attemptColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.InstructionID, a.Sequence, formatTime(a.ScheduledFor), formatTime(a.AttemptedAt), a.Outcome, a.TransactionID, a.Error)
+}
+
+func (r instructionRepository) ListAttempts(instructionID int) ([]bank.InstructionAttempt, error) {
+	rows, err := r.query(`SELECT `+attemptColumns+` FROM instruction_attempts WHERE instruction_id = ? ORDER BY sequence`, instructionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []bank.InstructionAttempt
+	for rows.Next() {
+		var (
+			a                         bank.InstructionAttempt
+			scheduledFor, attemptedAt string
+		)
+		err := rows.Scan(&a.InstructionID, &a.Sequence, &scheduledFor, &attemptedAt, &a.Outcome, &a.TransactionID, &a.Error)
+		if err != nil {
+			return nil, err
+		}
+		if a.ScheduledFor, err = parseTime(scheduledFor); err != nil {
+			return nil, err
+		}
+		if a.AttemptedAt, err = parseTime(attemptedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+type idempotencyRepository struct {
+	view
+}
+
+func (r idempotencyRepository) Get(actor, key string) (*bank.IdempotencyRecord, error) {
+	var (
+		record               bank.IdempotencyRecord
+		createdAt, expiresAt string
+	)
+	err := r.queryRow(`SELECT actor, idempotency_key, request_hash, transaction_id, created_at, expires_at
+		FROM idempotency_keys WHERE actor = ? AND idempotency_key = ?`, actor, key).
+		Scan(&record.Actor, &record.Key, &record.RequestHash, &record.TransactionID, &createdAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if record.ExpiresAt, err = parseTime(expiresAt); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r idempotencyRepository) Save(record bank.IdempotencyRecord) error {
+	return r.exec(`INSERT INTO idempotency_keys (actor, idempotency_key, request_hash, transaction_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (actor, idempotency_key) DO UPDATE SET
+			request_hash = excluded.request_hash,
+			transaction_id = excluded.transaction_id,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at`,
+		record.Actor, record.Key, record.RequestHash, record.TransactionID, formatTime(record.CreatedAt), formatTime(record.ExpiresAt))
+}
+
+func (r idempotencyRepository) Purge(now time.Time) (int, error) {
+	result, err := r.execResult(`DELETE FROM idempotency_keys WHERE expires_at <= ?`, formatTime(now))
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,230 @@
+// Package sqlitestore implements bank.Store on top of SQLite, using a
+// pure-Go driver so no C toolchain is needed.
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"bank-system/bank"
+
+	_ "modernc.org/sqlite"
+)
+
+const timeFormat = "2006-01-02T15:04:05.000000000Z"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// formatOptionalTime stores the zero time as an empty string.
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatTime(t)
+}
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(timeFormat, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Local(), nil
+}
+
+// parseOptionalTime reads the empty string as the zero time.
+func parseOptionalTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return parseTime(s)
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Store is a bank.Store backed by a SQLite database file.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path and applies any pending schema
+// migrations.
+func Open(path string) (*Store, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "synchronous(FULL)")
+	// Take the write lock when a transaction begins, so two units of work
+	// never both read and then fail to upgrade.
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// DB exposes the underlying database for ad-hoc SQL queries.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+func (s *Store) view() view {
+	return view{store: s, q: s.db}
+}
+
+func (s *Store) Users() bank.UserRepository {
+	return s.view().Users()
+}
+
+func (s *Store) Accounts() bank.AccountRepository {
+	return s.view().Accounts()
+}
+
+func (s *Store) Transactions() bank.TransactionRepository {
+	return s.view().Transactions()
+}
+
+func (s *Store) Journal() bank.JournalRepository {
+	return s.view().Journal()
+}
+
+func (s *Store) Audit() bank.AuditRepository {
+	return s.view().Audit()
+}
+
+func (s *Store) StandingInstructions() bank.StandingInstructionRepository {
+	return s.view().StandingInstructions()
+}
+
+func (s *Store) Idempotency() bank.IdempotencyRepository {
+	return s.view().Idempotency()
+}
+
+func (s *Store) Update(fn func(tx bank.Store) error) error {
+	return s.view().Update(fn)
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// view is the store seen either directly, where each write commits on its
+// own, or through a database transaction.
+type view struct {
+	store *Store
+	q     queryer
+	tx    *sql.Tx
+}
+
+func (v view) Users() bank.UserRepository {
+	return userRepository{v}
+}
+
+func (v view) Accounts() bank.AccountRepository {
+	return accountRepository{v}
+}
+
+func (v view) Transactions() bank.TransactionRepository {
+	return transactionRepository{v}
+}
+
+func (v view) Journal() bank.JournalRepository {
+	return journalRepository{v}
+}
+
+func (v view) Audit() bank.AuditRepository {
+	return auditRepository{v}
+}
+
+func (v view) StandingInstructions() bank.StandingInstructionRepository {
+	return instructionRepository{v}
+}
+
+func (v view) Idempotency() bank.IdempotencyRepository {
+	return idempotencyRepository{v}
+}
+
+func (v view) Update(fn func(tx bank.Store) error) error {
+	if v.tx != nil {
+		return fn(v)
+	}
+
+	tx, err := v.store.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(view{store: v.store, q: tx, tx: tx}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (v view) Close() error {
+	return nil
+}
+
+// atomic runs fn inside the current transaction, or a new one if there is
+// none, for writes that touch more than one row.
+func (v view) atomic(fn func(tx view) error) error {
+	return v.Update(func(tx bank.Store) error {
+		return fn(tx.(view))
+	})
+}
+
+func (v view) exec(query string, args ...any) error {
+	_, err := v.execResult(query, args...)
+	return err
+}
+
+func (v view) execResult(query string, args ...any) (sql.Result, error) {
+	return v.q.ExecContext(context.Background(), query, args...)
+}
+
+func (v view) query(query string, args ...any) (*sql.Rows, error) {
+	return v.q.QueryContext(context.Background(), query, args...)
+}
+
+func (v view) queryRow(query string, args ...any) *sql.Row {
+	return v.q.QueryRowContext(context.Background(), query, args...)
+}
+
+func (v view) nextSequence(name string) (int64, error) {
+	var n int64
+	err := v.queryRow(`INSERT INTO sequences (name, value) VALUES (?, 1)
+		ON CONFLICT (name) DO UPDATE SET value = value + 1
+		RETURNING value`, name).Scan(&n)
+	return n, err
+}
+
+// placeholders returns n comma-separated parameter placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,598 @@
+package sqlitestore_test
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bank-system/bank"
+	"bank-system/bank/sqlitestore"
+)
+
+func openStore(t *testing.T, path string) *sqlitestore.Store {
+	t.Helper()
+	store, err := sqlitestore.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func newStore(t *testing.T) *sqlitestore.Store {
+	return openStore(t, filepath.Join(t.TempDir(), "bank.db"))
+}
+
+func inr(s string) bank.Money {
+	return bank.MustParseMoney(s, bank.INR)
+}
+
+// at returns a local time on 1 March 2025, with nanoseconds so that the
+// stored precision is checked too.
+func at(hour, minute int) time.Time {
+	return time.Date(2025, time.March, 1, hour, minute, 0, 123456789, time.Local)
+}
+
+func schemaVersion(t *testing.T, store *sqlitestore.Store) (version, applied int) {
+	t.Helper()
+	err := store.DB().QueryRow(`SELECT MAX(version), COUNT(*) FROM schema_migrations`).Scan(&version, &applied)
+	if err != nil {
+		t.Fatalf("reading schema_migrations: %v", err)
+	}
+	return version, applied
+}
+
+func TestMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.db")
+	store, err := sqlitestore.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	version, applied := schemaVersion(t, store)
+	if version == 0 || applied != version {
+		t.Fatalf("fresh database at version %d with %d migrations applied", version, applied)
+	}
+	// The last migration is in place.
+	if _, err := store.DB().Exec(`SELECT expires_at FROM idempotency_keys`); err != nil {
+		t.Errorf("schema is incomplete: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopening applies nothing twice.
+	store = openStore(t, path)
+	if v, n := schemaVersion(t, store); v != version || n != applied {
+		t.Errorf("after reopening: version %d with %d applied, want %d with %d", v, n, version, applied)
+	}
+
+	// A database written by a newer program is refused.
+	if _, err := store.DB().Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, '')`, version+1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlitestore.Open(path); err == nil || !strings.Contains(err.Error(), "newer than this program supports") {
+		t.Errorf("Open of a newer schema = %v, want an error", err)
+	}
+}
+
+func TestUserRepository(t *testing.T) {
+	users := newStore(t).Users()
+
+	id, err := users.NextID()
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	if next, err := users.NextID(); err != nil || next != id+1 {
+		t.Errorf("NextID = %d, %v; want %d", next, err, id+1)
+	}
+
+	user := bank.User{
+		ID:               id,
+		FirstName:        "Test",
+		LastName:         "User",
+		Email:            "test.user@example.com",
+		PasswordHash:     "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$aGFzaA",
+		Address:          "Motihari, Bihar",
+		Phone:            "9876543210",
+		PanCardNumber:    "ABCPK1234F",
+		AadharCardNumber: "234123412346",
+		Accounts:         []string{"SAV001", "CUR001"},
+		Role:             bank.RoleTeller,
+		DataKey:          "wrapped-key",
+	}
+	if err := users.Save(user); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := users.Get(id)
+	if err != nil || !reflect.DeepEqual(*got, user) {
+		t.Errorf("Get = %+v, %v; want %+v", got, err, user)
+	}
+	got, err = users.GetByEmail(user.Email)
+	if err != nil || got.ID != id {
+		t.Errorf("GetByEmail = %+v, %v", got, err)
+	}
+
+	// Saving again replaces the user and the order of their accounts.
+	user.Accounts = []string{"CUR001"}
+	user.Address = "Patna, Bihar"
+	if err := users.Save(user); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	list, err := users.List()
+	if err != nil || len(list) != 1 || !reflect.DeepEqual(list[0], user) {
+		t.Errorf("List = %+v, %v; want only %+v", list, err, user)
+	}
+
+	if err := users.Delete(id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, err := range []error{users.Delete(id), getErr(users.Get(id)), getErr(users.GetByEmail(user.Email))} {
+		if !errors.Is(err, bank.ErrUserNotFound) {
+			t.Errorf("after Delete: %v, want ErrUserNotFound", err)
+		}
+	}
+}
+
+func getErr[T any](_ T, err error) error {
+	return err
+}
+
+func TestAccountRepository(t *testing.T) {
+	accounts := newStore(t).Accounts()
+
+	account := bank.Account{
+		AccountNumber:         "CUR001",
+		HolderName:            "Test User",
+		Balance:               inr("-250.75"),
+		Currency:              bank.INR,
+		AccountType:           "Current",
+		Status:                bank.StatusDebitFrozen,
+		StatusReason:          "court order",
+		StatusChangedBy:       "user:2",
+		StatusChangedAt:       at(10, 0),
+		CreatedAt:             at(9, 0),
+		UpdatedAt:             at(10, 0),
+		InterestCreditedUntil: at(0, 0),
+		OverdraftLimit:        inr("5000"),
+		HeldAmount:            inr("100"),
+	}
+	if err := accounts.Save(account); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := accounts.Get("CUR001")
+	if err != nil || !reflect.DeepEqual(*got, account) {
+		t.Errorf("Get = %+v, %v; want %+v", got, err, account)
+	}
+
+	account.Balance = inr("0")
+	account.Status = bank.StatusActive
+	if err := accounts.Save(account); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := accounts.Save(bank.Account{AccountNumber: "SAV001", Currency: bank.INR, Balance: inr("1"), CreatedAt: at(9, 0), UpdatedAt: at(9, 0)}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	list, err := accounts.List()
+	if err != nil || len(list) != 2 || !reflect.DeepEqual(list[0], account) || list[1].AccountNumber != "SAV001" {
+		t.Errorf("List = %+v, %v", list, err)
+	}
+
+	if _, err := accounts.Get("NONE"); !errors.Is(err, bank.ErrAccountNotFound) {
+		t.Errorf("Get of a missing account = %v, want ErrAccountNotFound", err)
+	}
+	account.Balance = bank.MustParseMoney("1", bank.USD)
+	if err := accounts.Save(account); !errors.Is(err, bank.ErrCurrencyMismatch) {
+		t.Errorf("Save of a dollar balance in a rupee account = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestJournalRepository(t *testing.T) {
+	journal := newStore(t).Journal()
+
+	if seq, err := journal.NextSequence(); err != nil || seq != 1 {
+		t.Fatalf("NextSequence = %d, %v; want 1", seq, err)
+	}
+	entries := []bank.JournalEntry{
+		{ID: "JE1", Sequence: 1, TransactionID: "TX1", Timestamp: at(9, 0), Description: "deposit", Lines: []bank.JournalLine{
+			{Account: "cash", Side: bank.Debit, Amount: inr("100")},
+			{Account: "SAV001", Side: bank.Credit, Amount: inr("100")},
+		}},
+		{ID: "JE2", Sequence: 2, TransactionID: "TX2", Timestamp: at(9, 5), Description: "transfer", Lines: []bank.JournalLine{
+			{Account: "SAV001", Side: bank.Debit, Amount: inr("30")},
+			{Account: "SAV002", Side: bank.Credit, Amount: inr("30")},
+		}},
+	}
+	for _, entry := range entries {
+		if err := journal.Append(entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	list, err := journal.List()
+	if err != nil || !reflect.DeepEqual(list, entries) {
+		t.Errorf("List = %+v, %v; want %+v", list, err, entries)
+	}
+	byAccount, err := journal.ListByAccount("SAV002")
+	if err != nil || !reflect.DeepEqual(byAccount, entries[1:]) {
+		t.Errorf("ListByAccount(SAV002) = %+v, %v; want %+v", byAccount, err, entries[1:])
+	}
+	totals, err := journal.Totals("SAV001")
+	if err != nil || !totals.Debits.Equal(inr("30")) || !totals.Credits.Equal(inr("100")) {
+		t.Errorf("Totals(SAV001) = %+v, %v", totals, err)
+	}
+	all, err := journal.AllTotals()
+	if err != nil || len(all) != 3 || !all["cash"].Debits.Equal(inr("100")) || !all["SAV002"].Credits.Equal(inr("30")) {
+		t.Errorf("AllTotals = %+v, %v", all, err)
+	}
+
+	if err := journal.Append(bank.JournalEntry{ID: "JE1", Sequence: 3, Timestamp: at(9, 10), Lines: entries[0].Lines}); err == nil {
+		t.Error("Append of an existing entry ID succeeded")
+	}
+	if list, _ := journal.List(); len(list) != 2 {
+		t.Errorf("a failed Append left %d entries, want 2", len(list))
+	}
+}
+
+func TestAuditRepository(t *testing.T) {
+	audit := newStore(t).Audit()
+
+	if last, err := audit.Last(); last != nil || err != nil {
+		t.Fatalf("Last of an empty trail = %+v, %v; want nil", last, err)
+	}
+	entries := []bank.AuditEntry{
+		{Sequence: 1, Timestamp: at(9, 0), Actor: "system", Action: "user.create", Resource: "user:1",
+			After: json.RawMessage(`{"ID":1}`), Hash: "h1"},
+		{Sequence: 2, Timestamp: at(9, 1), Actor: "user:1", Action: "user.update", Resource: "user:1",
+			Before: json.RawMessage(`{"ID":1}`), After: json.RawMessage(`{"ID":1,"Role":"admin"}`), PrevHash: "h1", Hash: "h2"},
+	}
+	for _, entry := range entries {
+		if err := audit.Append(entry); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if last, err := audit.Last(); err != nil || !reflect.DeepEqual(*last, entries[1]) {
+		t.Errorf("Last = %+v, %v; want %+v", last, err, entries[1])
+	}
+	if list, err := audit.List(); err != nil || !reflect.DeepEqual(list, entries) {
+		t.Errorf("List = %+v, %v; want %+v", list, err, entries)
+	}
+}
+
+func TestStandingInstructionRepository(t *testing.T) {
+	instructions := newStore(t).StandingInstructions()
+
+	id, err := instructions.NextID()
+	if err != nil {
+		t.Fatalf("NextID: %v", err)
+	}
+	instruction := bank.StandingInstruction{
+		ID:          id,
+		FromAccount: "SAV001",
+		ToAccount:   "SAV002",
+		Amount:      inr("1500"),
+		Description: "rent",
+		Recurrence:  bank.Recurrence{Frequency: bank.Monthly, Start: at(0, 0), DayOfMonth: 31},
+		Status:      bank.InstructionActive,
+		NextRun:     at(0, 0),
+		RetryAt:     at(6, 0),
+		Retries:     2,
+		CreatedBy:   "user:1",
+		CreatedAt:   at(8, 0),
+		UpdatedAt:   at(8, 30),
+	}
+	if err := instructions.Save(instruction); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := instructions.Get(id)
+	if err != nil || !reflect.DeepEqual(*got, instruction) {
+		t.Errorf("Get = %+v, %v; want %+v", got, err, instruction)
+	}
+	if _, err := instructions.Get(id + 1); !errors.Is(err, bank.ErrInstructionNotFound) {
+		t.Errorf("Get of a missing instruction = %v, want ErrInstructionNotFound", err)
+	}
+
+	attempts := []bank.InstructionAttempt{
+		{InstructionID: id, Sequence: 1, ScheduledFor: at(0, 0), AttemptedAt: at(0, 1), Outcome: bank.AttemptRetrying, Error: "insufficient funds"},
+		{InstructionID: id, Sequence: 2, ScheduledFor: at(0, 0), AttemptedAt: at(6, 1), Outcome: bank.AttemptSucceeded, TransactionID: "TX1"},
+	}
+	for _, attempt := range attempts {
+		if err := instructions.AppendAttempt(attempt); err != nil {
+			t.Fatalf("AppendAttempt: %v", err)
+		}
+	}
+	if got, err := instructions.ListAttempts(id); err != nil || !reflect.DeepEqual(got, attempts) {
+		t.Errorf("ListAttempts = %+v, %v; want %+v", got, err, attempts)
+	}
+	if err := instructions.AppendAttempt(bank.InstructionAttempt{InstructionID: id + 1, Sequence: 1, ScheduledFor: at(0, 0), AttemptedAt: at(0, 0)}); err == nil {
+		t.Error("AppendAttempt for a missing instruction succeeded")
+	}
+
+	instruction.Status = bank.InstructionCompleted
+	instruction.NextRun = time.Time{}
+	instruction.RetryAt = time.Time{}
+	if err := instructions.Save(instruction); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if list, err := instructions.List(); err != nil || len(list) != 1 || !reflect.DeepEqual(list[0], instruction) {
+		t.Errorf("List = %+v, %v; want only %+v", list, err, instruction)
+	}
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	idempotency := newStore(t).Idempotency()
+
+	if record, err := idempotency.Get("user:1", "k1"); record != nil || err != nil {
+		t.Fatalf("Get of an unused key = %+v, %v; want nil", record, err)
+	}
+	records := []bank.IdempotencyRecord{
+		{Actor: "user:1", Key: "k1", RequestHash: "r1", TransactionID: "TX1", CreatedAt: at(9, 0), ExpiresAt: at(10, 0)},
+		{Actor: "user:1", Key: "k2", RequestHash: "r2", TransactionID: "TX2", CreatedAt: at(9, 0), ExpiresAt: at(12, 0)},
+		// The same key used by someone else is a different record.
+		{Actor: "user:2", Key: "k1", RequestHash: "r3", TransactionID: "TX3", CreatedAt: at(9, 0), ExpiresAt: at(11, 0)},
+	}
+	for _, record := range records {
+		if err := idempotency.Save(record); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	for _, record := range records {
+		if got, err := idempotency.Get(record.Actor, record.Key); err != nil || !reflect.DeepEqual(*got, record) {
+			t.Errorf("Get(%s, %s) = %+v, %v; want %+v", record.Actor, record.Key, got, err, record)
+		}
+	}
+
+	// Records expire at ExpiresAt itself.
+	if n, err := idempotency.Purge(at(11, 0)); err != nil || n != 2 {
+		t.Errorf("Purge = %d, %v; want 2", n, err)
+	}
+	if got, _ := idempotency.Get("user:1", "k2"); got == nil {
+		t.Error("Purge removed a record that had not expired")
+	}
+	if got, _ := idempotency.Get("user:2", "k1"); got != nil {
+		t.Error("Purge kept an expired record")
+	}
+}
+
+func TestTransactionQuery(t *testing.T) {
+	transactions := newStore(t).Transactions()
+
+	all := []bank.Transaction{
+		{ID: "T1", Type: bank.Deposit, Status: bank.Completed, ToAccount: "A", Amount: inr("100"), BalanceAfter: inr("100"),
+			Timestamp: at(9, 0), Description: "Cash deposit", ReferenceNumber: "REF1"},
+		{ID: "T2", Type: bank.Transfer, Status: bank.Completed, FromAccount: "A", ToAccount: "B", Amount: inr("40"), Fee: inr("5"),
+			BalanceAfter: inr("55"), Timestamp: at(9, 5), Description: "Rent", ReferenceNumber: "REF2"},
+		{ID: "T3", Type: bank.Fee, Status: bank.Completed, FromAccount: "A", Amount: inr("5"), BalanceAfter: inr("55"),
+			Timestamp: at(9, 5), Description: "transfer fee for T2", ReferenceNumber: "REF3", LinkedTransactionID: "T2"},
+		{ID: "T4", Type: bank.Hold, Status: bank.Pending, FromAccount: "B", Amount: inr("10"), Fee: inr("0"), BalanceAfter: inr("0"),
+			Timestamp: at(9, 10), Description: "Card authorization", ReferenceNumber: "REF4", ExpiresAt: at(12, 0)},
+		{ID: "T5", Type: bank.Deposit, Status: bank.Completed, ToAccount: "C", Amount: bank.MustParseMoney("7", bank.USD),
+			BalanceAfter: bank.MustParseMoney("7", bank.USD), Timestamp: at(9, 15), Description: "Wire", ReferenceNumber: "REF5"},
+	}
+	for _, transaction := range all {
+		if err := transactions.Create(transaction); err != nil {
+			t.Fatalf("Create(%s): %v", transaction.ID, err)
+		}
+	}
+	clash := all[0]
+	clash.Amount = inr("999")
+	if err := transactions.Create(clash); !errors.Is(err, bank.ErrTransactionExists) {
+		t.Errorf("Create of an existing ID = %v, want ErrTransactionExists", err)
+	}
+	if got, _ := transactions.Get("T1"); !got.Amount.Equal(inr("100")) {
+		t.Errorf("a refused Create left T1 at %s, want 100.00", got.Amount)
+	}
+	if got, err := transactions.Get("T4"); err != nil || !reflect.DeepEqual(*got, all[3]) {
+		t.Errorf("Get(T4) = %+v, %v; want %+v", got, err, all[3])
+	}
+	if _, err := transactions.Get("T9"); !errors.Is(err, bank.ErrTransactionNotFound) {
+		t.Errorf("Get of a missing transaction = %v, want ErrTransactionNotFound", err)
+	}
+	mixed := all[0]
+	mixed.Fee = bank.MustParseMoney("1", bank.USD)
+	if err := transactions.Save(mixed); !errors.Is(err, bank.ErrCurrencyMismatch) {
+		t.Errorf("Save of a transaction in two currencies = %v, want ErrCurrencyMismatch", err)
+	}
+
+	low, high := inr("10"), inr("50")
+	tests := []struct {
+		name  string
+		query bank.TransactionQuery
+		want  []string
+	}{
+		{"everything", bank.TransactionQuery{}, []string{"T1", "T2", "T3", "T4", "T5"}},
+		{"account on either side", bank.TransactionQuery{Accounts: []string{"B"}}, []string{"T2", "T4"}},
+		{"several accounts", bank.TransactionQuery{Accounts: []string{"B", "C"}}, []string{"T2", "T4", "T5"}},
+		{"type", bank.TransactionQuery{Types: []bank.TransactionType{bank.Deposit, bank.Fee}}, []string{"T1", "T3", "T5"}},
+		{"status", bank.TransactionQuery{Statuses: []bank.TransactionStatus{bank.Pending}}, []string{"T4"}},
+		{"amount range in one currency", bank.TransactionQuery{MinAmount: &low, MaxAmount: &high}, []string{"T2", "T4"}},
+		{"since is inclusive, until exclusive", bank.TransactionQuery{Since: at(9, 5), Until: at(9, 10)}, []string{"T2", "T3"}},
+		{"reference", bank.TransactionQuery{ReferenceNumber: "REF3"}, []string{"T3"}},
+		{"text ignores case", bank.TransactionQuery{Text: "RENT"}, []string{"T2"}},
+		{"text matches reference", bank.TransactionQuery{Text: "ref5"}, []string{"T5"}},
+		{"newest first", bank.TransactionQuery{NewestFirst: true, Limit: 3}, []string{"T5", "T4", "T3"}},
+		{"after ties on timestamp", bank.TransactionQuery{After: &bank.TransactionPosition{Timestamp: at(9, 5), ID: "T2"}, Limit: 2}, []string{"T3", "T4"}},
+		{"after, newest first", bank.TransactionQuery{After: &bank.TransactionPosition{Timestamp: at(9, 5), ID: "T3"}, NewestFirst: true}, []string{"T2", "T1"}},
+		{"no match", bank.TransactionQuery{Accounts: []string{"Z"}}, []string{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := transactions.Query(tc.query)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			ids := []string{}
+			for _, transaction := range got {
+				ids = append(ids, transaction.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.want) {
+				t.Errorf("Query = %v, want %v", ids, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRollsBack(t *testing.T) {
+	store := newStore(t)
+	account := bank.Account{AccountNumber: "SAV001", Currency: bank.INR, Balance: inr("100"), CreatedAt: at(9, 0), UpdatedAt: at(9, 0)}
+	if err := store.Accounts().Save(account); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("failure")
+	err := store.Update(func(tx bank.Store) error {
+		changed := account
+		changed.Balance = inr("0")
+		if err := tx.Accounts().Save(changed); err != nil {
+			return err
+		}
+		if err := tx.Transactions().Save(bank.Transaction{ID: "T1", Type: bank.Withdrawal, Status: bank.Completed,
+			FromAccount: "SAV001", Amount: inr("100"), Timestamp: at(9, 1)}); err != nil {
+			return err
+		}
+		// Writes are visible inside the unit of work, including through a
+		// nested Update.
+		return tx.Update(func(tx bank.Store) error {
+			if got, err := tx.Accounts().Get("SAV001"); err != nil || !got.Balance.IsZero() {
+				t.Errorf("inside Update: balance %v, %v; want 0", got, err)
+			}
+			return failure
+		})
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Update = %v, want the error fn returned", err)
+	}
+
+	if got, err := store.Accounts().Get("SAV001"); err != nil || !got.Balance.Equal(inr("100")) {
+		t.Errorf("balance after rollback = %v, %v; want 100", got.Balance, err)
+	}
+	if _, err := store.Transactions().Get("T1"); !errors.Is(err, bank.ErrTransactionNotFound) {
+		t.Errorf("transaction after rollback: %v, want ErrTransactionNotFound", err)
+	}
+}
+
+// snapshot is what a failed operation must leave unchanged.
+type snapshot struct {
+	balances     map[string]bank.Money
+	transactions int
+	journal      int
+}
+
+func takeSnapshot(t *testing.T, bs *bank.BankingSystem, store bank.Store, accounts ...string) snapshot {
+	t.Helper()
+	s := snapshot{balances: make(map[string]bank.Money)}
+	for _, account := range accounts {
+		balance, err := bs.GetBalance(account)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		s.balances[account] = balance
+	}
+	transactions, err := store.Transactions().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := store.Journal().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.transactions, s.journal = len(transactions), len(journal)
+	return s
+}
+
+func TestFailedTransferRollsBack(t *testing.T) {
+	store := newStore(t)
+	bs := bank.NewBankingSystem(bank.WithStore(store))
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	for _, number := range []string{"SAV001", "SAV002"} {
+		if _, err := bs.CreateAccount(number, "Test User", "Savings", user.ID); err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+	}
+	if _, err := bs.Deposit("SAV001", inr("5000")); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+
+	// The default schedule charges for transfers after five a month.
+	for range 5 {
+		if _, err := bs.Transfer("SAV001", "SAV002", inr("100")); err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+	}
+	before := takeSnapshot(t, bs, store, "SAV001", "SAV002")
+
+	// The transfer itself fits the balance, but its fee does not, so the
+	// balances, transaction and journal entry it already wrote go too.
+	if _, err := bs.Transfer("SAV001", "SAV002", before.balances["SAV001"]); !errors.Is(err, bank.ErrInsufficientFunds) {
+		t.Fatalf("Transfer of the whole balance = %v, want ErrInsufficientFunds from the fee", err)
+	}
+	if after := takeSnapshot(t, bs, store, "SAV001", "SAV002"); !reflect.DeepEqual(after, before) {
+		t.Errorf("after the failed transfer %+v, want %+v", after, before)
+	}
+	if discrepancies, err := bs.ReconcileLedger(); err != nil || len(discrepancies) != 0 {
+		t.Errorf("ReconcileLedger = %v, %v", discrepancies, err)
+	}
+
+	// A transfer that leaves room for the fee goes through.
+	if _, err := bs.Transfer("SAV001", "SAV002", inr("100")); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if after := takeSnapshot(t, bs, store, "SAV001"); !after.balances["SAV001"].Equal(inr("4395")) || after.transactions != before.transactions+2 {
+		t.Errorf("after a transfer with a fee: %+v", after)
+	}
+}
+
+func TestBankingSystemSurvivesReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.db")
+	store, err := sqlitestore.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	bs := bank.NewBankingSystem(bank.WithStore(store))
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	for _, number := range []string{"SAV001", "SAV002"} {
+		if _, err := bs.CreateAccount(number, "Test User", "Savings", user.ID); err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+	}
+	if _, err := bs.Deposit("SAV001", inr("5000")); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	if _, err := bs.Transfer("SAV001", "SAV002", inr("1200")); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	// A transfer the balance cannot cover leaves nothing behind.
+	if _, err := bs.Transfer("SAV001", "SAV002", inr("10000")); !errors.Is(err, bank.ErrInsufficientFunds) {
+		t.Fatalf("Transfer of more than the balance = %v, want ErrInsufficientFunds", err)
+	}
+	if err := bs.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened := bank.NewBankingSystem(bank.WithStore(openStore(t, path)))
+	for number, want := range map[string]bank.Money{"SAV001": inr("3800"), "SAV002": inr("1200")} {
+		if balance, err := reopened.GetBalance(number); err != nil || !balance.Equal(want) {
+			t.Errorf("GetBalance(%s) = %s, %v; want %s", number, balance, err, want)
+		}
+	}
+	if user, err := reopened.GetUser(user.ID); err != nil || len(user.Accounts) != 2 {
+		t.Errorf("GetUser = %+v, %v; want a user with both accounts", user, err)
+	}
+	if tb, err := reopened.GetTrialBalance(); err != nil || !tb.IsBalanced() || len(tb.Rows) != 3 {
+		t.Errorf("trial balance %+v, %v; want three balanced rows", tb, err)
+	}
+	if discrepancies, err := reopened.ReconcileLedger(); err != nil || len(discrepancies) != 0 {
+		t.Errorf("ReconcileLedger = %v, %v", discrepancies, err)
+	}
+}
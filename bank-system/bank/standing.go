@@ -0,0 +1,411 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInstructionNotFound = errors.New("standing instruction not found")
+	ErrInstructionStatus   = errors.New("standing instruction status does not allow this")
+)
+
+// Frequency is how often a standing instruction runs.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+func ParseFrequency(s string) (Frequency, error) {
+	for _, f := range []Frequency{Daily, Weekly, Monthly} {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown frequency %q", ErrInvalidInput, s)
+}
+
+// Recurrence says when a standing instruction runs. Every run is at Start's
+// time of day, and weekly runs fall on Start's weekday.
+type Recurrence struct {
+	Frequency Frequency
+	Start     time.Time
+	// DayOfMonth is the day monthly runs fall on, from 1 to 31. In months
+	// without that day the run is on the last day. Zero means Start's day.
+	DayOfMonth int
+	// End, if set, is the time after which there are no more runs.
+	End time.Time
+}
+
+func (r Recurrence) validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly:
+	default:
+		return fmt.Errorf("%w: unknown frequency %q", ErrInvalidInput, r.Frequency)
+	}
+	if r.DayOfMonth < 0 || r.DayOfMonth > 31 {
+		return fmt.Errorf("%w: day of month %d", ErrInvalidInput, r.DayOfMonth)
+	}
+	if r.DayOfMonth != 0 && r.Frequency != Monthly {
+		return fmt.Errorf("%w: a day of month only applies to monthly instructions", ErrInvalidInput)
+	}
+	if !r.End.IsZero() && r.End.Before(r.first()) {
+		return fmt.Errorf("%w: the instruction ends before its first run", ErrInvalidInput)
+	}
+	return nil
+}
+
+// first returns the first run, which is Start unless a monthly instruction
+// runs on another day of the month.
+func (r Recurrence) first() time.Time {
+	if r.Frequency != Monthly || r.DayOfMonth == 0 {
+		return r.Start
+	}
+	run := r.onDay(r.Start.Year(), r.Start.Month())
+	if run.Before(r.Start) {
+		run = r.onDay(r.Start.Year(), r.Start.Month()+1)
+	}
+	return run
+}
+
+// after returns the run that follows run.
+func (r Recurrence) after(run time.Time) time.Time {
+	switch r.Frequency {
+	case Daily:
+		return run.AddDate(0, 0, 1)
+	case Weekly:
+		return run.AddDate(0, 0, 7)
+	default:
+		return r.onDay(run.Year(), run.Month()+1)
+	}
+}
+
+// onDay returns the monthly run in the given month. Months past December
+// roll over into the next year.
+func (r Recurrence) onDay(year int, month time.Month) time.Time {
+	day := r.DayOfMonth
+	if day == 0 {
+		day = r.Start.Day()
+	}
+	loc := r.Start.Location()
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	return time.Date(year, month, min(day, last), r.Start.Hour(), r.Start.Minute(), r.Start.Second(), r.Start.Nanosecond(), loc)
+}
+
+// InstructionStatus is where a standing instruction is in its life.
+type InstructionStatus string
+
+const (
+	InstructionActive    InstructionStatus = "ACTIVE"
+	InstructionPaused    InstructionStatus = "PAUSED"
+	InstructionCancelled InstructionStatus = "CANCELLED"
+	// InstructionCompleted instructions have passed their end date.
+	InstructionCompleted InstructionStatus = "COMPLETED"
+)
+
+// StandingInstruction is a transfer the bank makes on the customer's behalf
+// on a schedule, such as a monthly rent payment.
+type StandingInstruction struct {
+	ID          int
+	FromAccount string
+	ToAccount   string
+	Amount      Money
+	Description string
+	Recurrence  Recurrence
+	Status      InstructionStatus
+	// NextRun is the run that is due next. It is zero once the instruction
+	// has completed.
+	NextRun time.Time
+	// RetryAt is set while a run that failed for lack of funds waits to be
+	// tried again, and Retries counts the times it has been.
+	RetryAt   time.Time
+	Retries   int
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// dueAt returns when the instruction should next be tried.
+func (i StandingInstruction) dueAt() time.Time {
+	if !i.RetryAt.IsZero() {
+		return i.RetryAt
+	}
+	return i.NextRun
+}
+
+// advance moves on to the run after NextRun, completing the instruction if
+// that is past its end.
+func (i *StandingInstruction) advance() {
+	i.NextRun = i.Recurrence.after(i.NextRun)
+	i.RetryAt = time.Time{}
+	i.Retries = 0
+	if end := i.Recurrence.End; !end.IsZero() && i.NextRun.After(end) {
+		i.Status = InstructionCompleted
+		i.NextRun = time.Time{}
+	}
+}
+
+func (i StandingInstruction) DisplayStandingInstruction() {
+	fmt.Println("=== Standing Instruction ===")
+	fmt.Printf("ID: %d\n", i.ID)
+	fmt.Printf("From: %s\n", i.FromAccount)
+	fmt.Printf("To: %s\n", i.ToAccount)
+	fmt.Printf("Amount: %s\n", i.Amount)
+	if i.Description != "" {
+		fmt.Printf("Description: %s\n", i.Description)
+	}
+	fmt.Printf("Frequency: %s\n", i.Recurrence.Frequency)
+	if i.Recurrence.DayOfMonth != 0 {
+		fmt.Printf("Day of Month: %d\n", i.Recurrence.DayOfMonth)
+	}
+	if !i.Recurrence.End.IsZero() {
+		fmt.Printf("Ends: %s\n", i.Recurrence.End.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Printf("Status: %s\n", i.Status)
+	if !i.NextRun.IsZero() {
+		fmt.Printf("Next Run: %s\n", i.NextRun.Format("2006-01-02 15:04:05"))
+	}
+	if !i.RetryAt.IsZero() {
+		fmt.Printf("Retry %d At: %s\n", i.Retries, i.RetryAt.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Println("----------------------------")
+}
+
+// AttemptOutcome is what came of one attempt to run a standing instruction.
+type AttemptOutcome string
+
+const (
+	AttemptSucceeded AttemptOutcome = "SUCCEEDED"
+	// AttemptRetrying attempts failed for lack of funds and will be tried
+	// again.
+	AttemptRetrying AttemptOutcome = "RETRYING"
+	// AttemptFailed attempts will not be retried; the instruction moves on
+	// to its next run.
+	AttemptFailed AttemptOutcome = "FAILED"
+)
+
+// InstructionAttempt records one attempt to run a standing instruction.
+type InstructionAttempt struct {
+	InstructionID int
+	Sequence      int
+	// ScheduledFor is the run the attempt was for.
+	ScheduledFor  time.Time
+	AttemptedAt   time.Time
+	Outcome       AttemptOutcome
+	TransactionID string
+	Error         string
+}
+
+func instructionResource(instruction *StandingInstruction) resource {
+	return accountResource(instruction.FromAccount)
+}
+
+// instructionKey names a standing instruction in the lock table and the
+// audit trail.
+func instructionKey(id int) string {
+	return "standing_instruction:" + strconv.Itoa(id)
+}
+
+// CreateStandingInstruction sets up a transfer that runs on a schedule from
+// recurrence.Start, or from now if Start is zero. Only those who may
+// transfer from the paying account can create one.
+func (bs *BankingSystem) CreateStandingInstruction(fromAccount, toAccount string, amount Money, description string, recurrence Recurrence) (*StandingInstruction, error) {
+	if fromAccount == toAccount {
+		return nil, ErrSameAccount
+	}
+	if err := bs.authorize(ActionTransfer, accountResource(fromAccount)); err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+
+	now := bs.clock.Now()
+	if recurrence.Start.IsZero() {
+		recurrence.Start = now
+	} else if recurrence.Start.Before(now) {
+		return nil, fmt.Errorf("%w: a standing instruction cannot start in the past", ErrInvalidInput)
+	}
+	if err := recurrence.validate(); err != nil {
+		return nil, err
+	}
+
+	var created *StandingInstruction
+	err := bs.update(func(s *services) error {
+		from, err := s.accounts.GetAccountDetails(fromAccount)
+		if err != nil {
+			return err
+		}
+		if _, err := s.accounts.GetAccountDetails(toAccount); err != nil {
+			return err
+		}
+		if amount.Currency() != from.Currency {
+			return fmt.Errorf("%w: %s instruction from a %s account", ErrCurrencyMismatch, amount.Currency(), from.Currency)
+		}
+
+		id, err := s.instructions.NextID()
+		if err != nil {
+			return err
+		}
+		instruction := StandingInstruction{
+			ID:          id,
+			FromAccount: fromAccount,
+			ToAccount:   toAccount,
+			Amount:      amount,
+			Description: description,
+			Recurrence:  recurrence,
+			Status:      InstructionActive,
+			NextRun:     recurrence.first(),
+			CreatedBy:   s.audit.actor,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.instructions.Save(instruction); err != nil {
+			return err
+		}
+
+		s.audit.record(AuditCreateInstruction, instructionKey(id), nil, instruction)
+		created = &instruction
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (bs *BankingSystem) GetStandingInstruction(id int) (*StandingInstruction, error) {
+	instruction, err := bs.instructions.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.authorize(ActionViewAccount, instructionResource(instruction)); err != nil {
+		return nil, err
+	}
+	return instruction, nil
+}
+
+// ListStandingInstructions returns the standing instructions paid from
+// accounts the caller may see, ordered by ID.
+func (bs *BankingSystem) ListStandingInstructions() ([]StandingInstruction, error) {
+	allowed, err := bs.authorizer()
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := bs.instructions.List()
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []StandingInstruction{}
+	for _, instruction := range instructions {
+		if allowed(ActionViewAccount, instructionResource(&instruction)) == nil {
+			visible = append(visible, instruction)
+		}
+	}
+	return visible, nil
+}
+
+// ListInstructionAttempts returns every attempt to run a standing
+// instruction, oldest first.
+func (bs *BankingSystem) ListInstructionAttempts(id int) ([]InstructionAttempt, error) {
+	if _, err := bs.GetStandingInstruction(id); err != nil {
+		return nil, err
+	}
+
+	attempts, err := bs.instructions.ListAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+	if attempts == nil {
+		attempts = []InstructionAttempt{}
+	}
+	return attempts, nil
+}
+
+// PauseStandingInstruction stops an active instruction from running until
+// it is resumed.
+func (bs *BankingSystem) PauseStandingInstruction(id int) (*StandingInstruction, error) {
+	return bs.changeInstruction(id, AuditPauseInstruction, func(instruction *StandingInstruction, now time.Time) error {
+		if instruction.Status != InstructionActive {
+			return fmt.Errorf("%w: cannot pause a %s instruction", ErrInstructionStatus, instruction.Status)
+		}
+		instruction.Status = InstructionPaused
+		return nil
+	})
+}
+
+// ResumeStandingInstruction restarts a paused instruction. Runs that fell
+// due while it was paused are skipped.
+func (bs *BankingSystem) ResumeStandingInstruction(id int) (*StandingInstruction, error) {
+	return bs.changeInstruction(id, AuditResumeInstruction, func(instruction *StandingInstruction, now time.Time) error {
+		if instruction.Status != InstructionPaused {
+			return fmt.Errorf("%w: cannot resume a %s instruction", ErrInstructionStatus, instruction.Status)
+		}
+		instruction.Status = InstructionActive
+		instruction.RetryAt, instruction.Retries = time.Time{}, 0
+		for instruction.Status == InstructionActive && instruction.NextRun.Before(now) {
+			instruction.advance()
+		}
+		return nil
+	})
+}
+
+// CancelStandingInstruction stops an instruction for good.
+func (bs *BankingSystem) CancelStandingInstruction(id int) (*StandingInstruction, error) {
+	return bs.changeInstruction(id, AuditCancelInstruction, func(instruction *StandingInstruction, now time.Time) error {
+		if instruction.Status != InstructionActive && instruction.Status != InstructionPaused {
+			return fmt.Errorf("%w: cannot cancel a %s instruction", ErrInstructionStatus, instruction.Status)
+		}
+		instruction.Status = InstructionCancelled
+		instruction.NextRun, instruction.RetryAt = time.Time{}, time.Time{}
+		return nil
+	})
+}
+
+// changeInstruction applies fn to a standing instruction under its lock and
+// records the change in the audit trail under action.
+func (bs *BankingSystem) changeInstruction(id int, action string, fn func(instruction *StandingInstruction, now time.Time) error) (*StandingInstruction, error) {
+	instruction, err := bs.instructions.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.authorize(ActionTransfer, instructionResource(instruction)); err != nil {
+		return nil, err
+	}
+
+	unlock := bs.accountLocks.lock(instructionKey(id))
+	defer unlock()
+
+	var changed *StandingInstruction
+	err = bs.update(func(s *services) error {
+		before, err := s.instructions.Get(id)
+		if err != nil {
+			return err
+		}
+		after := *before
+		now := s.clock.Now()
+		if err := fn(&after, now); err != nil {
+			return err
+		}
+		after.UpdatedAt = now
+		if err := s.instructions.Save(after); err != nil {
+			return err
+		}
+
+		s.audit.record(action, instructionKey(id), before, after)
+		changed = &after
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,184 @@
+package bank
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Statement is an account's activity over a period, as posted to the
+// ledger: the balance it started with, every entry in date order with the
+// balance after it, and the balance it ended with.
+type Statement struct {
+	AccountNumber string
+	HolderName    string
+	AccountType   string
+	Currency      Currency
+	// From and To bound the period: From inclusively, To exclusively.
+	From           time.Time
+	To             time.Time
+	OpeningBalance Money
+	Entries        []StatementEntry
+	ClosingBalance Money
+	// TotalCredits is the money that came into the account during the
+	// period, and TotalDebits the money that left it.
+	TotalCredits Money
+	TotalDebits  Money
+	GeneratedAt  time.Time
+}
+
+// StatementEntry is one line of a statement. Exactly one of Credit and Debit
+// is positive.
+type StatementEntry struct {
+	Date            time.Time
+	TransactionID   string
+	ReferenceNumber string
+	Type            TransactionType
+	Description     string
+	Credit          Money
+	Debit           Money
+	// Balance is the account's balance after the entry.
+	Balance Money
+}
+
+// GenerateStatement returns the statement of an account for the period from
+// from up to, but not including, to. Times on the statement are in from's
+// location.
+func (bs *BankingSystem) GenerateStatement(accountNumber string, from, to time.Time) (*Statement, error) {
+	if err := bs.authorize(ActionViewAccount, accountResource(accountNumber)); err != nil {
+		return nil, err
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: statement period must end after it starts", ErrInvalidInput)
+	}
+
+	// Hold the account still so the entries and the transactions they
+	// belong to agree.
+	unlock := bs.accountLocks.lock(accountNumber)
+	defer unlock()
+
+	account, err := bs.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := from.Location()
+	st := &Statement{
+		AccountNumber:  account.AccountNumber,
+		HolderName:     account.HolderName,
+		AccountType:    account.AccountType,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: Zero(account.Currency),
+		Entries:        []StatementEntry{},
+		TotalCredits:   Zero(account.Currency),
+		TotalDebits:    Zero(account.Currency),
+		GeneratedAt:    bs.clock.Now().In(loc),
+	}
+
+	entries, err := bs.ledger.GetEntries(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	// Interest is posted after the period it is for has ended, dated at its
+	// end, so posting order is not date order.
+	slices.SortFunc(entries, func(a, b JournalEntry) int {
+		return cmp.Or(a.Timestamp.Compare(b.Timestamp), cmp.Compare(a.Sequence, b.Sequence))
+	})
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(to) {
+			continue
+		}
+		net, err := netCredit(entry, accountNumber, account.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Timestamp.Before(from) {
+			if st.OpeningBalance, err = st.OpeningBalance.Add(net); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if net.IsZero() {
+			continue
+		}
+
+		line := StatementEntry{
+			Date:          entry.Timestamp.In(loc),
+			TransactionID: entry.TransactionID,
+			Description:   entry.Description,
+			Credit:        Zero(account.Currency),
+			Debit:         Zero(account.Currency),
+		}
+		if transaction, err := bs.transactions.GetTransaction(entry.TransactionID); err == nil {
+			line.ReferenceNumber = transaction.ReferenceNumber
+			line.Type = transaction.Type
+			if line.Description == "" {
+				line.Description = transaction.Description
+			}
+		}
+		if net.IsPositive() {
+			line.Credit = net
+			st.TotalCredits, err = st.TotalCredits.Add(net)
+		} else if line.Debit, err = net.Neg(); err == nil {
+			st.TotalDebits, err = st.TotalDebits.Add(line.Debit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		st.Entries = append(st.Entries, line)
+	}
+
+	balance := st.OpeningBalance
+	for i := range st.Entries {
+		if balance, err = balance.Add(st.Entries[i].Credit); err != nil {
+			return nil, err
+		}
+		if balance, err = balance.Sub(st.Entries[i].Debit); err != nil {
+			return nil, err
+		}
+		st.Entries[i].Balance = balance
+	}
+	st.ClosingBalance = balance
+
+	return st, nil
+}
+
+// netCredit is how much a journal entry added to a customer account: its
+// credits to the account less its debits.
+func netCredit(entry JournalEntry, accountNumber string, currency Currency) (Money, error) {
+	net := Zero(currency)
+	for _, line := range entry.Lines {
+		if line.Account != accountNumber {
+			continue
+		}
+		var err error
+		if line.Side == Credit {
+			net, err = net.Add(line.Amount)
+		} else {
+			net, err = net.Sub(line.Amount)
+		}
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	return net, nil
+}
+
+// MonthlyStatement returns the statement of an account for a calendar month
+// that has ended. Months run in the location of the system's clock.
+func (bs *BankingSystem) MonthlyStatement(accountNumber string, year int, month time.Month) (*Statement, error) {
+	if month < time.January || month > time.December {
+		return nil, fmt.Errorf("%w: month %d", ErrInvalidInput, month)
+	}
+
+	now := bs.clock.Now()
+	from := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 1, 0)
+	if to.After(now) {
+		return nil, fmt.Errorf("%w: %s has not ended yet", ErrInvalidInput, from.Format("January 2006"))
+	}
+	return bs.GenerateStatement(accountNumber, from, to)
+}
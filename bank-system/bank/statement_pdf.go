@@ -0,0 +1,126 @@
+package bank
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A PDF statement is the plain text table set in Courier on A4 pages, so
+// the columns line up without font metrics. The document uses only the
+// standard fonts every PDF reader has, and nothing is compressed.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+	pdfFontSize   = 8
+	pdfLeading    = 11
+	pdfTitleSize  = 14
+)
+
+// pdfLinesPerPage is how many lines of text fit between the title and the
+// footer.
+const pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin - 2*pdfTitleSize - 2*pdfLeading) / pdfLeading
+
+// RenderPDF writes the statement as a PDF document. Each page repeats the
+// table heading and is numbered.
+func (st *Statement) RenderPDF(w io.Writer) error {
+	heading, rows := st.tableLines()
+
+	var pages [][]string
+	page := append(st.summaryLines(), "")
+	page = append(page, heading...)
+	for _, row := range rows {
+		if len(page) == pdfLinesPerPage {
+			pages = append(pages, page)
+			page = append([]string{}, heading...)
+		}
+		page = append(page, row)
+	}
+	pages = append(pages, page)
+
+	title := fmt.Sprintf("Account Statement %s", st.AccountNumber)
+
+	// Objects 1 to 4 are the catalog, the page tree and the two fonts;
+	// each page then takes two more, itself and its content.
+	doc := &pdfDocument{}
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	doc.object("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	doc.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	doc.object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		var content bytes.Buffer
+		top := pdfPageHeight - pdfMargin - pdfTitleSize
+		fmt.Fprintf(&content, "BT /F2 %d Tf %d %d Td (%s) Tj ET\n", pdfTitleSize, pdfMargin, top, pdfString(title))
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, top-pdfTitleSize-pdfLeading)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdfString(line))
+		}
+		content.WriteString("ET\n")
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(pages))
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", pdfFontSize, pdfMargin, pdfMargin/2, pdfString(footer))
+
+		doc.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		doc.object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	_, err := w.Write(doc.finish())
+	return err
+}
+
+// pdfDocument accumulates the numbered objects of a PDF file in order.
+type pdfDocument struct {
+	buf bytes.Buffer
+	// offsets[i] is where object i+1 starts.
+	offsets []int
+}
+
+func (d *pdfDocument) object(body string) {
+	if d.buf.Len() == 0 {
+		// The comment of high bytes tells tools the file is binary.
+		d.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	}
+	d.offsets = append(d.offsets, d.buf.Len())
+	fmt.Fprintf(&d.buf, "%d 0 obj\n%s\nendobj\n", len(d.offsets), body)
+}
+
+// finish appends the cross-reference table and the trailer, and returns the
+// whole file.
+func (d *pdfDocument) finish() []byte {
+	xref := d.buf.Len()
+	fmt.Fprintf(&d.buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.offsets)+1)
+	for _, offset := range d.offsets {
+		fmt.Fprintf(&d.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&d.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.offsets)+1, xref)
+	return d.buf.Bytes()
+}
+
+// pdfString escapes s for a PDF string literal in WinAnsiEncoding.
+// Characters the encoding lacks become "?".
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, `\%03o`, r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
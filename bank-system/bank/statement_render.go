@@ -0,0 +1,182 @@
+package bank
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	statementDate     = "2006-01-02"
+	statementDateTime = "2006-01-02 15:04:05 MST"
+)
+
+// lastDay is the last day of the period, which ends just before To.
+func (st *Statement) lastDay() time.Time {
+	return st.To.Add(-time.Nanosecond)
+}
+
+// summaryLines describe the account and the period.
+func (st *Statement) summaryLines() []string {
+	return []string{
+		fmt.Sprintf("Account:   %s (%s)", st.AccountNumber, st.AccountType),
+		fmt.Sprintf("Holder:    %s", st.HolderName),
+		fmt.Sprintf("Period:    %s to %s", st.From.Format(statementDate), st.lastDay().Format(statementDate)),
+		fmt.Sprintf("Currency:  %s", st.Currency),
+		fmt.Sprintf("Generated: %s", st.GeneratedAt.Format(statementDateTime)),
+	}
+}
+
+// The table of a plain text or PDF statement has fixed-width columns.
+const statementRowFormat = "%-10s %-21s %-26s %14s %14s %14s"
+
+func statementRow(date, reference, description, debit, credit, balance string) string {
+	row := fmt.Sprintf(statementRowFormat, date, truncate(reference, 21), truncate(description, 26), debit, credit, balance)
+	return strings.TrimRight(row, " ")
+}
+
+// tableLines are the heading and rows of the table of entries, ending with
+// the closing balance and the totals.
+func (st *Statement) tableLines() (heading, rows []string) {
+	header := statementRow("Date", "Reference", "Description", "Debit", "Credit", "Balance")
+	heading = []string{header, strings.Repeat("-", len(header))}
+
+	rows = append(rows, statementRow("", "", "Opening balance", "", "", st.OpeningBalance.Amount()))
+	for _, entry := range st.Entries {
+		rows = append(rows, statementRow(entry.Date.Format(statementDate), entry.ReferenceNumber, entry.Description,
+			nonZeroAmount(entry.Debit), nonZeroAmount(entry.Credit), entry.Balance.Amount()))
+	}
+	rows = append(rows,
+		statementRow("", "", "Closing balance", "", "", st.ClosingBalance.Amount()),
+		statementRow("", "", "Totals", st.TotalDebits.Amount(), st.TotalCredits.Amount(), ""),
+	)
+	return heading, rows
+}
+
+// nonZeroAmount formats m, leaving zero blank.
+func nonZeroAmount(m Money) string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.Amount()
+}
+
+// truncate shortens s to at most n characters, marking the cut with "~".
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "~"
+}
+
+// RenderText writes the statement as plain text, in columns.
+func (st *Statement) RenderText(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("=== Account Statement ===\n")
+	for _, line := range st.summaryLines() {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+	heading, rows := st.tableLines()
+	for _, line := range append(heading, rows...) {
+		b.WriteString(line + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format(statementDate) },
+	"datetime": func(t time.Time) string { return t.Format(statementDateTime) },
+	"amount":   nonZeroAmount,
+	"lastDay":  (*Statement).lastDay,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Statement {{.AccountNumber}} {{date .From}} to {{date (lastDay .)}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.3em 0.6em; border-bottom: 1px solid #ddd; text-align: left; }
+.amount { text-align: right; font-variant-numeric: tabular-nums; }
+.balance { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Account Statement</h1>
+<dl>
+<dt>Account</dt><dd>{{.AccountNumber}} ({{.AccountType}})</dd>
+<dt>Holder</dt><dd>{{.HolderName}}</dd>
+<dt>Period</dt><dd>{{date .From}} to {{date (lastDay .)}}</dd>
+<dt>Currency</dt><dd>{{.Currency}}</dd>
+<dt>Generated</dt><dd>{{datetime .GeneratedAt}}</dd>
+</dl>
+<table>
+<thead>
+<tr><th>Date</th><th>Reference</th><th>Type</th><th>Description</th><th class="amount">Debit</th><th class="amount">Credit</th><th class="amount">Balance</th></tr>
+</thead>
+<tbody>
+<tr class="balance"><td colspan="6">Opening balance</td><td class="amount">{{.OpeningBalance.Amount}}</td></tr>
+{{- range .Entries}}
+<tr><td>{{date .Date}}</td><td>{{.ReferenceNumber}}</td><td>{{.Type}}</td><td>{{.Description}}</td><td class="amount">{{amount .Debit}}</td><td class="amount">{{amount .Credit}}</td><td class="amount">{{.Balance.Amount}}</td></tr>
+{{- end}}
+<tr class="balance"><td colspan="6">Closing balance</td><td class="amount">{{.ClosingBalance.Amount}}</td></tr>
+</tbody>
+<tfoot>
+<tr><th colspan="4">Totals</th><th class="amount">{{.TotalDebits.Amount}}</th><th class="amount">{{.TotalCredits.Amount}}</th><th></th></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+// RenderHTML writes the statement as a standalone HTML page.
+func (st *Statement) RenderHTML(w io.Writer) error {
+	var b bytes.Buffer
+	if err := statementTemplate.Execute(&b, st); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// StatementFormat names a way to render a statement.
+type StatementFormat string
+
+const (
+	StatementText StatementFormat = "text"
+	StatementHTML StatementFormat = "html"
+	StatementPDF  StatementFormat = "pdf"
+)
+
+// ContentType returns the media type of statements rendered in f.
+func (f StatementFormat) ContentType() string {
+	switch f {
+	case StatementText:
+		return "text/plain; charset=utf-8"
+	case StatementHTML:
+		return "text/html; charset=utf-8"
+	case StatementPDF:
+		return "application/pdf"
+	}
+	return ""
+}
+
+// Render writes the statement in format.
+func (st *Statement) Render(w io.Writer, format StatementFormat) error {
+	switch format {
+	case StatementText:
+		return st.RenderText(w)
+	case StatementHTML:
+		return st.RenderHTML(w)
+	case StatementPDF:
+		return st.RenderPDF(w)
+	}
+	return fmt.Errorf("%w: unknown statement format %q", ErrInvalidInput, format)
+}
@@ -0,0 +1,113 @@
+package bank
+
+import (
+	"errors"
+	"time"
+)
+
+// UserRepository persists users. Get and GetByEmail return ErrUserNotFound
+// when there is no match.
+type UserRepository interface {
+	NextID() (int, error)
+	Get(id int) (*User, error)
+	GetByEmail(email string) (*User, error)
+	Save(user User) error
+	Delete(id int) error
+	List() ([]User, error)
+}
+
+// AccountRepository persists accounts. Get returns ErrAccountNotFound when
+// there is no match.
+type AccountRepository interface {
+	Get(accountNumber string) (*Account, error)
+	Save(account Account) error
+	List() ([]Account, error)
+}
+
+// TransactionRepository persists transactions. Get returns
+// ErrTransactionNotFound when there is no match.
+type TransactionRepository interface {
+	Get(transactionID string) (*Transaction, error)
+	// Create stores a new transaction. It returns ErrTransactionExists
+	// rather than overwrite one with the same ID.
+	Create(transaction Transaction) error
+	// Save stores changes to a transaction.
+	Save(transaction Transaction) error
+	List() ([]*Transaction, error)
+	// Query returns the transactions that match q, in q's order.
+	Query(q TransactionQuery) ([]*Transaction, error)
+}
+
+// AccountTotals holds the debit and credit sums posted to one ledger account.
+type AccountTotals struct {
+	Debits  Money
+	Credits Money
+}
+
+// JournalRepository persists ledger entries. Entries are append-only.
+type JournalRepository interface {
+	NextSequence() (int64, error)
+	Append(entry JournalEntry) error
+	List() ([]JournalEntry, error)
+	// ListByAccount returns the entries with a line on account, in sequence
+	// order.
+	ListByAccount(account string) ([]JournalEntry, error)
+	Totals(account string) (AccountTotals, error)
+	AllTotals() (map[string]AccountTotals, error)
+}
+
+// AuditRepository persists the audit trail. Entries are append-only.
+type AuditRepository interface {
+	Append(entry AuditEntry) error
+	// Last returns the entry with the highest sequence, or nil if the trail
+	// is empty.
+	Last() (*AuditEntry, error)
+	List() ([]AuditEntry, error)
+}
+
+// StandingInstructionRepository persists standing instructions and the
+// history of their runs. Get returns ErrInstructionNotFound when there is no
+// match. Attempts are append-only.
+type StandingInstructionRepository interface {
+	NextID() (int, error)
+	Get(id int) (*StandingInstruction, error)
+	Save(instruction StandingInstruction) error
+	List() ([]StandingInstruction, error)
+	AppendAttempt(attempt InstructionAttempt) error
+	// ListAttempts returns an instruction's attempts, oldest first.
+	ListAttempts(instructionID int) ([]InstructionAttempt, error)
+}
+
+// IdempotencyRepository remembers requests made with idempotency keys. Get
+// returns nil when the actor has not used the key.
+type IdempotencyRepository interface {
+	Get(actor, key string) (*IdempotencyRecord, error)
+	Save(record IdempotencyRecord) error
+	// Purge deletes the records that expired by now and returns how many
+	// there were.
+	Purge(now time.Time) (int, error)
+}
+
+// Store groups the repositories the banking system is built on.
+type Store interface {
+	Users() UserRepository
+	Accounts() AccountRepository
+	Transactions() TransactionRepository
+	Journal() JournalRepository
+	Audit() AuditRepository
+	StandingInstructions() StandingInstructionRepository
+	Idempotency() IdempotencyRepository
+
+	// Update runs fn as one unit of work. Writes made through tx are
+	// visible to later reads through tx, and become durable together when
+	// fn returns nil. If fn returns an error they are discarded. Calling
+	// Update on tx joins the enclosing unit of work.
+	Update(fn func(tx Store) error) error
+
+	Close() error
+}
+
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrTransactionExists   = errors.New("transaction already exists")
+)
@@ -0,0 +1,403 @@
+package bank
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+
+	// DefaultSnapshotInterval is the number of commits between snapshots
+	// when OpenFileStore is given no interval.
+	DefaultSnapshotInterval = 1000
+
+	walHeaderSize = 8
+	maxWALRecord  = 64 << 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type walChange struct {
+	Kind    string          `json:"kind"`
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+type walRecord struct {
+	Seq     uint64      `json:"seq"`
+	Changes []walChange `json:"changes"`
+}
+
+type snapshotFile struct {
+	Seq    uint64                                `json:"seq"`
+	Tables map[string]map[string]json.RawMessage `json:"tables"`
+}
+
+// FileStore is a durable Store. It keeps the working set in memory, appends
+// every commit to a write-ahead log before applying it, and periodically
+// writes a snapshot so the log can be truncated.
+//
+// Each log record holds all writes of one unit of work and is framed with
+// its length and a CRC, so a record torn by a crash is detected and dropped
+// on recovery together with everything after it.
+type FileStore struct {
+	*MemoryStore
+
+	dir           string
+	wal           *os.File
+	walSize       int64
+	seq           uint64
+	snapshotEvery int
+	sinceSnapshot int
+	warn          func(error)
+	// broken is why the log can no longer be appended to: a failed record
+	// could not be cut off it, and would hide any record after it on
+	// recovery.
+	broken error
+}
+
+// FileStoreOption configures a FileStore.
+type FileStoreOption func(*FileStore)
+
+// WithWarningHandler has fn told about problems the store recovered from
+// without failing: a damaged tail of the write-ahead log discarded on
+// opening, and snapshots that could not be written after a commit. They are
+// ignored by default.
+func WithWarningHandler(fn func(error)) FileStoreOption {
+	return func(fs *FileStore) {
+		fs.warn = fn
+	}
+}
+
+// OpenFileStore opens or creates a store in dir and recovers its state from
+// the latest snapshot and the write-ahead log. A snapshot is taken every
+// snapshotEvery commits; zero selects DefaultSnapshotInterval.
+func OpenFileStore(dir string, snapshotEvery int, opts ...FileStoreOption) (*FileStore, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotInterval
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	fs := &FileStore{
+		MemoryStore:   NewMemoryStore(),
+		dir:           dir,
+		snapshotEvery: snapshotEvery,
+		warn:          func(error) {},
+	}
+	for _, opt := range opts {
+		opt(fs)
+	}
+
+	if err := fs.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := fs.replayWAL(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := wal.Seek(fs.walSize, io.SeekStart); err != nil {
+		wal.Close()
+		return nil, err
+	}
+
+	fs.wal = wal
+	fs.MemoryStore.log = fs
+	return fs, nil
+}
+
+func (fs *FileStore) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(fs.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snap snapshotFile
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	for kind, rows := range snap.Tables {
+		t, err := fs.table(kind)
+		if err != nil {
+			return fmt.Errorf("reading snapshot: %w", err)
+		}
+		for key, raw := range rows {
+			c, err := t.decode(key, raw, false)
+			if err != nil {
+				return fmt.Errorf("reading snapshot: %w", err)
+			}
+			t.apply(c)
+		}
+	}
+
+	fs.seq = snap.Seq
+	return nil
+}
+
+// replayWAL applies every intact log record newer than the snapshot. The
+// log is truncated after the last intact record.
+func (fs *FileStore) replayWAL() error {
+	path := filepath.Join(fs.dir, walFileName)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		payload, err := readWALRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fs.warn(fmt.Errorf("discarded damaged write-ahead log after offset %d: %w", offset, err))
+			if err := os.Truncate(path, offset); err != nil {
+				return err
+			}
+			if err := f.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+
+		var rec walRecord
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return fmt.Errorf("write-ahead log record at offset %d: %w", offset, err)
+		}
+		if rec.Seq > fs.seq {
+			if err := fs.applyRecord(rec); err != nil {
+				return fmt.Errorf("write-ahead log record %d: %w", rec.Seq, err)
+			}
+			fs.seq = rec.Seq
+		}
+		offset += walHeaderSize + int64(len(payload))
+	}
+
+	fs.walSize = offset
+	return nil
+}
+
+func readWALRecord(r io.Reader) ([]byte, error) {
+	var header [walHeaderSize]byte
+	n, err := io.ReadFull(r, header[:])
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("torn record header (%d bytes)", n)
+	}
+
+	size := binary.BigEndian.Uint32(header[0:4])
+	sum := binary.BigEndian.Uint32(header[4:8])
+	if size > maxWALRecord {
+		return nil, fmt.Errorf("record length %d out of range", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, errors.New("torn record payload")
+	}
+	if crc32.Checksum(payload, crcTable) != sum {
+		return nil, errors.New("record checksum mismatch")
+	}
+	return payload, nil
+}
+
+func (fs *FileStore) applyRecord(rec walRecord) error {
+	changes := make([]change, 0, len(rec.Changes))
+	for _, wc := range rec.Changes {
+		t, err := fs.table(wc.Kind)
+		if err != nil {
+			return err
+		}
+		c, err := t.decode(wc.Key, wc.Value, wc.Deleted)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c)
+	}
+
+	for _, c := range changes {
+		t, _ := fs.table(c.kind)
+		t.apply(c)
+	}
+	return nil
+}
+
+// append implements commitLog. It runs under the store's commit lock.
+func (fs *FileStore) append(changes []change) error {
+	if fs.broken != nil {
+		return fmt.Errorf("write-ahead log unusable: %w", fs.broken)
+	}
+
+	rec := walRecord{Seq: fs.seq + 1, Changes: make([]walChange, 0, len(changes))}
+	for _, c := range changes {
+		wc := walChange{Kind: c.kind, Key: c.key, Deleted: c.deleted}
+		if !c.deleted {
+			raw, err := json.Marshal(c.value)
+			if err != nil {
+				return fmt.Errorf("encoding %s %q: %w", c.kind, c.key, err)
+			}
+			wc.Value = raw
+		}
+		rec.Changes = append(rec.Changes, wc)
+	}
+
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, walHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.Checksum(payload, crcTable))
+	copy(frame[walHeaderSize:], payload)
+
+	if _, err := fs.wal.Write(frame); err != nil {
+		return errors.Join(fmt.Errorf("writing write-ahead log: %w", err), fs.discardTail())
+	}
+	if err := fs.wal.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("syncing write-ahead log: %w", err), fs.discardTail())
+	}
+
+	fs.walSize += int64(len(frame))
+	fs.seq = rec.Seq
+	return nil
+}
+
+// discardTail cuts off a partially written record so later records are not
+// hidden behind it on recovery. If it cannot, the store refuses every later
+// commit.
+func (fs *FileStore) discardTail() error {
+	err := fs.wal.Truncate(fs.walSize)
+	if err == nil {
+		err = fs.wal.Sync()
+	}
+	if err == nil {
+		_, err = fs.wal.Seek(fs.walSize, io.SeekStart)
+	}
+	if err != nil {
+		fs.broken = fmt.Errorf("discarding a failed record: %w", err)
+	}
+	return fs.broken
+}
+
+// committed implements commitLog. It runs under the store's commit lock.
+func (fs *FileStore) committed() {
+	fs.sinceSnapshot++
+	if fs.sinceSnapshot < fs.snapshotEvery {
+		return
+	}
+	if err := fs.snapshotLocked(); err != nil {
+		fs.warn(fmt.Errorf("writing snapshot: %w", err))
+	}
+}
+
+// Snapshot writes the current state to disk and truncates the write-ahead
+// log.
+func (fs *FileStore) Snapshot() error {
+	fs.commitMu.Lock()
+	defer fs.commitMu.Unlock()
+
+	return fs.snapshotLocked()
+}
+
+func (fs *FileStore) snapshotLocked() error {
+	snap := snapshotFile{Seq: fs.seq, Tables: make(map[string]map[string]json.RawMessage)}
+	for _, t := range fs.tables() {
+		rows, err := t.dump()
+		if err != nil {
+			return err
+		}
+		snap.Tables[t.kind()] = rows
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(fs.dir, snapshotFileName)
+	if err := writeFileSync(path+".tmp", data); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	if err := syncDir(fs.dir); err != nil {
+		return err
+	}
+
+	// Records up to snap.Seq are now covered by the snapshot. If we crash
+	// before the truncate, replay skips them by sequence number.
+	if err := fs.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := fs.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	fs.walSize = 0
+	fs.sinceSnapshot = 0
+	return nil
+}
+
+func (fs *FileStore) Close() error {
+	fs.commitMu.Lock()
+	defer fs.commitMu.Unlock()
+
+	err := fs.snapshotLocked()
+	if closeErr := fs.wal.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,284 @@
+package bank_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bank-system/bank"
+)
+
+// fileBank opens a banking system on a FileStore in dir and collects the
+// store's warnings.
+func fileBank(t *testing.T, dir string, snapshotEvery int) (*bank.BankingSystem, *[]error) {
+	t.Helper()
+	var warnings []error
+	store, err := bank.OpenFileStore(dir, snapshotEvery, bank.WithWarningHandler(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	return bank.NewBankingSystem(bank.WithStore(store), bank.WithFeeSchedules(nil)), &warnings
+}
+
+// crash copies the files of a store in use to a new directory, as they would
+// be found if the process died now, without the snapshot Close takes.
+func crash(t *testing.T, dir string) string {
+	t.Helper()
+	crashed := t.TempDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(crashed, entry.Name()), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return crashed
+}
+
+// walRecords returns the offset at which each record of a write-ahead log
+// starts, and the log's length.
+func walRecords(t *testing.T, path string) ([]int64, int64) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offsets []int64
+	for off := int64(0); off < int64(len(data)); {
+		offsets = append(offsets, off)
+		off += 8 + int64(binary.BigEndian.Uint32(data[off:off+4]))
+	}
+	return offsets, int64(len(data))
+}
+
+// newFileBankWithDeposits opens a store in a new directory with one account
+// and a deposit of 1, 2, ... n rupees, each its own commit and so its own
+// log record.
+func newFileBankWithDeposits(t *testing.T, n int) (*bank.BankingSystem, string) {
+	t.Helper()
+	dir := t.TempDir()
+	bs, _ := fileBank(t, dir, 1_000_000)
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := bs.CreateAccount("SAV", "Test User", "Savings", user.ID); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	for i := 1; i <= n; i++ {
+		if _, err := bs.Deposit("SAV", bank.NewMoney(int64(i)*100, bank.INR)); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+	}
+	return bs, dir
+}
+
+// checkDeposits checks that the recovered account holds exactly the first n
+// deposits and that its ledger agrees.
+func checkDeposits(t *testing.T, bs *bank.BankingSystem, n int) {
+	t.Helper()
+	balance, err := bs.GetBalance("SAV")
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if want := bank.NewMoney(int64(n*(n+1)/2)*100, bank.INR); !balance.Equal(want) {
+		t.Errorf("balance %s, want %s (the first %d deposits)", balance, want, n)
+	}
+	transactions, err := bs.ListAccountTransactions("SAV")
+	if err != nil {
+		t.Fatalf("ListAccountTransactions: %v", err)
+	}
+	if len(transactions) != n {
+		t.Errorf("%d transactions, want %d", len(transactions), n)
+	}
+	assertLedgerConsistent(t, bs)
+}
+
+func TestFileStoreReplaysLogAfterCrash(t *testing.T) {
+	bs, dir := newFileBankWithDeposits(t, 5)
+	crashed := crash(t, dir)
+	bs.Close()
+
+	if _, err := os.Stat(filepath.Join(crashed, "snapshot.json")); !os.IsNotExist(err) {
+		t.Fatalf("a snapshot was written before the crash: %v", err)
+	}
+	recovered, warnings := fileBank(t, crashed, 0)
+	defer recovered.Close()
+	checkDeposits(t, recovered, 5)
+	if len(*warnings) != 0 {
+		t.Errorf("intact log produced warnings: %v", *warnings)
+	}
+}
+
+func TestFileStoreDropsDamagedTail(t *testing.T) {
+	tests := []struct {
+		name string
+		// damage changes the log, whose records start at offsets, and
+		// returns how many deposits survive.
+		damage func(t *testing.T, path string, offsets []int64, size int64) int
+	}{
+		{"record cut mid-payload", func(t *testing.T, path string, offsets []int64, size int64) int {
+			last := offsets[len(offsets)-1]
+			truncate(t, path, last+(size-last)/2)
+			return 4
+		}},
+		{"record cut mid-header", func(t *testing.T, path string, offsets []int64, size int64) int {
+			truncate(t, path, offsets[len(offsets)-1]+3)
+			return 4
+		}},
+		{"last record corrupt", func(t *testing.T, path string, offsets []int64, size int64) int {
+			flipByte(t, path, size-2)
+			return 4
+		}},
+		{"middle record corrupt", func(t *testing.T, path string, offsets []int64, size int64) int {
+			// The third deposit's record and everything after it go.
+			flipByte(t, path, offsets[len(offsets)-3]+20)
+			return 2
+		}},
+		{"length out of range", func(t *testing.T, path string, offsets []int64, size int64) int {
+			f, err := os.OpenFile(path, os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			if _, err := f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, offsets[len(offsets)-1]); err != nil {
+				t.Fatal(err)
+			}
+			return 4
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bs, dir := newFileBankWithDeposits(t, 5)
+			crashed := crash(t, dir)
+			bs.Close()
+
+			path := filepath.Join(crashed, "wal.log")
+			offsets, size := walRecords(t, path)
+			survivors := tc.damage(t, path, offsets, size)
+
+			recovered, warnings := fileBank(t, crashed, 0)
+			checkDeposits(t, recovered, survivors)
+			if len(*warnings) != 1 || !strings.Contains((*warnings)[0].Error(), "discarded damaged write-ahead log") {
+				t.Errorf("warnings = %v, want one about the damaged log", *warnings)
+			}
+
+			// The damaged tail is gone, so what is written next is found
+			// after the next crash.
+			if _, err := recovered.Deposit("SAV", inr("1000")); err != nil {
+				t.Fatalf("Deposit after recovery: %v", err)
+			}
+			again := crash(t, crashed)
+			recovered.Close()
+			reopened, warnings := fileBank(t, again, 0)
+			defer reopened.Close()
+			balance, err := reopened.GetBalance("SAV")
+			if err != nil {
+				t.Fatalf("GetBalance: %v", err)
+			}
+			if want := bank.NewMoney(int64(survivors*(survivors+1)/2)*100+100000, bank.INR); !balance.Equal(want) {
+				t.Errorf("balance after a second crash %s, want %s", balance, want)
+			}
+			if len(*warnings) != 0 {
+				t.Errorf("second recovery produced warnings: %v", *warnings)
+			}
+		})
+	}
+}
+
+func TestFileStoreSnapshotTruncatesLog(t *testing.T) {
+	dir := t.TempDir()
+	bs, warnings := fileBank(t, dir, 3)
+	user, err := bs.CreateUser("Test", "User", "test.user@example.com", "S3cure!Passw0rd",
+		"Motihari, Bihar", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := bs.CreateAccount("SAV", "Test User", "Savings", user.ID); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		if _, err := bs.Deposit("SAV", bank.NewMoney(int64(i)*100, bank.INR)); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+	}
+	if len(*warnings) != 0 {
+		t.Fatalf("warnings: %v", *warnings)
+	}
+
+	// Seven commits with a snapshot every three leave one in the log.
+	if _, err := os.Stat(filepath.Join(dir, "snapshot.json")); err != nil {
+		t.Fatalf("no snapshot: %v", err)
+	}
+	if offsets, _ := walRecords(t, filepath.Join(dir, "wal.log")); len(offsets) != 1 {
+		t.Errorf("log holds %d records after the snapshot, want 1", len(offsets))
+	}
+
+	crashed := crash(t, dir)
+	bs.Close()
+	recovered, _ := fileBank(t, crashed, 3)
+	defer recovered.Close()
+	checkDeposits(t, recovered, 5)
+}
+
+func TestFileStoreCrashBetweenSnapshotAndTruncate(t *testing.T) {
+	bs, dir := newFileBankWithDeposits(t, 5)
+	wal, err := os.ReadFile(filepath.Join(dir, "wal.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crashed := crash(t, dir)
+	bs.Close()
+
+	// The recovered state is snapshotted, but the process dies before the
+	// log is truncated: every record in it is already in the snapshot.
+	store, err := bank.OpenFileStore(crashed, 0)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(crashed, "wal.log"), wal, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, warnings := fileBank(t, crashed, 0)
+	defer recovered.Close()
+	checkDeposits(t, recovered, 5)
+	if len(*warnings) != 0 {
+		t.Errorf("warnings: %v", *warnings)
+	}
+}
+
+func truncate(t *testing.T, path string, size int64) {
+	t.Helper()
+	if err := os.Truncate(path, size); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func flipByte(t *testing.T, path string, offset int64) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[offset] ^= 0xff
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
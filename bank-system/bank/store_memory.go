@@ -0,0 +1,800 @@
+package bank
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	kindUser        = "user"
+	kindAccount     = "account"
+	kindTransaction = "transaction"
+	kindJournal     = "journal"
+	kindAudit       = "audit"
+	kindInstruction = "standing_instruction"
+	kindAttempt     = "instruction_attempt"
+	kindIdempotency = "idempotency_key"
+	kindSequence    = "sequence"
+)
+
+// change is a single record write inside a unit of work.
+type change struct {
+	kind    string
+	key     string
+	value   any
+	deleted bool
+}
+
+// table is the type-erased view of a memTable used for commits, snapshots
+// and recovery.
+type table interface {
+	kind() string
+	apply(c change)
+	decode(key string, raw json.RawMessage, deleted bool) (change, error)
+	dump() (map[string]json.RawMessage, error)
+}
+
+type memTable[V any] struct {
+	name  string
+	mu    sync.RWMutex
+	rows  map[string]V
+	merge func(old V, value V) V
+	onPut func(value V)
+	// onChange, if set, is told of every insertion, update and deletion
+	// after it is applied. old is nil for an insertion and value for a
+	// deletion.
+	onChange func(old, value *V)
+}
+
+func newMemTable[V any](name string) *memTable[V] {
+	return &memTable[V]{
+		name: name,
+		rows: make(map[string]V),
+	}
+}
+
+func (t *memTable[V]) kind() string {
+	return t.name
+}
+
+func (t *memTable[V]) get(key string) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	v, ok := t.rows[key]
+	return v, ok
+}
+
+func (t *memTable[V]) all() map[string]V {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return maps.Clone(t.rows)
+}
+
+func (t *memTable[V]) apply(c change) {
+	old, existed, value := t.put(c)
+	if t.onChange == nil {
+		return
+	}
+	var before *V
+	if existed {
+		before = &old
+	}
+	t.onChange(before, value)
+}
+
+// put applies a change and returns the row it replaced, if any, and the new
+// row, or nil for a deletion.
+func (t *memTable[V]) put(c change) (old V, existed bool, value *V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	old, existed = t.rows[c.key]
+	if c.deleted {
+		delete(t.rows, c.key)
+		return old, existed, nil
+	}
+
+	v := c.value.(V)
+	if existed && t.merge != nil {
+		v = t.merge(old, v)
+	}
+	t.rows[c.key] = v
+	if t.onPut != nil {
+		t.onPut(v)
+	}
+	return old, existed, &v
+}
+
+func (t *memTable[V]) decode(key string, raw json.RawMessage, deleted bool) (change, error) {
+	c := change{kind: t.name, key: key, deleted: deleted}
+	if deleted {
+		return c, nil
+	}
+
+	var v V
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return change{}, fmt.Errorf("decoding %s %q: %w", t.name, key, err)
+	}
+	c.value = v
+	return c, nil
+}
+
+func (t *memTable[V]) dump() (map[string]json.RawMessage, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(t.rows))
+	for key, v := range t.rows {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s %q: %w", t.name, key, err)
+		}
+		out[key] = raw
+	}
+	return out, nil
+}
+
+// commitLog is notified of every commit. FileStore uses it to make commits
+// durable before they become visible.
+type commitLog interface {
+	append(changes []change) error
+	committed()
+}
+
+// MemoryStore keeps all records in memory. It is the default store and the
+// working set underneath FileStore.
+type MemoryStore struct {
+	commitMu sync.Mutex
+	log      commitLog
+
+	seqMu sync.Mutex
+
+	users        *memTable[User]
+	accounts     *memTable[Account]
+	transactions *memTable[Transaction]
+	journal      *memTable[JournalEntry]
+	audit        *memTable[AuditEntry]
+	instructions *memTable[StandingInstruction]
+	attempts     *memTable[InstructionAttempt]
+	idempotency  *memTable[IdempotencyRecord]
+	sequences    *memTable[int64]
+
+	transactionIndex *transactionIndex
+
+	// journalMu guards the totals and entry IDs of each ledger account,
+	// kept as entries are committed so neither needs a journal scan.
+	journalMu      sync.RWMutex
+	totals         map[string]AccountTotals
+	accountEntries map[string][]string
+
+	auditHeadMu sync.RWMutex
+	auditHead   *AuditEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		users:        newMemTable[User](kindUser),
+		accounts:     newMemTable[Account](kindAccount),
+		transactions: newMemTable[Transaction](kindTransaction),
+		journal:      newMemTable[JournalEntry](kindJournal),
+		audit:        newMemTable[AuditEntry](kindAudit),
+		instructions: newMemTable[StandingInstruction](kindInstruction),
+		attempts:     newMemTable[InstructionAttempt](kindAttempt),
+		idempotency:  newMemTable[IdempotencyRecord](kindIdempotency),
+		sequences:    newMemTable[int64](kindSequence),
+		totals:       make(map[string]AccountTotals),
+
+		accountEntries: make(map[string][]string),
+
+		transactionIndex: newTransactionIndex(),
+	}
+	s.sequences.merge = func(old, value int64) int64 { return max(old, value) }
+	s.journal.onPut = s.indexJournalEntry
+	s.audit.onPut = s.advanceAuditHead
+	s.transactions.onChange = s.transactionIndex.update
+	return s
+}
+
+func (s *MemoryStore) tables() []table {
+	return []table{s.users, s.accounts, s.transactions, s.journal, s.audit, s.instructions, s.attempts, s.idempotency, s.sequences}
+}
+
+func (s *MemoryStore) table(kind string) (table, error) {
+	for _, t := range s.tables() {
+		if t.kind() == kind {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown record kind %q", kind)
+}
+
+func (s *MemoryStore) indexJournalEntry(entry JournalEntry) {
+	s.journalMu.Lock()
+	defer s.journalMu.Unlock()
+
+	addLineTotals(s.totals, entry)
+	for _, account := range entryAccounts(entry) {
+		s.accountEntries[account] = append(s.accountEntries[account], entry.ID)
+	}
+}
+
+// entryAccounts returns the accounts an entry has lines on, each once.
+func entryAccounts(entry JournalEntry) []string {
+	var accounts []string
+	for _, line := range entry.Lines {
+		if !slices.Contains(accounts, line.Account) {
+			accounts = append(accounts, line.Account)
+		}
+	}
+	return accounts
+}
+
+func addLineTotals(totals map[string]AccountTotals, entry JournalEntry) {
+	for _, line := range entry.Lines {
+		t := totals[line.Account]
+		if line.Side == Debit {
+			t.Debits, _ = t.Debits.Add(line.Amount)
+		} else {
+			t.Credits, _ = t.Credits.Add(line.Amount)
+		}
+		totals[line.Account] = t
+	}
+}
+
+func (s *MemoryStore) commit(changes []change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+
+	if s.log != nil {
+		if err := s.log.append(changes); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range changes {
+		t, err := s.table(c.kind)
+		if err != nil {
+			return err
+		}
+		t.apply(c)
+	}
+
+	if s.log != nil {
+		s.log.committed()
+	}
+	return nil
+}
+
+// allocate hands out the next value of a named sequence. Values are never
+// reused, even when the unit of work that allocated them is discarded.
+func (s *MemoryStore) allocate(name string) (int64, change) {
+	s.seqMu.Lock()
+	defer s.seqMu.Unlock()
+
+	n, _ := s.sequences.get(name)
+	n++
+	c := change{kind: kindSequence, key: name, value: n}
+	s.sequences.apply(c)
+	return n, c
+}
+
+func (s *MemoryStore) view() memView {
+	return memView{store: s}
+}
+
+func (s *MemoryStore) Users() UserRepository {
+	return s.view().Users()
+}
+
+func (s *MemoryStore) Accounts() AccountRepository {
+	return s.view().Accounts()
+}
+
+func (s *MemoryStore) Transactions() TransactionRepository {
+	return s.view().Transactions()
+}
+
+func (s *MemoryStore) Journal() JournalRepository {
+	return s.view().Journal()
+}
+
+func (s *MemoryStore) Audit() AuditRepository {
+	return s.view().Audit()
+}
+
+func (s *MemoryStore) StandingInstructions() StandingInstructionRepository {
+	return s.view().StandingInstructions()
+}
+
+func (s *MemoryStore) Idempotency() IdempotencyRepository {
+	return s.view().Idempotency()
+}
+
+func (s *MemoryStore) Update(fn func(tx Store) error) error {
+	return s.view().Update(fn)
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// memTx stages the writes of one unit of work until it commits.
+type memTx struct {
+	staged map[string]map[string]change
+	order  []change
+}
+
+func (tx *memTx) stage(c change) {
+	if tx.staged[c.kind] == nil {
+		tx.staged[c.kind] = make(map[string]change)
+	}
+	tx.staged[c.kind][c.key] = c
+	tx.order = append(tx.order, c)
+}
+
+// memView is a MemoryStore seen either directly (tx == nil), where every
+// write commits on its own, or through a unit of work.
+type memView struct {
+	store *MemoryStore
+	tx    *memTx
+}
+
+func (v memView) write(c change) error {
+	if v.tx != nil {
+		v.tx.stage(c)
+		return nil
+	}
+	return v.store.commit([]change{c})
+}
+
+func (v memView) Users() UserRepository {
+	return memUserRepository{v}
+}
+
+func (v memView) Accounts() AccountRepository {
+	return memAccountRepository{v}
+}
+
+func (v memView) Transactions() TransactionRepository {
+	return memTransactionRepository{v}
+}
+
+func (v memView) Journal() JournalRepository {
+	return memJournalRepository{v}
+}
+
+func (v memView) Audit() AuditRepository {
+	return memAuditRepository{v}
+}
+
+func (v memView) StandingInstructions() StandingInstructionRepository {
+	return memInstructionRepository{v}
+}
+
+func (v memView) Idempotency() IdempotencyRepository {
+	return memIdempotencyRepository{v}
+}
+
+func (v memView) Update(fn func(tx Store) error) error {
+	if v.tx != nil {
+		return fn(v)
+	}
+
+	tx := &memTx{staged: make(map[string]map[string]change)}
+	if err := fn(memView{store: v.store, tx: tx}); err != nil {
+		return err
+	}
+	return v.store.commit(tx.order)
+}
+
+func (v memView) Close() error {
+	return nil
+}
+
+func lookup[V any](v memView, t *memTable[V], key string) (V, bool) {
+	if v.tx != nil {
+		if c, ok := v.tx.staged[t.name][key]; ok {
+			if c.deleted {
+				var zero V
+				return zero, false
+			}
+			return c.value.(V), true
+		}
+	}
+	return t.get(key)
+}
+
+func listAll[V any](v memView, t *memTable[V]) []V {
+	rows := t.all()
+	if v.tx != nil {
+		for key, c := range v.tx.staged[t.name] {
+			if c.deleted {
+				delete(rows, key)
+			} else {
+				rows[key] = c.value.(V)
+			}
+		}
+	}
+	return slices.Collect(maps.Values(rows))
+}
+
+type memUserRepository struct {
+	memView
+}
+
+func (r memUserRepository) NextID() (int, error) {
+	n, c := r.store.allocate(kindUser)
+	if err := r.write(c); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func (r memUserRepository) Get(id int) (*User, error) {
+	user, exists := lookup(r.memView, r.store.users, strconv.Itoa(id))
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	user.Accounts = slices.Clone(user.Accounts)
+	return &user, nil
+}
+
+func (r memUserRepository) GetByEmail(email string) (*User, error) {
+	for _, user := range listAll(r.memView, r.store.users) {
+		if user.Email == email {
+			user.Accounts = slices.Clone(user.Accounts)
+			return &user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r memUserRepository) Save(user User) error {
+	user.Accounts = slices.Clone(user.Accounts)
+	return r.write(change{kind: kindUser, key: strconv.Itoa(user.ID), value: user})
+}
+
+func (r memUserRepository) Delete(id int) error {
+	key := strconv.Itoa(id)
+	if _, exists := lookup(r.memView, r.store.users, key); !exists {
+		return ErrUserNotFound
+	}
+	return r.write(change{kind: kindUser, key: key, deleted: true})
+}
+
+func (r memUserRepository) List() ([]User, error) {
+	users := listAll(r.memView, r.store.users)
+	for i := range users {
+		users[i].Accounts = slices.Clone(users[i].Accounts)
+	}
+	slices.SortFunc(users, func(a, b User) int { return cmp.Compare(a.ID, b.ID) })
+	return users, nil
+}
+
+type memAccountRepository struct {
+	memView
+}
+
+func (r memAccountRepository) Get(accountNumber string) (*Account, error) {
+	account, exists := lookup(r.memView, r.store.accounts, accountNumber)
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	return &account, nil
+}
+
+func (r memAccountRepository) Save(account Account) error {
+	return r.write(change{kind: kindAccount, key: account.AccountNumber, value: account})
+}
+
+func (r memAccountRepository) List() ([]Account, error) {
+	accounts := listAll(r.memView, r.store.accounts)
+	slices.SortFunc(accounts, func(a, b Account) int { return cmp.Compare(a.AccountNumber, b.AccountNumber) })
+	return accounts, nil
+}
+
+type memTransactionRepository struct {
+	memView
+}
+
+func (r memTransactionRepository) Get(transactionID string) (*Transaction, error) {
+	transaction, exists := lookup(r.memView, r.store.transactions, transactionID)
+	if !exists {
+		return nil, ErrTransactionNotFound
+	}
+	return &transaction, nil
+}
+
+func (r memTransactionRepository) Create(transaction Transaction) error {
+	if _, exists := lookup(r.memView, r.store.transactions, transaction.ID); exists {
+		return fmt.Errorf("%w: %s", ErrTransactionExists, transaction.ID)
+	}
+	return r.write(change{kind: kindTransaction, key: transaction.ID, value: transaction})
+}
+
+func (r memTransactionRepository) Save(transaction Transaction) error {
+	return r.write(change{kind: kindTransaction, key: transaction.ID, value: transaction})
+}
+
+func (r memTransactionRepository) List() ([]*Transaction, error) {
+	return r.Query(TransactionQuery{})
+}
+
+// Query reads the committed transactions through the store's index, then
+// lays the unit of work's own writes over them.
+func (r memTransactionRepository) Query(q TransactionQuery) ([]*Transaction, error) {
+	var staged map[string]change
+	if r.tx != nil {
+		staged = r.tx.staged[kindTransaction]
+	}
+
+	// Staged transactions are matched separately, so find enough committed
+	// ones to fill the page even if every staged one replaces one of them.
+	limit := q.Limit
+	if limit > 0 {
+		limit += len(staged)
+	}
+
+	matched := []*Transaction{}
+	ix := r.store.transactionIndex
+	ix.mu.RLock()
+	scan(ix.plan(q), q, func(key txKey) bool {
+		if _, ok := staged[key.id]; ok {
+			return true
+		}
+		t, ok := r.store.transactions.get(key.id)
+		if ok && q.Matches(&t) {
+			matched = append(matched, &t)
+		}
+		return limit == 0 || len(matched) < limit
+	})
+	ix.mu.RUnlock()
+
+	if len(staged) == 0 {
+		return matched, nil
+	}
+	for _, c := range staged {
+		if c.deleted {
+			continue
+		}
+		t := c.value.(Transaction)
+		if q.Follows(&t) && q.Matches(&t) {
+			matched = append(matched, &t)
+		}
+	}
+	slices.SortFunc(matched, compareTransactions)
+	if q.NewestFirst {
+		slices.Reverse(matched)
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	return matched, nil
+}
+
+func compareTransactions(a, b *Transaction) int {
+	if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+type memJournalRepository struct {
+	memView
+}
+
+func (r memJournalRepository) NextSequence() (int64, error) {
+	n, c := r.store.allocate(kindJournal)
+	if err := r.write(c); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r memJournalRepository) Append(entry JournalEntry) error {
+	if _, exists := lookup(r.memView, r.store.journal, entry.ID); exists {
+		return fmt.Errorf("journal entry %s already exists", entry.ID)
+	}
+	return r.write(change{kind: kindJournal, key: entry.ID, value: entry})
+}
+
+func (r memJournalRepository) List() ([]JournalEntry, error) {
+	entries := listAll(r.memView, r.store.journal)
+	slices.SortFunc(entries, func(a, b JournalEntry) int { return cmp.Compare(a.Sequence, b.Sequence) })
+	return entries, nil
+}
+
+func (r memJournalRepository) ListByAccount(account string) ([]JournalEntry, error) {
+	r.store.journalMu.RLock()
+	ids := slices.Clone(r.store.accountEntries[account])
+	r.store.journalMu.RUnlock()
+
+	entries := make([]JournalEntry, 0, len(ids))
+	for _, id := range ids {
+		if entry, ok := r.store.journal.get(id); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if r.tx != nil {
+		for _, c := range r.tx.staged[kindJournal] {
+			if entry := c.value.(JournalEntry); slices.Contains(entryAccounts(entry), account) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	slices.SortFunc(entries, func(a, b JournalEntry) int { return cmp.Compare(a.Sequence, b.Sequence) })
+	return entries, nil
+}
+
+func (r memJournalRepository) Totals(account string) (AccountTotals, error) {
+	r.store.journalMu.RLock()
+	totals := map[string]AccountTotals{account: r.store.totals[account]}
+	r.store.journalMu.RUnlock()
+
+	if r.tx != nil {
+		for _, c := range r.tx.staged[kindJournal] {
+			addLineTotals(totals, c.value.(JournalEntry))
+		}
+	}
+	return totals[account], nil
+}
+
+func (r memJournalRepository) AllTotals() (map[string]AccountTotals, error) {
+	r.store.journalMu.RLock()
+	totals := maps.Clone(r.store.totals)
+	r.store.journalMu.RUnlock()
+
+	if r.tx != nil {
+		for _, c := range r.tx.staged[kindJournal] {
+			addLineTotals(totals, c.value.(JournalEntry))
+		}
+	}
+	return totals, nil
+}
+
+// advanceAuditHead keeps the latest audit entry at hand, so appending does
+// not have to scan the trail.
+func (s *MemoryStore) advanceAuditHead(entry AuditEntry) {
+	s.auditHeadMu.Lock()
+	defer s.auditHeadMu.Unlock()
+
+	if s.auditHead == nil || entry.Sequence > s.auditHead.Sequence {
+		s.auditHead = &entry
+	}
+}
+
+type memAuditRepository struct {
+	memView
+}
+
+func auditKey(sequence int64) string {
+	return fmt.Sprintf("%020d", sequence)
+}
+
+func (r memAuditRepository) Append(entry AuditEntry) error {
+	if _, exists := lookup(r.memView, r.store.audit, auditKey(entry.Sequence)); exists {
+		return fmt.Errorf("audit entry %d already exists", entry.Sequence)
+	}
+	return r.write(change{kind: kindAudit, key: auditKey(entry.Sequence), value: entry})
+}
+
+func (r memAuditRepository) Last() (*AuditEntry, error) {
+	r.store.auditHeadMu.RLock()
+	head := r.store.auditHead
+	r.store.auditHeadMu.RUnlock()
+
+	if r.tx != nil {
+		for _, c := range r.tx.staged[kindAudit] {
+			if entry := c.value.(AuditEntry); head == nil || entry.Sequence > head.Sequence {
+				head = &entry
+			}
+		}
+	}
+	return head, nil
+}
+
+func (r memAuditRepository) List() ([]AuditEntry, error) {
+	entries := listAll(r.memView, r.store.audit)
+	slices.SortFunc(entries, func(a, b AuditEntry) int { return cmp.Compare(a.Sequence, b.Sequence) })
+	return entries, nil
+}
+
+type memInstructionRepository struct {
+	memView
+}
+
+func (r memInstructionRepository) NextID() (int, error) {
+	n, c := r.store.allocate(kindInstruction)
+	if err := r.write(c); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func (r memInstructionRepository) Get(id int) (*StandingInstruction, error) {
+	instruction, exists := lookup(r.memView, r.store.instructions, strconv.Itoa(id))
+	if !exists {
+		return nil, ErrInstructionNotFound
+	}
+	return &instruction, nil
+}
+
+func (r memInstructionRepository) Save(instruction StandingInstruction) error {
+	return r.write(change{kind: kindInstruction, key: strconv.Itoa(instruction.ID), value: instruction})
+}
+
+func (r memInstructionRepository) List() ([]StandingInstruction, error) {
+	instructions := listAll(r.memView, r.store.instructions)
+	slices.SortFunc(instructions, func(a, b StandingInstruction) int { return cmp.Compare(a.ID, b.ID) })
+	return instructions, nil
+}
+
+func attemptKey(instructionID, sequence int) string {
+	return fmt.Sprintf("%010d/%06d", instructionID, sequence)
+}
+
+func (r memInstructionRepository) AppendAttempt(attempt InstructionAttempt) error {
+	key := attemptKey(attempt.InstructionID, attempt.Sequence)
+	if _, exists := lookup(r.memView, r.store.attempts, key); exists {
+		return fmt.Errorf("attempt %d of standing instruction %d already exists", attempt.Sequence, attempt.InstructionID)
+	}
+	return r.write(change{kind: kindAttempt, key: key, value: attempt})
+}
+
+func (r memInstructionRepository) ListAttempts(instructionID int) ([]InstructionAttempt, error) {
+	var attempts []InstructionAttempt
+	for _, attempt := range listAll(r.memView, r.store.attempts) {
+		if attempt.InstructionID == instructionID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	slices.SortFunc(attempts, func(a, b InstructionAttempt) int { return cmp.Compare(a.Sequence, b.Sequence) })
+	return attempts, nil
+}
+
+type memIdempotencyRepository struct {
+	memView
+}
+
+func idempotencyKey(actor, key string) string {
+	return actor + "/" + key
+}
+
+func (r memIdempotencyRepository) Get(actor, key string) (*IdempotencyRecord, error) {
+	record, exists := lookup(r.memView, r.store.idempotency, idempotencyKey(actor, key))
+	if !exists {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r memIdempotencyRepository) Save(record IdempotencyRecord) error {
+	return r.write(change{kind: kindIdempotency, key: idempotencyKey(record.Actor, record.Key), value: record})
+}
+
+func (r memIdempotencyRepository) Purge(now time.Time) (int, error) {
+	purged := 0
+	for _, record := range listAll(r.memView, r.store.idempotency) {
+		if now.Before(record.ExpiresAt) {
+			continue
+		}
+		if err := r.write(change{kind: kindIdempotency, key: idempotencyKey(record.Actor, record.Key), deleted: true}); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,208 @@
+package bank
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// txKey is where a transaction falls in the order queries return: by
+// timestamp, then ID.
+type txKey struct {
+	at int64
+	id string
+}
+
+func keyOf(t *Transaction) txKey {
+	return txKey{at: t.Timestamp.UnixNano(), id: t.ID}
+}
+
+func compareKeys(a, b txKey) int {
+	if c := cmp.Compare(a.at, b.at); c != 0 {
+		return c
+	}
+	return strings.Compare(a.id, b.id)
+}
+
+// Index terms name the lists a transaction is filed under.
+const termAll = "*"
+
+func accountTerm(accountNumber string) string { return "a:" + accountNumber }
+
+func typeTerm(tType TransactionType) string { return "t:" + string(tType) }
+
+func statusTerm(status TransactionStatus) string { return "s:" + string(status) }
+
+func referenceTerm(reference string) string { return "r:" + reference }
+
+func indexTerms(t *Transaction) []string {
+	terms := []string{termAll, typeTerm(t.Type), statusTerm(t.Status)}
+	if t.FromAccount != "" {
+		terms = append(terms, accountTerm(t.FromAccount))
+	}
+	if t.ToAccount != "" && t.ToAccount != t.FromAccount {
+		terms = append(terms, accountTerm(t.ToAccount))
+	}
+	if t.ReferenceNumber != "" {
+		terms = append(terms, referenceTerm(t.ReferenceNumber))
+	}
+	return terms
+}
+
+// transactionIndex files the committed transactions of a MemoryStore under
+// every account they touch, their type, their status and their reference
+// number, each list in query order, so a query reads only the transactions
+// of its most selective list instead of all of them.
+//
+// Transactions almost always arrive in timestamp order, so most insertions
+// append. A status change moves a transaction between two status lists and
+// touches nothing else.
+type transactionIndex struct {
+	mu    sync.RWMutex
+	lists map[string][]txKey
+}
+
+func newTransactionIndex() *transactionIndex {
+	return &transactionIndex{lists: make(map[string][]txKey)}
+}
+
+// update refiles a transaction that changed from old to value. Either may be
+// nil, for an insertion or a deletion.
+func (ix *transactionIndex) update(old, value *Transaction) {
+	var oldKey, newKey txKey
+	var oldTerms, newTerms []string
+	if old != nil {
+		oldKey, oldTerms = keyOf(old), indexTerms(old)
+	}
+	if value != nil {
+		newKey, newTerms = keyOf(value), indexTerms(value)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for _, term := range oldTerms {
+		if oldKey == newKey && slices.Contains(newTerms, term) {
+			continue
+		}
+		list := ix.lists[term]
+		if i, found := slices.BinarySearchFunc(list, oldKey, compareKeys); found {
+			list = slices.Delete(list, i, i+1)
+		}
+		if len(list) == 0 {
+			delete(ix.lists, term)
+		} else {
+			ix.lists[term] = list
+		}
+	}
+	for _, term := range newTerms {
+		if oldKey == newKey && slices.Contains(oldTerms, term) {
+			continue
+		}
+		list := ix.lists[term]
+		if i, found := slices.BinarySearchFunc(list, newKey, compareKeys); !found {
+			ix.lists[term] = slices.Insert(list, i, newKey)
+		}
+	}
+}
+
+// plan picks the terms whose lists hold every transaction that can match q,
+// choosing the alternative with the fewest entries. The caller must hold
+// ix.mu.
+func (ix *transactionIndex) plan(q TransactionQuery) [][]txKey {
+	var alternatives [][]string
+	if q.ReferenceNumber != "" {
+		alternatives = append(alternatives, []string{referenceTerm(q.ReferenceNumber)})
+	}
+	if len(q.Accounts) > 0 {
+		alternatives = append(alternatives, mapTerms(q.Accounts, accountTerm))
+	}
+	if len(q.Types) > 0 {
+		alternatives = append(alternatives, mapTerms(q.Types, typeTerm))
+	}
+	if len(q.Statuses) > 0 {
+		alternatives = append(alternatives, mapTerms(q.Statuses, statusTerm))
+	}
+	alternatives = append(alternatives, []string{termAll})
+
+	var best [][]txKey
+	bestSize := -1
+	for _, terms := range alternatives {
+		lists := make([][]txKey, 0, len(terms))
+		size := 0
+		for _, term := range terms {
+			if list := ix.lists[term]; len(list) > 0 {
+				lists = append(lists, list)
+				size += len(list)
+			}
+		}
+		if bestSize < 0 || size < bestSize {
+			best, bestSize = lists, size
+		}
+	}
+	return best
+}
+
+func mapTerms[T any](values []T, term func(T) string) []string {
+	terms := make([]string, len(values))
+	for i, v := range values {
+		terms[i] = term(v)
+	}
+	return terms
+}
+
+// scan calls fn with the keys in lists, merged without duplicates, in q's
+// order and after q.After, until fn returns false. The caller must hold
+// ix.mu.
+func scan(lists [][]txKey, q TransactionQuery, fn func(txKey) bool) {
+	// next[i] is the position in lists[i] of its next key.
+	next := make([]int, len(lists))
+	for i, list := range lists {
+		switch {
+		case q.After == nil && q.NewestFirst:
+			next[i] = len(list) - 1
+		case q.After == nil:
+			next[i] = 0
+		default:
+			after := txKey{at: q.After.Timestamp.UnixNano(), id: q.After.ID}
+			j, found := slices.BinarySearchFunc(list, after, compareKeys)
+			if q.NewestFirst {
+				next[i] = j - 1
+			} else if found {
+				next[i] = j + 1
+			} else {
+				next[i] = j
+			}
+		}
+	}
+
+	step := 1
+	if q.NewestFirst {
+		step = -1
+	}
+	for {
+		var head *txKey
+		for i, list := range lists {
+			if next[i] < 0 || next[i] >= len(list) {
+				continue
+			}
+			k := &list[next[i]]
+			if head == nil || compareKeys(*k, *head)*step < 0 {
+				head = k
+			}
+		}
+		if head == nil {
+			return
+		}
+		key := *head
+		for i, list := range lists {
+			if next[i] >= 0 && next[i] < len(list) && list[next[i]] == key {
+				next[i] += step
+			}
+		}
+		if !fn(key) {
+			return
+		}
+	}
+}
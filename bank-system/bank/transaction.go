@@ -3,6 +3,7 @@ package bank
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -14,8 +15,16 @@ const (
 	Transfer   TransactionType = "TRANSFER"
 	Interest   TransactionType = "INTEREST"
 	Fee        TransactionType = "FEE"
+	// Reversal moves the money of an earlier transaction back. See
+	// ReverseTransaction.
+	Reversal TransactionType = "REVERSAL"
+	// Hold reserves money for a payment that has been authorized but not
+	// yet taken. See PlaceHold.
+	Hold TransactionType = "HOLD"
 )
 
+var transactionTypes = []TransactionType{Deposit, Withdrawal, Transfer, Interest, Fee, Reversal, Hold}
+
 type TransactionStatus string
 
 const (
@@ -23,234 +32,298 @@ const (
 	Completed TransactionStatus = "COMPLETED"
 	Failed    TransactionStatus = "FAILED"
 	Cancelled TransactionStatus = "CANCELLED"
+	// Reversed transactions have been undone by a Reversal.
+	Reversed TransactionStatus = "REVERSED"
+	// Expired holds lapsed before they were captured or released.
+	Expired TransactionStatus = "EXPIRED"
 )
 
+var transactionStatuses = []TransactionStatus{Pending, Completed, Failed, Cancelled, Reversed, Expired}
+
 type Transaction struct {
 	ID              string
 	Type            TransactionType
 	Status          TransactionStatus
 	FromAccount     string
 	ToAccount       string
-	Amount          float64
+	Amount          Money
 	Timestamp       time.Time
 	Description     string
 	ReferenceNumber string
-	BalanceAfter    float64
-	Fee             float64
+	BalanceAfter    Money
+	Fee             Money
+	// LinkedTransactionID is the transaction this one belongs to, such as
+	// the withdrawal a fee was charged for.
+	LinkedTransactionID string
+	// ExpiresAt is when a pending hold lapses.
+	ExpiresAt time.Time
 }
 
 type TransactionService interface {
-	CreateTransaction(tType TransactionType, fromAcc, toAcc string, amount float64, description string) (*Transaction, error)
+	CreateTransaction(tType TransactionType, fromAcc, toAcc string, amount Money, description string) (*Transaction, error)
+	// CreateTransactionAt records a completed transaction that took effect
+	// at an earlier time, such as interest credited at the end of its
+	// period, or now if at is zero. BalanceAfter is still the balance when
+	// it is recorded.
+	CreateTransactionAt(tType TransactionType, fromAcc, toAcc string, amount Money, description string, at time.Time) (*Transaction, error)
+	// CreatePendingTransaction records a transaction that has not moved
+	// any money yet and lapses at expiresAt.
+	CreatePendingTransaction(tType TransactionType, fromAcc, toAcc string, amount Money, description string, expiresAt time.Time) (*Transaction, error)
 	GetTransaction(transactionID string) (*Transaction, error)
+	// GetTransactionsByAccount returns the transactions from or to an
+	// account, oldest first.
 	GetTransactionsByAccount(accountNumber string) ([]*Transaction, error)
-	GetTransactionsByUser(userID int) ([]*Transaction, error)
-	GetTransactionsByType(tType TransactionType) ([]*Transaction, error)
-	GetTransactionsByDateRange(startDate, endDate time.Time) ([]*Transaction, error)
+	// Query returns the transactions that match q, in q's order.
+	Query(q TransactionQuery) ([]*Transaction, error)
 	UpdateTransactionStatus(transactionID string, status TransactionStatus) error
-	GetAllTransactions() []*Transaction
-	GetTransactionSummary(accountNumber string) *TransactionSummary
+	// LinkFee links a Fee transaction to the transaction it was charged
+	// for and adds its amount to that transaction's Fee.
+	LinkFee(transactionID, feeTransactionID string) error
+	// MarkReversed marks a transaction Reversed and links its reversal to
+	// it, giving the reversal the original's reference number.
+	MarkReversed(transactionID, reversalID string) error
+	// LinkCapture links the withdrawal that captured a hold to the hold.
+	LinkCapture(holdID, withdrawalID string) error
+	GetAllTransactions() ([]*Transaction, error)
+	GetTransactionSummary(accountNumber string) (*TransactionSummary, error)
 }
 
 type TransactionSummary struct {
 	AccountNumber     string
-	TotalDeposits     float64
-	TotalWithdrawals  float64
-	TotalTransfersOut float64
-	TotalTransfersIn  float64
-	TotalFees         float64
-	TransactionCount  int
-	LastTransaction   time.Time
+	TotalDeposits     Money
+	TotalWithdrawals  Money
+	TotalTransfersOut Money
+	TotalTransfersIn  Money
+	TotalFees         Money
+	// TotalInterest is interest paid less overdraft interest charged.
+	TotalInterest    Money
+	TransactionCount int
+	LastTransaction  time.Time
 }
 
 type transactionService struct {
-	transactions  map[string]*Transaction
-	bankingSystem *BankingSystem
+	mu       sync.Mutex
+	repo     TransactionRepository
+	accounts AccountService
+	users    UserService
+	clock    Clock
+	ids      IDGenerator
 }
 
-func NewTransactionService(bankingSystem *BankingSystem) TransactionService {
+func NewTransactionService(repo TransactionRepository, accounts AccountService, users UserService, clock Clock, ids IDGenerator) TransactionService {
 	return &transactionService{
-		transactions:  make(map[string]*Transaction),
-		bankingSystem: bankingSystem,
+		repo:     repo,
+		accounts: accounts,
+		users:    users,
+		clock:    clock,
+		ids:      ids,
 	}
 }
 
-func generateTransactionID() string {
-	return fmt.Sprintf("TXN%d", time.Now().UnixNano())
+func (ts *transactionService) CreateTransaction(tType TransactionType, fromAcc, toAcc string, amount Money, description string) (*Transaction, error) {
+	return ts.create(tType, fromAcc, toAcc, amount, description, Completed, time.Time{}, time.Time{})
 }
 
-func generateReferenceNumber() string {
-	return fmt.Sprintf("REF%d", time.Now().UnixNano())
+func (ts *transactionService) CreateTransactionAt(tType TransactionType, fromAcc, toAcc string, amount Money, description string, at time.Time) (*Transaction, error) {
+	return ts.create(tType, fromAcc, toAcc, amount, description, Completed, time.Time{}, at)
 }
 
-func (ts *transactionService) CreateTransaction(tType TransactionType, fromAcc, toAcc string, amount float64, description string) (*Transaction, error) {
-	if amount <= 0 {
+func (ts *transactionService) CreatePendingTransaction(tType TransactionType, fromAcc, toAcc string, amount Money, description string, expiresAt time.Time) (*Transaction, error) {
+	return ts.create(tType, fromAcc, toAcc, amount, description, Pending, expiresAt, time.Time{})
+}
+
+func (ts *transactionService) create(tType TransactionType, fromAcc, toAcc string, amount Money, description string, status TransactionStatus, expiresAt, at time.Time) (*Transaction, error) {
+	if !amount.IsPositive() {
 		return nil, errors.New("transaction amount must be positive")
 	}
+	if at.IsZero() {
+		at = ts.clock.Now()
+	}
 
 	transaction := &Transaction{
-		ID:              generateTransactionID(),
+		ID:              ts.ids.NewTransactionID(),
 		Type:            tType,
 		Status:          Pending,
 		FromAccount:     fromAcc,
 		ToAccount:       toAcc,
 		Amount:          amount,
-		Timestamp:       time.Now(),
+		Timestamp:       at,
 		Description:     description,
-		ReferenceNumber: generateReferenceNumber(),
-		Fee:             0.0,
+		ReferenceNumber: ts.ids.NewReferenceNumber(),
+		Fee:             Zero(amount.Currency()),
+		ExpiresAt:       expiresAt,
 	}
 
-	// Calculate balance after transaction
-	switch tType {
-	case Deposit:
-		if toAcc != "" {
-			if balance, err := ts.bankingSystem.accounts.GetBalance(toAcc); err == nil {
-				transaction.BalanceAfter = balance + amount
-			}
-		}
-	case Withdrawal:
-		if fromAcc != "" {
-			if balance, err := ts.bankingSystem.accounts.GetBalance(fromAcc); err == nil {
-				transaction.BalanceAfter = balance - amount
-			}
-		}
-	case Transfer:
-		if fromAcc != "" {
-			if balance, err := ts.bankingSystem.accounts.GetBalance(fromAcc); err == nil {
-				transaction.BalanceAfter = balance - amount
-			}
+	// Transactions are recorded after the account has been updated, so the
+	// current balance already reflects this movement.
+	balanceAccount := fromAcc
+	if fromAcc == "" {
+		balanceAccount = toAcc
+	}
+	if balanceAccount != "" {
+		if balance, err := ts.accounts.GetBalance(balanceAccount); err == nil {
+			transaction.BalanceAfter = balance
 		}
 	}
 
-	transaction.Status = Completed
-	ts.transactions[transaction.ID] = transaction
+	transaction.Status = status
+
+	if err := ts.repo.Create(*transaction); err != nil {
+		return nil, err
+	}
 
 	return transaction, nil
 }
 
 func (ts *transactionService) GetTransaction(transactionID string) (*Transaction, error) {
-	transaction, exists := ts.transactions[transactionID]
-	if !exists {
-		return nil, errors.New("transaction not found")
-	}
-	return transaction, nil
+	return ts.repo.Get(transactionID)
 }
 
 func (ts *transactionService) GetTransactionsByAccount(accountNumber string) ([]*Transaction, error) {
-	var accountTransactions []*Transaction
+	return ts.repo.Query(TransactionQuery{Accounts: []string{accountNumber}})
+}
 
-	for _, transaction := range ts.transactions {
-		if transaction.FromAccount == accountNumber || transaction.ToAccount == accountNumber {
-			accountTransactions = append(accountTransactions, transaction)
-		}
-	}
+func (ts *transactionService) Query(q TransactionQuery) ([]*Transaction, error) {
+	return ts.repo.Query(q)
+}
 
-	if len(accountTransactions) == 0 {
-		return nil, errors.New("no transactions found for account")
+func (ts *transactionService) UpdateTransactionStatus(transactionID string, status TransactionStatus) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	transaction, err := ts.repo.Get(transactionID)
+	if err != nil {
+		return err
 	}
 
-	return accountTransactions, nil
+	transaction.Status = status
+	return ts.repo.Save(*transaction)
 }
 
-func (ts *transactionService) GetTransactionsByUser(userID int) ([]*Transaction, error) {
-	user, err := ts.bankingSystem.users.Get(userID)
+func (ts *transactionService) LinkFee(transactionID, feeTransactionID string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	transaction, err := ts.repo.Get(transactionID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	var userTransactions []*Transaction
-
-	for _, accountNumber := range user.Accounts {
-		accountTxns, err := ts.GetTransactionsByAccount(accountNumber)
-		if err == nil {
-			userTransactions = append(userTransactions, accountTxns...)
-		}
+	fee, err := ts.repo.Get(feeTransactionID)
+	if err != nil {
+		return err
 	}
 
-	if len(userTransactions) == 0 {
-		return nil, errors.New("no transactions found for user")
+	if transaction.Fee, err = transaction.Fee.Add(fee.Amount); err != nil {
+		return err
 	}
+	fee.LinkedTransactionID = transactionID
 
-	return userTransactions, nil
+	if err := ts.repo.Save(*fee); err != nil {
+		return err
+	}
+	return ts.repo.Save(*transaction)
 }
 
-func (ts *transactionService) GetTransactionsByType(tType TransactionType) ([]*Transaction, error) {
-	var typeTransactions []*Transaction
+func (ts *transactionService) MarkReversed(transactionID, reversalID string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
 
-	for _, transaction := range ts.transactions {
-		if transaction.Type == tType {
-			typeTransactions = append(typeTransactions, transaction)
-		}
+	transaction, err := ts.repo.Get(transactionID)
+	if err != nil {
+		return err
 	}
-
-	if len(typeTransactions) == 0 {
-		return nil, fmt.Errorf("no %s transactions found", tType)
+	reversal, err := ts.repo.Get(reversalID)
+	if err != nil {
+		return err
 	}
 
-	return typeTransactions, nil
+	transaction.Status = Reversed
+	reversal.ReferenceNumber = transaction.ReferenceNumber
+	reversal.LinkedTransactionID = transactionID
+
+	if err := ts.repo.Save(*reversal); err != nil {
+		return err
+	}
+	return ts.repo.Save(*transaction)
 }
 
-func (ts *transactionService) GetTransactionsByDateRange(startDate, endDate time.Time) ([]*Transaction, error) {
-	var dateRangeTransactions []*Transaction
+func (ts *transactionService) LinkCapture(holdID, withdrawalID string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
 
-	for _, transaction := range ts.transactions {
-		if (transaction.Timestamp.Equal(startDate) || transaction.Timestamp.After(startDate)) &&
-			(transaction.Timestamp.Equal(endDate) || transaction.Timestamp.Before(endDate)) {
-			dateRangeTransactions = append(dateRangeTransactions, transaction)
-		}
+	if _, err := ts.repo.Get(holdID); err != nil {
+		return err
 	}
-
-	if len(dateRangeTransactions) == 0 {
-		return nil, errors.New("no transactions found in the specified date range")
+	withdrawal, err := ts.repo.Get(withdrawalID)
+	if err != nil {
+		return err
 	}
 
-	return dateRangeTransactions, nil
+	withdrawal.LinkedTransactionID = holdID
+	return ts.repo.Save(*withdrawal)
 }
 
-func (ts *transactionService) UpdateTransactionStatus(transactionID string, status TransactionStatus) error {
-	transaction, exists := ts.transactions[transactionID]
-	if !exists {
-		return errors.New("transaction not found")
-	}
-
-	transaction.Status = status
-	ts.transactions[transactionID] = transaction
-	return nil
+func (ts *transactionService) GetAllTransactions() ([]*Transaction, error) {
+	return ts.repo.List()
 }
 
-func (ts *transactionService) GetAllTransactions() []*Transaction {
-	allTransactions := make([]*Transaction, 0, len(ts.transactions))
-	for _, transaction := range ts.transactions {
-		allTransactions = append(allTransactions, transaction)
+func (ts *transactionService) GetTransactionSummary(accountNumber string) (*TransactionSummary, error) {
+	account, err := ts.accounts.GetAccountDetails(accountNumber)
+	if err != nil {
+		return nil, err
 	}
-	return allTransactions
-}
-
-func (ts *transactionService) GetTransactionSummary(accountNumber string) *TransactionSummary {
+	zero := Zero(account.Currency)
 	summary := &TransactionSummary{
-		AccountNumber: accountNumber,
+		AccountNumber:     accountNumber,
+		TotalDeposits:     zero,
+		TotalWithdrawals:  zero,
+		TotalTransfersOut: zero,
+		TotalTransfersIn:  zero,
+		TotalFees:         zero,
+		TotalInterest:     zero,
 	}
 
 	transactions, err := ts.GetTransactionsByAccount(accountNumber)
 	if err != nil {
-		return summary
+		return nil, err
 	}
 
 	for _, transaction := range transactions {
-		if transaction.Status == Completed {
+		// A captured hold is counted through the withdrawal it became.
+		if transaction.Status == Completed && transaction.Type != Hold {
+			amount := transaction.Amount
+			var total *Money
 			switch transaction.Type {
 			case Deposit:
-				summary.TotalDeposits += transaction.Amount
+				total = &summary.TotalDeposits
 			case Withdrawal:
-				summary.TotalWithdrawals += transaction.Amount
+				total = &summary.TotalWithdrawals
 			case Transfer:
 				if transaction.FromAccount == accountNumber {
-					summary.TotalTransfersOut += transaction.Amount
+					total = &summary.TotalTransfersOut
 				} else {
-					summary.TotalTransfersIn += transaction.Amount
+					total = &summary.TotalTransfersIn
 				}
 			case Fee:
-				summary.TotalFees += transaction.Amount
+				total = &summary.TotalFees
+			case Interest:
+				// Overdraft interest is charged from the account.
+				if transaction.FromAccount == accountNumber {
+					if amount, err = amount.Neg(); err != nil {
+						return nil, err
+					}
+				}
+				total = &summary.TotalInterest
+			case Reversal:
+				// The reversed transaction is no longer completed, so
+				// neither it nor its reversal counts towards the totals.
+			}
+			if total != nil {
+				sum, err := total.Add(amount)
+				if err != nil {
+					return nil, err
+				}
+				*total = sum
 			}
 			summary.TransactionCount++
 
@@ -260,7 +333,7 @@ func (ts *transactionService) GetTransactionSummary(accountNumber string) *Trans
 		}
 	}
 
-	return summary
+	return summary, nil
 }
 
 func (t Transaction) DisplayTransaction() {
@@ -277,14 +350,22 @@ func (t Transaction) DisplayTransaction() {
 		fmt.Printf("To: %s\n", t.ToAccount)
 	}
 
-	fmt.Printf("Amount: $%.2f\n", t.Amount)
+	fmt.Printf("Amount: %s\n", t.Amount)
+
+	if t.Fee.IsPositive() {
+		fmt.Printf("Fee: %s\n", t.Fee)
+	}
 
-	if t.Fee > 0 {
-		fmt.Printf("Fee: $%.2f\n", t.Fee)
+	if t.BalanceAfter.IsPositive() {
+		fmt.Printf("Balance After: %s\n", t.BalanceAfter)
 	}
 
-	if t.BalanceAfter > 0 {
-		fmt.Printf("Balance After: $%.2f\n", t.BalanceAfter)
+	if t.LinkedTransactionID != "" {
+		fmt.Printf("Linked To: %s\n", t.LinkedTransactionID)
+	}
+
+	if t.Status == Pending && !t.ExpiresAt.IsZero() {
+		fmt.Printf("Expires: %s\n", t.ExpiresAt.Format("2006-01-02 15:04:05"))
 	}
 
 	fmt.Printf("Time: %s\n", t.Timestamp.Format("2006-01-02 15:04:05"))
@@ -292,21 +373,43 @@ func (t Transaction) DisplayTransaction() {
 	fmt.Println("---------------------------")
 }
 
+// NetAmount returns money in minus money out for the summarised account.
+func (ts TransactionSummary) NetAmount() (Money, error) {
+	net, err := ts.TotalDeposits.Add(ts.TotalTransfersIn)
+	if err != nil {
+		return Money{}, err
+	}
+	if net, err = net.Add(ts.TotalInterest); err != nil {
+		return Money{}, err
+	}
+	for _, out := range []Money{ts.TotalWithdrawals, ts.TotalTransfersOut, ts.TotalFees} {
+		if net, err = net.Sub(out); err != nil {
+			return Money{}, err
+		}
+	}
+	return net, nil
+}
+
 func (ts TransactionSummary) DisplayTransactionSummary() {
 	fmt.Printf("\n=== Transaction Summary for Account: %s ===\n", ts.AccountNumber)
 	fmt.Printf("Total Transactions: %d\n", ts.TransactionCount)
-	fmt.Printf("Total Deposits: $%.2f\n", ts.TotalDeposits)
-	fmt.Printf("Total Withdrawals: $%.2f\n", ts.TotalWithdrawals)
-	fmt.Printf("Total Transfers Out: $%.2f\n", ts.TotalTransfersOut)
-	fmt.Printf("Total Transfers In: $%.2f\n", ts.TotalTransfersIn)
-	fmt.Printf("Total Fees: $%.2f\n", ts.TotalFees)
+	fmt.Printf("Total Deposits: %s\n", ts.TotalDeposits)
+	fmt.Printf("Total Withdrawals: %s\n", ts.TotalWithdrawals)
+	fmt.Printf("Total Transfers Out: %s\n", ts.TotalTransfersOut)
+	fmt.Printf("Total Transfers In: %s\n", ts.TotalTransfersIn)
+	fmt.Printf("Total Fees: %s\n", ts.TotalFees)
+	fmt.Printf("Total Interest: %s\n", ts.TotalInterest)
 
 	if !ts.LastTransaction.IsZero() {
 		fmt.Printf("Last Transaction: %s\n", ts.LastTransaction.Format("2006-01-02 15:04:05"))
 	}
 
-	netAmount := ts.TotalDeposits + ts.TotalTransfersIn - ts.TotalWithdrawals - ts.TotalTransfersOut - ts.TotalFees
-	fmt.Printf("Net Amount: $%.2f\n", netAmount)
+	netAmount, err := ts.NetAmount()
+	if err != nil {
+		fmt.Printf("Net Amount: unavailable (%v)\n", err)
+	} else {
+		fmt.Printf("Net Amount: %s\n", netAmount)
+	}
 	fmt.Println("-----------------------------------")
 }
 
@@ -320,4 +423,4 @@ func DisplayTransactions(transactions []*Transaction, title string) {
 	for _, transaction := range transactions {
 		transaction.DisplayTransaction()
 	}
-}
\ No newline at end of file
+}
@@ -3,19 +3,32 @@ package bank
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 )
 
 type User struct {
-	ID               int
-	FirstName        string
-	LastName         string
-	Email            string
-	Password         string
+	ID        int
+	FirstName string
+	LastName  string
+	Email     string
+	// Password is the plaintext password given to UserService.Create, which
+	// replaces it with PasswordHash. It is never stored or returned.
+	Password         string `json:"-"`
+	PasswordHash     string
 	Address          string
 	Phone            string
 	PanCardNumber    string
 	AadharCardNumber string
 	Accounts         []string
+	Role             Role
+	// DataKey is the wrapped key that encrypts the user's PII at rest; see
+	// KeyProvider.
+	DataKey string
+
+	// unmasked is set on users returned to a caller allowed to see their
+	// PII in full, so that DisplayUserInfo and String do not mask it.
+	unmasked bool
 }
 
 type UserService interface {
@@ -26,6 +39,8 @@ type UserService interface {
 	Delete(id int) error
 	List() ([]User, error)
 	AddAccountToUser(userID int, accountNumber string) error
+	VerifyPassword(email, password string) (*User, error)
+	ChangePassword(userID int, currentPassword, newPassword string) error
 }
 
 var (
@@ -36,116 +51,198 @@ var (
 )
 
 type userService struct {
-	users  map[int]User
-	nextID int
+	mu        sync.Mutex
+	repo      UserRepository
+	passwords *PasswordHasher
+	policy    PasswordPolicy
 }
 
-func NewUserService() UserService {
+func NewUserService(repo UserRepository, passwords *PasswordHasher, policy PasswordPolicy) UserService {
 	return &userService{
-		users:  make(map[int]User),
-		nextID: 1,
+		repo:      repo,
+		passwords: passwords,
+		policy:    policy,
 	}
 }
 
+// Create validates and normalizes a new user's details. A user with invalid
+// fields is rejected with a *ValidationError listing all of them.
 func (us *userService) Create(user User) (*User, error) {
-	if user.Email == "" || user.FirstName == "" || user.LastName == "" {
-		return nil, ErrInvalidInput
+	normalizeUser(&user)
+	if err := validateNewUser(user, us.policy); err != nil {
+		return nil, err
 	}
 
-	for _, u := range us.users {
-		if u.Email == user.Email {
-			return nil, ErrEmailExists
-		}
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	_, err := us.repo.GetByEmail(user.Email)
+	if err == nil {
+		return nil, ErrEmailExists
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return nil, err
 	}
 
-	user.ID = us.nextID
+	user.PasswordHash, err = us.passwords.Hash(user.Password)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+
+	user.ID, err = us.repo.NextID()
+	if err != nil {
+		return nil, err
+	}
 	user.Accounts = []string{}
-	us.users[us.nextID] = user
-	us.nextID++
+	if user.Role == "" {
+		user.Role = RoleCustomer
+	}
+
+	if err := us.repo.Save(user); err != nil {
+		return nil, err
+	}
 
 	return &user, nil
 }
 
 func (us *userService) Get(id int) (*User, error) {
-	user, exists := us.users[id]
-	if !exists {
-		return nil, ErrUserNotFound
-	}
-
-	return &user, nil
+	return us.repo.Get(id)
 }
 
 func (us *userService) GetByEmail(email string) (*User, error) {
-	for _, user := range us.users {
-		if user.Email == email {
-			return &user, nil
-		}
-	}
-
-	return nil, ErrUserNotFound
+	return us.repo.GetByEmail(email)
 }
 
 func (us *userService) Update(user User) error {
-	_, exists := us.users[user.ID]
-	if !exists {
-		return ErrUserNotFound
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	existing, err := us.repo.Get(user.ID)
+	if err != nil {
+		return err
 	}
 
-	us.users[user.ID] = user
-	return nil
+	// Passwords only change through ChangePassword.
+	user.Password = ""
+	user.PasswordHash = existing.PasswordHash
+	return us.repo.Save(user)
 }
 
 func (us *userService) Delete(id int) error {
-	_, exists := us.users[id]
-	if !exists {
-		return ErrUserNotFound
-	}
-	
-	delete(us.users, id)
-	return nil
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	return us.repo.Delete(id)
 }
 
 func (us *userService) List() ([]User, error) {
-	userList := make([]User, 0, len(us.users))
+	return us.repo.List()
+}
 
-	for _, user := range us.users {
-		userList = append(userList, user)
+func (us *userService) AddAccountToUser(userID int, accountNumber string) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	user, err := us.repo.Get(userID)
+	if err != nil {
+		return err
 	}
 
-	return userList, nil
+	if slices.Contains(user.Accounts, accountNumber) {
+		return errors.New("account already linked to user")
+	}
+
+	user.Accounts = append(user.Accounts, accountNumber)
+	return us.repo.Save(*user)
 }
 
-func (us *userService) AddAccountToUser(userID int, accountNumber string) error {
-	user, exists := us.users[userID]
-	if !exists {
-		return ErrUserNotFound
+// VerifyPassword returns the user with the given email if password is
+// theirs, and ErrInvalidCredentials otherwise. A hash made with outdated
+// parameters is replaced as a side effect.
+func (us *userService) VerifyPassword(email, password string) (*User, error) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	user, err := us.repo.GetByEmail(email)
+	if errors.Is(err, ErrUserNotFound) {
+		// Spend the same time as a real check so response times do not
+		// reveal which emails are registered.
+		us.passwords.Hash(password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	for _, acc := range user.Accounts {
-		if acc == accountNumber {
-			return errors.New("account already linked to user")
+	ok, needsRehash, err := us.passwords.Verify(user.PasswordHash, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user %d: %v", ErrInternal, user.ID, err)
+	}
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		hash, err := us.passwords.Hash(password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+		if err := us.repo.Save(*user); err != nil {
+			return nil, err
 		}
 	}
 
-	user.Accounts = append(user.Accounts, accountNumber)
-	us.users[userID] = user
-	return nil
+	return user, nil
+}
+
+func (us *userService) ChangePassword(userID int, currentPassword, newPassword string) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	user, err := us.repo.Get(userID)
+	if err != nil {
+		return err
+	}
+
+	ok, _, err := us.passwords.Verify(user.PasswordHash, currentPassword)
+	if err != nil {
+		return fmt.Errorf("%w: user %d: %v", ErrInternal, user.ID, err)
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+
+	if err := us.policy.Check(newPassword); err != nil {
+		return err
+	}
+
+	hash, err := us.passwords.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+	return us.repo.Save(*user)
 }
 
 func (u User) String() string {
+	u = u.forDisplay()
 	return fmt.Sprintf("User{ID: %d, Name: %s %s, Email: %s, Phone: %s, Address: %s, Accounts: %v}",
 		u.ID, u.FirstName, u.LastName, u.Email, u.Phone, u.Address, u.Accounts)
 }
 
 func (u User) DisplayUserInfo() {
+	u = u.forDisplay()
 	fmt.Println("=== User Information ===")
 	fmt.Printf("ID: %d\n", u.ID)
 	fmt.Printf("Name: %s %s\n", u.FirstName, u.LastName)
 	fmt.Printf("Email: %s\n", u.Email)
+	fmt.Printf("Role: %s\n", u.Role)
 	fmt.Printf("Phone: %s\n", u.Phone)
 	fmt.Printf("Address: %s\n", u.Address)
 	fmt.Printf("PAN Card: %s\n", u.PanCardNumber)
 	fmt.Printf("Aadhar Card: %s\n", u.AadharCardNumber)
 	fmt.Printf("Linked Accounts: %v\n", u.Accounts)
 	fmt.Println("------------------------")
-}
\ No newline at end of file
+}
@@ -0,0 +1,218 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrRequired       = errors.New("required")
+	ErrInvalidEmail   = errors.New("invalid email address")
+	ErrInvalidPhone   = errors.New("invalid Indian mobile number")
+	ErrInvalidPAN     = errors.New("invalid PAN")
+	ErrInvalidAadhaar = errors.New("invalid Aadhaar number")
+)
+
+// FieldError is a problem with one input field. Field uses the same names
+// as the JSON and gRPC APIs.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every field that failed validation. It matches
+// ErrInvalidInput and each field's error with errors.Is.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidInput, strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := []error{ErrInvalidInput}
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+func (e *ValidationError) add(field string, err error) {
+	if err != nil {
+		e.Fields = append(e.Fields, FieldError{Field: field, Err: err})
+	}
+}
+
+// err returns e if any field failed and nil otherwise.
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func required(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return ErrRequired
+	}
+	return nil
+}
+
+// ValidateEmail accepts a bare RFC 5322 address such as "a.b@example.com",
+// without a display name or angle brackets.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return ErrRequired
+	}
+	if len(email) > 254 {
+		return ErrInvalidEmail
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return ErrInvalidEmail
+	}
+
+	_, domain, _ := strings.Cut(addr.Address, "@")
+	if !strings.Contains(domain, ".") || strings.HasSuffix(domain, ".") {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+var mobilePattern = regexp.MustCompile(`^[6-9][0-9]{9}$`)
+
+// NormalizePhone strips spaces, hyphens and a +91, 91 or 0 prefix from an
+// Indian mobile number.
+func NormalizePhone(phone string) string {
+	phone = strings.NewReplacer(" ", "", "-", "").Replace(phone)
+	switch {
+	case strings.HasPrefix(phone, "+91"):
+		phone = phone[3:]
+	case len(phone) == 12 && strings.HasPrefix(phone, "91"):
+		phone = phone[2:]
+	case len(phone) == 11 && strings.HasPrefix(phone, "0"):
+		phone = phone[1:]
+	}
+	return phone
+}
+
+// ValidatePhone accepts a normalized ten-digit Indian mobile number, which
+// starts with 6, 7, 8 or 9.
+func ValidatePhone(phone string) error {
+	if phone == "" {
+		return ErrRequired
+	}
+	if !mobilePattern.MatchString(phone) {
+		return ErrInvalidPhone
+	}
+	return nil
+}
+
+var panPattern = regexp.MustCompile(`^[A-Z]{3}[ABCFGHJLPTK][A-Z][0-9]{4}[A-Z]$`)
+
+// ValidatePAN accepts a ten-character Permanent Account Number: five
+// letters, four digits and a letter. The fourth letter is the holder's
+// entity type, such as P for an individual or C for a company.
+func ValidatePAN(pan string) error {
+	if pan == "" {
+		return ErrRequired
+	}
+	if !panPattern.MatchString(pan) {
+		return ErrInvalidPAN
+	}
+	return nil
+}
+
+var aadhaarPattern = regexp.MustCompile(`^[2-9][0-9]{11}$`)
+
+// ValidateAadhaar accepts a twelve-digit Aadhaar number that does not start
+// with 0 or 1 and whose last digit is its Verhoeff check digit.
+func ValidateAadhaar(aadhaar string) error {
+	if aadhaar == "" {
+		return ErrRequired
+	}
+	if !aadhaarPattern.MatchString(aadhaar) || !verhoeffValid(aadhaar) {
+		return ErrInvalidAadhaar
+	}
+	return nil
+}
+
+// Verhoeff tables: multiplication in the dihedral group D5, the position
+// permutation and the inverse.
+var (
+	verhoeffD = [10][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+		{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+		{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+		{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+		{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+		{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+		{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+		{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+		{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	}
+	verhoeffP = [8][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+		{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+		{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+		{9, 4, 5, 3, 1, 2, 7, 6, 8, 0},
+		{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+		{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+		{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+	}
+)
+
+// verhoeffValid reports whether the last digit of digits is the Verhoeff
+// check digit of the rest. digits must contain only ASCII digits.
+func verhoeffValid(digits string) bool {
+	c := 0
+	for i := range len(digits) {
+		c = verhoeffD[c][verhoeffP[i%8][digits[len(digits)-1-i]-'0']]
+	}
+	return c == 0
+}
+
+// normalizeUser trims the user's fields and brings identifiers to their
+// canonical form, so that "abcpk1234f" and "2341 2341 2346" are accepted.
+func normalizeUser(user *User) {
+	user.FirstName = strings.TrimSpace(user.FirstName)
+	user.LastName = strings.TrimSpace(user.LastName)
+	user.Email = strings.TrimSpace(user.Email)
+	user.Address = strings.TrimSpace(user.Address)
+	user.Phone = NormalizePhone(strings.TrimSpace(user.Phone))
+	user.PanCardNumber = strings.ToUpper(strings.TrimSpace(user.PanCardNumber))
+	user.AadharCardNumber = strings.NewReplacer(" ", "", "-", "").Replace(user.AadharCardNumber)
+}
+
+// validateNewUser checks every field of a user about to be created and
+// reports all failures at once.
+func validateNewUser(user User, policy PasswordPolicy) error {
+	v := &ValidationError{}
+	v.add("first_name", required(user.FirstName))
+	v.add("last_name", required(user.LastName))
+	v.add("email", ValidateEmail(user.Email))
+	v.add("password", policy.Check(user.Password))
+	v.add("phone", ValidatePhone(user.Phone))
+	v.add("pan_card_number", ValidatePAN(user.PanCardNumber))
+	v.add("aadhar_card_number", ValidateAadhaar(user.AadharCardNumber))
+	return v.err()
+}
@@ -0,0 +1,206 @@
+package bank_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"bank-system/bank"
+)
+
+func TestValidateAadhaar(t *testing.T) {
+	valid := []string{"234123412346", "499186527026", "876543210988", "647827391010", "200000000009", "999999999999"}
+	for _, aadhaar := range valid {
+		if err := bank.ValidateAadhaar(aadhaar); err != nil {
+			t.Errorf("ValidateAadhaar(%q) = %v, want nil", aadhaar, err)
+		}
+	}
+
+	// The Verhoeff check digit catches every single-digit mistake, and every
+	// swap of two neighbouring digits.
+	for _, aadhaar := range valid {
+		for i := range len(aadhaar) {
+			for digit := '0'; digit <= '9'; digit++ {
+				if byte(digit) == aadhaar[i] || (i == 0 && digit < '2') {
+					continue
+				}
+				typo := aadhaar[:i] + string(digit) + aadhaar[i+1:]
+				if err := bank.ValidateAadhaar(typo); !errors.Is(err, bank.ErrInvalidAadhaar) {
+					t.Errorf("ValidateAadhaar(%q), a typo of %q, = %v, want ErrInvalidAadhaar", typo, aadhaar, err)
+				}
+			}
+		}
+		for i := range len(aadhaar) - 1 {
+			if aadhaar[i] == aadhaar[i+1] || (i == 0 && aadhaar[1] < '2') {
+				continue
+			}
+			swapped := aadhaar[:i] + aadhaar[i+1:i+2] + aadhaar[i:i+1] + aadhaar[i+2:]
+			if err := bank.ValidateAadhaar(swapped); !errors.Is(err, bank.ErrInvalidAadhaar) {
+				t.Errorf("ValidateAadhaar(%q), %q with digits swapped, = %v, want ErrInvalidAadhaar", swapped, aadhaar, err)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		aadhaar string
+		want    error
+	}{
+		{"", bank.ErrRequired},
+		{"134123412346", bank.ErrInvalidAadhaar}, // starts with 1
+		{"034123412346", bank.ErrInvalidAadhaar}, // starts with 0
+		{"23412341234", bank.ErrInvalidAadhaar},  // eleven digits
+		{"2341234123460", bank.ErrInvalidAadhaar},
+		{"2341 2341 2346", bank.ErrInvalidAadhaar}, // not normalized
+		{"23412341234a", bank.ErrInvalidAadhaar},
+		{"२३४१२३४१२३४६", bank.ErrInvalidAadhaar}, // Devanagari digits
+	} {
+		if err := bank.ValidateAadhaar(tc.aadhaar); !errors.Is(err, tc.want) {
+			t.Errorf("ValidateAadhaar(%q) = %v, want %v", tc.aadhaar, err, tc.want)
+		}
+	}
+}
+
+func TestValidatePAN(t *testing.T) {
+	// The fourth character is the holder's entity type.
+	for _, entity := range "ABCFGHJLPTK" {
+		pan := fmt.Sprintf("ABC%cK1234F", entity)
+		if err := bank.ValidatePAN(pan); err != nil {
+			t.Errorf("ValidatePAN(%q) = %v, want nil", pan, err)
+		}
+	}
+	for _, entity := range "DEIMNOQRSUVWXYZ" {
+		pan := fmt.Sprintf("ABC%cK1234F", entity)
+		if err := bank.ValidatePAN(pan); !errors.Is(err, bank.ErrInvalidPAN) {
+			t.Errorf("ValidatePAN(%q) = %v, want ErrInvalidPAN", pan, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		pan  string
+		want error
+	}{
+		{"", bank.ErrRequired},
+		{"abcpk1234f", bank.ErrInvalidPAN}, // not normalized
+		{"ABCPK1234", bank.ErrInvalidPAN},
+		{"ABCPK1234FF", bank.ErrInvalidPAN},
+		{"ABCPK12345", bank.ErrInvalidPAN},
+		{"AB1PK1234F", bank.ErrInvalidPAN},
+		{"ABCP11234F", bank.ErrInvalidPAN},
+		{"ABCPKA234F", bank.ErrInvalidPAN},
+		{" ABCPK1234F", bank.ErrInvalidPAN},
+	} {
+		if err := bank.ValidatePAN(tc.pan); !errors.Is(err, tc.want) {
+			t.Errorf("ValidatePAN(%q) = %v, want %v", tc.pan, err, tc.want)
+		}
+	}
+}
+
+func TestNormalizeAndValidatePhone(t *testing.T) {
+	for _, tc := range []struct {
+		phone, normalized string
+		want              error
+	}{
+		{"9876543210", "9876543210", nil},
+		{"98765 43210", "9876543210", nil},
+		{"98765-43210", "9876543210", nil},
+		{"+919876543210", "9876543210", nil},
+		{"+91 98765 43210", "9876543210", nil},
+		{"+91-98765-43210", "9876543210", nil},
+		{"919876543210", "9876543210", nil},
+		{"09876543210", "9876543210", nil},
+		{"6000000000", "6000000000", nil},
+		// 91 and 0 are only prefixes when the rest is ten digits, so a
+		// number that happens to start with them is left alone.
+		{"9198765432", "9198765432", nil},
+		{"9101234567", "9101234567", nil},
+		{"", "", bank.ErrRequired},
+		{"5876543210", "5876543210", bank.ErrInvalidPhone}, // not a mobile number
+		{"0612345678", "0612345678", bank.ErrInvalidPhone}, // a landline
+		{"987654321", "987654321", bank.ErrInvalidPhone},
+		{"98765432101", "98765432101", bank.ErrInvalidPhone},
+		{"+449876543210", "+449876543210", bank.ErrInvalidPhone},
+		{"(987) 654-3210", "(987)6543210", bank.ErrInvalidPhone},
+		{"9876543210x", "9876543210x", bank.ErrInvalidPhone},
+	} {
+		normalized := bank.NormalizePhone(tc.phone)
+		if normalized != tc.normalized {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tc.phone, normalized, tc.normalized)
+		}
+		if err := bank.ValidatePhone(normalized); !errors.Is(err, tc.want) {
+			t.Errorf("ValidatePhone(%q) = %v, want %v", normalized, err, tc.want)
+		}
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	for _, tc := range []struct {
+		email string
+		want  error
+	}{
+		{"test.user@example.com", nil},
+		{"test.user+savings@example.co.in", nil},
+		{"o'brien@example.com", nil},
+		{"a@b.in", nil},
+		{strings.Repeat("a", 64) + "@" + strings.Repeat("b", 180) + ".com", nil},
+		{"", bank.ErrRequired},
+		{strings.Repeat("a", 64) + "@" + strings.Repeat("b", 186) + ".com", bank.ErrInvalidEmail}, // 255 characters
+		{"test.user", bank.ErrInvalidEmail},
+		{"test.user@", bank.ErrInvalidEmail},
+		{"@example.com", bank.ErrInvalidEmail},
+		{"test.user@localhost", bank.ErrInvalidEmail},
+		{"test.user@example.", bank.ErrInvalidEmail},
+		{"test user@example.com", bank.ErrInvalidEmail},
+		{"test.user@@example.com", bank.ErrInvalidEmail},
+		{"Test User <test.user@example.com>", bank.ErrInvalidEmail},
+		{"<test.user@example.com>", bank.ErrInvalidEmail},
+		{" test.user@example.com", bank.ErrInvalidEmail},
+		{"test.user@example.com, other@example.com", bank.ErrInvalidEmail},
+	} {
+		if err := bank.ValidateEmail(tc.email); !errors.Is(err, tc.want) {
+			t.Errorf("ValidateEmail(%q) = %v, want %v", tc.email, err, tc.want)
+		}
+	}
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	bs := bank.NewBankingSystem(bank.WithPasswordParams(cheapParams))
+
+	// Identifiers are stored in their canonical form.
+	user, err := bs.CreateUser("  Test ", "User", " test.user@example.com ", "S3cure!Passw0rd",
+		"Motihari, Bihar", "+91 98765-43210", " abcpk1234f", "2341 2341-2346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	got := [...]string{user.FirstName, user.Email, user.Phone, user.PanCardNumber, user.AadharCardNumber}
+	if want := [...]string{"Test", "test.user@example.com", "9876543210", "ABCPK1234F", "234123412346"}; got != want {
+		t.Errorf("stored user %q, want %q", got, want)
+	}
+
+	// Every bad field is reported at once, by the name the APIs use.
+	_, err = bs.CreateUser(" ", "User", "test.user@localhost", "weak",
+		"Motihari, Bihar", "5876543210", "ABCDK1234F", "234123412345")
+	var invalid *bank.ValidationError
+	if !errors.As(err, &invalid) || !errors.Is(err, bank.ErrInvalidInput) {
+		t.Fatalf("CreateUser with bad fields = %v, want a ValidationError", err)
+	}
+	want := []bank.FieldError{
+		{Field: "first_name", Err: bank.ErrRequired},
+		{Field: "email", Err: bank.ErrInvalidEmail},
+		{Field: "password", Err: bank.ErrWeakPassword},
+		{Field: "phone", Err: bank.ErrInvalidPhone},
+		{Field: "pan_card_number", Err: bank.ErrInvalidPAN},
+		{Field: "aadhar_card_number", Err: bank.ErrInvalidAadhaar},
+	}
+	if len(invalid.Fields) != len(want) {
+		t.Fatalf("CreateUser reported %v, want %v", invalid.Fields, want)
+	}
+	for i, f := range invalid.Fields {
+		if f.Field != want[i].Field || !errors.Is(f.Err, want[i].Err) {
+			t.Errorf("field error %d = %v, want %v", i, f, want[i])
+		}
+	}
+	if users, err := bs.ListUsers(); err != nil || len(users) != 1 {
+		t.Errorf("ListUsers = %d users, %v; want only the valid one", len(users), err)
+	}
+}
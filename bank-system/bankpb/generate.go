@@ -0,0 +1,7 @@
+// Package bankpb holds the Go types and gRPC client and server stubs
+// generated from proto/bank/v1/bank.proto.
+package bankpb
+
+// Regenerate with buf and the protoc-gen-go and protoc-gen-go-grpc plugins
+// on PATH.
+//go:generate sh -c "cd .. && buf generate"
@@ -0,0 +1,122 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"bank-system/bank"
+	"bank-system/bankpb"
+)
+
+// publicMethods may be called without a session: signing up, logging in,
+// and logging out, which only needs the token it ends.
+var publicMethods = map[string]bool{
+	bankpb.UserService_CreateUser_FullMethodName: true,
+	bankpb.SessionService_Login_FullMethodName:   true,
+	bankpb.SessionService_Logout_FullMethodName:  true,
+}
+
+type callerKey struct{}
+
+// authenticate resolves the bearer token in ctx's metadata to a
+// BankingSystem acting for the session's user, and returns ctx carrying it.
+func authenticate(ctx context.Context, bs *bank.BankingSystem) (context.Context, error) {
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, toStatus(bank.ErrUnauthenticated)
+	}
+	as, err := bs.Authenticate(token)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return context.WithValue(ctx, callerKey{}, as), nil
+}
+
+// caller returns the BankingSystem that authenticate stored in ctx, which
+// acts for the session's user with that user's role. There is no fallback
+// to an unrestricted system: a call without a principal is refused.
+func caller(ctx context.Context) (*bank.BankingSystem, error) {
+	bs, ok := ctx.Value(callerKey{}).(*bank.BankingSystem)
+	if !ok {
+		return nil, toStatus(bank.ErrUnauthenticated)
+	}
+	if _, ok := bs.Principal(); !ok {
+		return nil, toStatus(bank.ErrUnauthenticated)
+	}
+	return bs, nil
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", false
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func unaryAuth(bs *bank.BankingSystem) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, bs)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(bs *bank.BankingSystem) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), bs)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a ServerStream whose context carries the caller.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+type sessionServer struct {
+	bankpb.UnimplementedSessionServiceServer
+	bank *bank.BankingSystem
+}
+
+func (s *sessionServer) Login(ctx context.Context, req *bankpb.LoginRequest) (*bankpb.Session, error) {
+	session, err := s.bank.Login(req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &bankpb.Session{
+		Token:     session.Token,
+		TokenType: "Bearer",
+		UserId:    int64(session.Principal.UserID),
+		ExpiresAt: toTimestamp(session.ExpiresAt),
+	}, nil
+}
+
+func (s *sessionServer) Logout(ctx context.Context, req *bankpb.LogoutRequest) (*bankpb.LogoutResponse, error) {
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, toStatus(bank.ErrUnauthenticated)
+	}
+	s.bank.Logout(token)
+	return &bankpb.LogoutResponse{}, nil
+}
@@ -0,0 +1,172 @@
+package grpcserver
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"bank-system/bank"
+	"bank-system/bankpb"
+)
+
+func toMoney(m bank.Money) *bankpb.Money {
+	return &bankpb.Money{
+		Currency:   string(m.Currency()),
+		Amount:     m.Amount(),
+		MinorUnits: m.MinorUnits(),
+	}
+}
+
+// fromMoney parses a request amount. Only the decimal amount is read;
+// minor_units is informational.
+func fromMoney(m *bankpb.Money, defaultCurrency bank.Currency) (bank.Money, error) {
+	currency := bank.Currency(m.GetCurrency())
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	return bank.ParseMoney(m.GetAmount(), currency)
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// toUser deliberately leaves out the password.
+func toUser(u bank.User) *bankpb.User {
+	return &bankpb.User{
+		Id:               int64(u.ID),
+		FirstName:        u.FirstName,
+		LastName:         u.LastName,
+		Email:            u.Email,
+		Address:          u.Address,
+		Phone:            u.Phone,
+		PanCardNumber:    u.PanCardNumber,
+		AadharCardNumber: u.AadharCardNumber,
+		AccountNumbers:   u.Accounts,
+		Role:             string(u.Role),
+	}
+}
+
+func toAccount(a bank.Account) *bankpb.Account {
+	return &bankpb.Account{
+		AccountNumber:   a.AccountNumber,
+		HolderName:      a.HolderName,
+		AccountType:     a.AccountType,
+		Currency:        string(a.Currency),
+		Balance:         toMoney(a.Balance),
+		OverdraftLimit:  toMoney(bank.NewMoney(a.OverdraftLimit.MinorUnits(), a.Currency)),
+		HeldAmount:      toMoney(bank.NewMoney(a.HeldAmount.MinorUnits(), a.Currency)),
+		Status:          string(a.Status),
+		StatusReason:    a.StatusReason,
+		StatusChangedBy: a.StatusChangedBy,
+		StatusChangedAt: toTimestamp(a.StatusChangedAt),
+		CreatedAt:       toTimestamp(a.CreatedAt),
+		UpdatedAt:       toTimestamp(a.UpdatedAt),
+	}
+}
+
+func toAccounts(accounts []bank.Account) *bankpb.ListAccountsResponse {
+	resp := &bankpb.ListAccountsResponse{Accounts: make([]*bankpb.Account, len(accounts))}
+	for i, account := range accounts {
+		resp.Accounts[i] = toAccount(account)
+	}
+	return resp
+}
+
+func toTransaction(t *bank.Transaction) *bankpb.Transaction {
+	return &bankpb.Transaction{
+		Id:                  t.ID,
+		Type:                string(t.Type),
+		Status:              string(t.Status),
+		FromAccount:         t.FromAccount,
+		ToAccount:           t.ToAccount,
+		Amount:              toMoney(t.Amount),
+		Fee:                 toMoney(t.Fee),
+		BalanceAfter:        toMoney(t.BalanceAfter),
+		Timestamp:           toTimestamp(t.Timestamp),
+		Description:         t.Description,
+		ReferenceNumber:     t.ReferenceNumber,
+		LinkedTransactionId: t.LinkedTransactionID,
+		ExpiresAt:           toTimestamp(t.ExpiresAt),
+	}
+}
+
+func toSummary(s *bank.TransactionSummary, net bank.Money) *bankpb.TransactionSummary {
+	return &bankpb.TransactionSummary{
+		AccountNumber:     s.AccountNumber,
+		TotalDeposits:     toMoney(s.TotalDeposits),
+		TotalWithdrawals:  toMoney(s.TotalWithdrawals),
+		TotalTransfersIn:  toMoney(s.TotalTransfersIn),
+		TotalTransfersOut: toMoney(s.TotalTransfersOut),
+		TotalFees:         toMoney(s.TotalFees),
+		TotalInterest:     toMoney(s.TotalInterest),
+		NetAmount:         toMoney(net),
+		TransactionCount:  int64(s.TransactionCount),
+		LastTransaction:   toTimestamp(s.LastTransaction),
+	}
+}
+
+func toStatement(st *bank.Statement) *bankpb.Statement {
+	resp := &bankpb.Statement{
+		AccountNumber:  st.AccountNumber,
+		HolderName:     st.HolderName,
+		AccountType:    st.AccountType,
+		Currency:       string(st.Currency),
+		From:           toTimestamp(st.From),
+		To:             toTimestamp(st.To),
+		OpeningBalance: toMoney(st.OpeningBalance),
+		ClosingBalance: toMoney(st.ClosingBalance),
+		TotalCredits:   toMoney(st.TotalCredits),
+		TotalDebits:    toMoney(st.TotalDebits),
+		GeneratedAt:    toTimestamp(st.GeneratedAt),
+	}
+	for _, entry := range st.Entries {
+		resp.Entries = append(resp.Entries, &bankpb.StatementEntry{
+			Date:            toTimestamp(entry.Date),
+			TransactionId:   entry.TransactionID,
+			ReferenceNumber: entry.ReferenceNumber,
+			Type:            string(entry.Type),
+			Description:     entry.Description,
+			Credit:          toMoney(entry.Credit),
+			Debit:           toMoney(entry.Debit),
+			Balance:         toMoney(entry.Balance),
+		})
+	}
+	return resp
+}
+
+func toInstruction(i bank.StandingInstruction) *bankpb.StandingInstruction {
+	return &bankpb.StandingInstruction{
+		Id:          int64(i.ID),
+		FromAccount: i.FromAccount,
+		ToAccount:   i.ToAccount,
+		Amount:      toMoney(i.Amount),
+		Description: i.Description,
+		Frequency:   string(i.Recurrence.Frequency),
+		DayOfMonth:  int32(i.Recurrence.DayOfMonth),
+		Start:       toTimestamp(i.Recurrence.Start),
+		End:         toTimestamp(i.Recurrence.End),
+		Status:      string(i.Status),
+		NextRun:     toTimestamp(i.NextRun),
+		RetryAt:     toTimestamp(i.RetryAt),
+		Retries:     int32(i.Retries),
+		CreatedBy:   i.CreatedBy,
+		CreatedAt:   toTimestamp(i.CreatedAt),
+		UpdatedAt:   toTimestamp(i.UpdatedAt),
+	}
+}
+
+func toAttempt(a bank.InstructionAttempt) *bankpb.InstructionAttempt {
+	return &bankpb.InstructionAttempt{
+		InstructionId: int64(a.InstructionID),
+		Sequence:      int32(a.Sequence),
+		ScheduledFor:  toTimestamp(a.ScheduledFor),
+		AttemptedAt:   toTimestamp(a.AttemptedAt),
+		Outcome:       string(a.Outcome),
+		TransactionId: a.TransactionID,
+		Error:         a.Error,
+	}
+}
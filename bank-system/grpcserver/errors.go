@@ -0,0 +1,85 @@
+package grpcserver
+
+import (
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"bank-system/bank"
+)
+
+// errorCodes maps the bank's sentinel errors to gRPC status codes. The first
+// match wins.
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{bank.ErrUserNotFound, codes.NotFound},
+	{bank.ErrAccountNotFound, codes.NotFound},
+	{bank.ErrTransactionNotFound, codes.NotFound},
+	{bank.ErrInstructionNotFound, codes.NotFound},
+	{bank.ErrEmailExists, codes.AlreadyExists},
+	{bank.ErrAccountExists, codes.AlreadyExists},
+	{bank.ErrInsufficientFunds, codes.FailedPrecondition},
+	{bank.ErrAccountNotActive, codes.FailedPrecondition},
+	{bank.ErrNonZeroBalance, codes.FailedPrecondition},
+	{bank.ErrOverdraftNotAllowed, codes.FailedPrecondition},
+	{bank.ErrInvalidTransition, codes.FailedPrecondition},
+	{bank.ErrAlreadyReversed, codes.FailedPrecondition},
+	{bank.ErrNotReversible, codes.FailedPrecondition},
+	{bank.ErrHoldNotPending, codes.FailedPrecondition},
+	{bank.ErrHoldExpired, codes.FailedPrecondition},
+	{bank.ErrInstructionStatus, codes.FailedPrecondition},
+	{bank.ErrIdempotencyKeyReused, codes.FailedPrecondition},
+	{bank.ErrInvalidCursor, codes.InvalidArgument},
+	{bank.ErrSameAccount, codes.InvalidArgument},
+	{bank.ErrInvalidAmount, codes.InvalidArgument},
+	{bank.ErrInvalidMoney, codes.InvalidArgument},
+	{bank.ErrMoneyOverflow, codes.InvalidArgument},
+	{bank.ErrUnknownCurrency, codes.InvalidArgument},
+	{bank.ErrCurrencyMismatch, codes.InvalidArgument},
+	{bank.ErrWeakPassword, codes.InvalidArgument},
+	{bank.ErrInvalidRole, codes.InvalidArgument},
+	{bank.ErrInvalidStatus, codes.InvalidArgument},
+	{bank.ErrInvalidCredentials, codes.Unauthenticated},
+	{bank.ErrUnauthenticated, codes.Unauthenticated},
+	{bank.ErrForbidden, codes.PermissionDenied},
+	{bank.ErrInvalidInput, codes.InvalidArgument},
+}
+
+func toStatus(err error) error {
+	var invalid *bank.ValidationError
+	if errors.As(err, &invalid) {
+		return validationStatus(invalid)
+	}
+
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return status.Error(e.code, err.Error())
+		}
+	}
+
+	log.Printf("grpc: %v", err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// validationStatus reports each invalid field as a BadRequest field
+// violation, so clients can point users at the fields to fix.
+func validationStatus(err *bank.ValidationError) error {
+	details := &errdetails.BadRequest{}
+	for _, f := range err.Fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Err.Error(),
+		})
+	}
+
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(details)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,641 @@
+// Package grpcserver serves the bank.v1 gRPC API defined in
+// proto/bank/v1/bank.proto on top of a BankingSystem.
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"bank-system/bank"
+	"bank-system/bankpb"
+)
+
+// NewServer returns a gRPC server with the session, user, account,
+// transaction and standing instruction services backed by bs. Every call
+// but signing up and logging in must carry a session token, and acts with
+// that session's access; bs itself is never exposed.
+func NewServer(bs *bank.BankingSystem, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(unaryAuth(bs)), grpc.ChainStreamInterceptor(streamAuth(bs)))
+	s := grpc.NewServer(opts...)
+	bankpb.RegisterSessionServiceServer(s, &sessionServer{bank: bs})
+	bankpb.RegisterUserServiceServer(s, &userServer{bank: bs})
+	bankpb.RegisterAccountServiceServer(s, &accountServer{})
+	bankpb.RegisterTransactionServiceServer(s, &transactionServer{})
+	bankpb.RegisterStandingInstructionServiceServer(s, &instructionServer{})
+	return s
+}
+
+type userServer struct {
+	bankpb.UnimplementedUserServiceServer
+	// bank is unrestricted and only used to sign up. Every other call acts
+	// as its caller.
+	bank *bank.BankingSystem
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *bankpb.CreateUserRequest) (*bankpb.User, error) {
+	user, err := s.bank.CreateUser(req.GetFirstName(), req.GetLastName(), req.GetEmail(), req.GetPassword(),
+		req.GetAddress(), req.GetPhone(), req.GetPanCardNumber(), req.GetAadharCardNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toUser(*user), nil
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *bankpb.GetUserRequest) (*bankpb.User, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := bs.GetUser(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toUser(*user), nil
+}
+
+func (s *userServer) GetUserByEmail(ctx context.Context, req *bankpb.GetUserByEmailRequest) (*bankpb.User, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := bs.GetUserByEmail(req.GetEmail())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toUser(*user), nil
+}
+
+func (s *userServer) ListUsers(ctx context.Context, req *bankpb.ListUsersRequest) (*bankpb.ListUsersResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := bs.ListUsers()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &bankpb.ListUsersResponse{Users: make([]*bankpb.User, len(users))}
+	for i, user := range users {
+		resp.Users[i] = toUser(user)
+	}
+	return resp, nil
+}
+
+func (s *userServer) ListUserAccounts(ctx context.Context, req *bankpb.ListUserAccountsRequest) (*bankpb.ListAccountsResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := bs.ListUserAccounts(int(req.GetUserId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccounts(accounts), nil
+}
+
+func (s *userServer) AssignRole(ctx context.Context, req *bankpb.AssignRoleRequest) (*bankpb.User, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.AssignRole(int(req.GetUserId()), bank.Role(req.GetRole())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	user, err := bs.GetUser(int(req.GetUserId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toUser(*user), nil
+}
+
+type accountServer struct {
+	bankpb.UnimplementedAccountServiceServer
+}
+
+func (s *accountServer) CreateAccount(ctx context.Context, req *bankpb.CreateAccountRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := bs.CreateAccount(req.GetAccountNumber(), req.GetHolderName(), req.GetAccountType(), int(req.GetUserId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+func (s *accountServer) GetAccount(ctx context.Context, req *bankpb.GetAccountRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := bs.GetAccount(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+func (s *accountServer) GetBalance(ctx context.Context, req *bankpb.GetBalanceRequest) (*bankpb.Balance, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := bs.GetBalance(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	available, err := bs.GetAvailableBalance(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &bankpb.Balance{AccountNumber: req.GetAccountNumber(), Balance: toMoney(balance), Available: toMoney(available)}, nil
+}
+
+func (s *accountServer) ListAccounts(ctx context.Context, req *bankpb.ListAccountsRequest) (*bankpb.ListAccountsResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := bs.ListAccounts()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccounts(accounts), nil
+}
+
+func (s *accountServer) Deposit(ctx context.Context, req *bankpb.DepositRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := fromMoney(req.GetAmount(), accountCurrency(bs, req.GetAccountNumber()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	transaction, err := idempotent(bs, req.GetIdempotencyKey()).Deposit(req.GetAccountNumber(), amount)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *accountServer) Withdraw(ctx context.Context, req *bankpb.WithdrawRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := fromMoney(req.GetAmount(), accountCurrency(bs, req.GetAccountNumber()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	transaction, err := idempotent(bs, req.GetIdempotencyKey()).Withdraw(req.GetAccountNumber(), amount)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *accountServer) CloseAccount(ctx context.Context, req *bankpb.CloseAccountRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.CloseAccount(req.GetAccountNumber()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	account, err := bs.GetAccount(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+func (s *accountServer) SetOverdraftLimit(ctx context.Context, req *bankpb.SetOverdraftLimitRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := fromMoney(req.GetLimit(), accountCurrency(bs, req.GetAccountNumber()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := bs.SetOverdraftLimit(req.GetAccountNumber(), limit); err != nil {
+		return nil, toStatus(err)
+	}
+
+	account, err := bs.GetAccount(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+func (s *accountServer) FreezeAccount(ctx context.Context, req *bankpb.FreezeAccountRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.FreezeAccount(req.GetAccountNumber(), req.GetReason()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	account, err := bs.GetAccount(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+func (s *accountServer) UnfreezeAccount(ctx context.Context, req *bankpb.UnfreezeAccountRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.UnfreezeAccount(req.GetAccountNumber(), req.GetReason()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	account, err := bs.GetAccount(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+func (s *accountServer) ChangeAccountStatus(ctx context.Context, req *bankpb.ChangeAccountStatusRequest) (*bankpb.Account, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accountStatus, err := bank.ParseAccountStatus(req.GetStatus())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := bs.ChangeAccountStatus(req.GetAccountNumber(), accountStatus, req.GetReason()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	account, err := bs.GetAccount(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toAccount(*account), nil
+}
+
+type transactionServer struct {
+	bankpb.UnimplementedTransactionServiceServer
+}
+
+func (s *transactionServer) Transfer(ctx context.Context, req *bankpb.TransferRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := fromMoney(req.GetAmount(), accountCurrency(bs, req.GetFromAccount()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	transaction, err := idempotent(bs, req.GetIdempotencyKey()).Transfer(req.GetFromAccount(), req.GetToAccount(), amount)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *transactionServer) GetTransaction(ctx context.Context, req *bankpb.GetTransactionRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := bs.GetTransaction(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *transactionServer) ReverseTransaction(ctx context.Context, req *bankpb.ReverseTransactionRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reversal, err := bs.ReverseTransaction(req.GetId(), req.GetReason())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(reversal), nil
+}
+
+func (s *transactionServer) PlaceHold(ctx context.Context, req *bankpb.PlaceHoldRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := fromMoney(req.GetAmount(), accountCurrency(bs, req.GetAccountNumber()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	hold, err := bs.PlaceHold(req.GetAccountNumber(), amount, req.GetDescription())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(hold), nil
+}
+
+func (s *transactionServer) CaptureHold(ctx context.Context, req *bankpb.CaptureHoldRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := bs.GetTransaction(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	amount := hold.Amount
+	if req.GetAmount() != nil {
+		if amount, err = fromMoney(req.GetAmount(), hold.Amount.Currency()); err != nil {
+			return nil, toStatus(err)
+		}
+	}
+
+	withdrawal, err := bs.CaptureHold(req.GetId(), amount)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(withdrawal), nil
+}
+
+func (s *transactionServer) ReleaseHold(ctx context.Context, req *bankpb.ReleaseHoldRequest) (*bankpb.Transaction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := bs.ReleaseHold(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toTransaction(hold), nil
+}
+
+func (s *transactionServer) StreamTransactionHistory(req *bankpb.TransactionHistoryRequest, stream grpc.ServerStreamingServer[bankpb.Transaction]) error {
+	bs, err := caller(stream.Context())
+	if err != nil {
+		return err
+	}
+	var transactions []*bank.Transaction
+	if req.GetAccountNumber() == "" {
+		transactions, err = bs.ListTransactions()
+	} else {
+		transactions, err = bs.ListAccountTransactions(req.GetAccountNumber())
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for _, transaction := range transactions {
+		if err := stream.Send(toTransaction(transaction)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *transactionServer) QueryTransactions(ctx context.Context, req *bankpb.QueryTransactionsRequest) (*bankpb.QueryTransactionsResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter := bank.TransactionFilter{
+		AccountNumber:   req.GetAccountNumber(),
+		UserID:          int(req.GetUserId()),
+		Text:            req.GetText(),
+		ReferenceNumber: req.GetReferenceNumber(),
+		NewestFirst:     req.GetNewestFirst(),
+		Limit:           int(req.GetPageSize()),
+		Cursor:          req.GetPageToken(),
+	}
+	for _, t := range req.GetTypes() {
+		filter.Types = append(filter.Types, bank.TransactionType(t))
+	}
+	for _, status := range req.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, bank.TransactionStatus(status))
+	}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		filter.Until = req.GetUntil().AsTime()
+	}
+	currency := accountCurrency(bs, req.GetAccountNumber())
+	if req.GetMinAmount() != nil {
+		amount, err := fromMoney(req.GetMinAmount(), currency)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		filter.MinAmount = &amount
+	}
+	if req.GetMaxAmount() != nil {
+		amount, err := fromMoney(req.GetMaxAmount(), currency)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		filter.MaxAmount = &amount
+	}
+
+	page, err := bs.QueryTransactions(filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &bankpb.QueryTransactionsResponse{NextPageToken: page.NextCursor}
+	for _, transaction := range page.Transactions {
+		resp.Transactions = append(resp.Transactions, toTransaction(transaction))
+	}
+	return resp, nil
+}
+
+func (s *transactionServer) GetTransactionSummary(ctx context.Context, req *bankpb.GetTransactionSummaryRequest) (*bankpb.TransactionSummary, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := bs.GetAccountSummary(req.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	net, err := summary.NetAmount()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toSummary(summary, net), nil
+}
+
+func (s *transactionServer) GetStatement(ctx context.Context, req *bankpb.GetStatementRequest) (*bankpb.GetStatementResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := bs.MonthlyStatement(req.GetAccountNumber(), int(req.GetYear()), time.Month(req.GetMonth()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &bankpb.GetStatementResponse{Statement: toStatement(statement)}
+	if format := bank.StatementFormat(req.GetFormat()); format != "" {
+		var document bytes.Buffer
+		if err := statement.Render(&document, format); err != nil {
+			return nil, toStatus(err)
+		}
+		resp.Document = document.Bytes()
+		resp.ContentType = format.ContentType()
+	}
+	return resp, nil
+}
+
+type instructionServer struct {
+	bankpb.UnimplementedStandingInstructionServiceServer
+}
+
+func (s *instructionServer) CreateStandingInstruction(ctx context.Context, req *bankpb.CreateStandingInstructionRequest) (*bankpb.StandingInstruction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := fromMoney(req.GetAmount(), accountCurrency(bs, req.GetFromAccount()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	frequency, err := bank.ParseFrequency(req.GetFrequency())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	recurrence := bank.Recurrence{Frequency: frequency, DayOfMonth: int(req.GetDayOfMonth())}
+	if req.GetStart() != nil {
+		recurrence.Start = req.GetStart().AsTime()
+	}
+	if req.GetEnd() != nil {
+		recurrence.End = req.GetEnd().AsTime()
+	}
+
+	instruction, err := bs.CreateStandingInstruction(req.GetFromAccount(), req.GetToAccount(), amount, req.GetDescription(), recurrence)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toInstruction(*instruction), nil
+}
+
+func (s *instructionServer) GetStandingInstruction(ctx context.Context, req *bankpb.GetStandingInstructionRequest) (*bankpb.StandingInstruction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instruction, err := bs.GetStandingInstruction(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toInstruction(*instruction), nil
+}
+
+func (s *instructionServer) ListStandingInstructions(ctx context.Context, req *bankpb.ListStandingInstructionsRequest) (*bankpb.ListStandingInstructionsResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instructions, err := bs.ListStandingInstructions()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &bankpb.ListStandingInstructionsResponse{Instructions: make([]*bankpb.StandingInstruction, len(instructions))}
+	for i, instruction := range instructions {
+		resp.Instructions[i] = toInstruction(instruction)
+	}
+	return resp, nil
+}
+
+func (s *instructionServer) PauseStandingInstruction(ctx context.Context, req *bankpb.PauseStandingInstructionRequest) (*bankpb.StandingInstruction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instruction, err := bs.PauseStandingInstruction(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toInstruction(*instruction), nil
+}
+
+func (s *instructionServer) ResumeStandingInstruction(ctx context.Context, req *bankpb.ResumeStandingInstructionRequest) (*bankpb.StandingInstruction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instruction, err := bs.ResumeStandingInstruction(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toInstruction(*instruction), nil
+}
+
+func (s *instructionServer) CancelStandingInstruction(ctx context.Context, req *bankpb.CancelStandingInstructionRequest) (*bankpb.StandingInstruction, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instruction, err := bs.CancelStandingInstruction(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toInstruction(*instruction), nil
+}
+
+func (s *instructionServer) ListInstructionAttempts(ctx context.Context, req *bankpb.ListInstructionAttemptsRequest) (*bankpb.ListInstructionAttemptsResponse, error) {
+	bs, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := bs.ListInstructionAttempts(int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &bankpb.ListInstructionAttemptsResponse{Attempts: make([]*bankpb.InstructionAttempt, len(attempts))}
+	for i, attempt := range attempts {
+		resp.Attempts[i] = toAttempt(attempt)
+	}
+	return resp, nil
+}
+
+// accountCurrency is the currency assumed for a request amount that names
+// none. An unknown account falls back to the default currency and is
+// reported by the operation itself.
+func accountCurrency(bs *bank.BankingSystem, accountNumber string) bank.Currency {
+	account, err := bs.GetAccount(accountNumber)
+	if err != nil {
+		return bank.DefaultCurrency
+	}
+	return account.Currency
+}
+
+// idempotent applies a request's idempotency key, if it has one, to bs.
+func idempotent(bs *bank.BankingSystem, key string) *bank.BankingSystem {
+	if key == "" {
+		return bs
+	}
+	return bs.Idempotent(key)
+}
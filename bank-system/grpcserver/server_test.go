@@ -0,0 +1,545 @@
+package grpcserver_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"bank-system/bank"
+	"bank-system/bankpb"
+	"bank-system/grpcserver"
+)
+
+type clients struct {
+	sessions     bankpb.SessionServiceClient
+	users        bankpb.UserServiceClient
+	accounts     bankpb.AccountServiceClient
+	transactions bankpb.TransactionServiceClient
+	// admin carries the session of an administrator, who may do anything.
+	admin context.Context
+}
+
+// newTestClients serves a fresh in-memory bank, which opens accounts pending
+// KYC, over an in-process listener and returns clients connected to it.
+func newTestClients(t *testing.T) clients {
+	t.Helper()
+
+	bs := bank.NewBankingSystem(bank.WithInitialAccountStatus(bank.StatusPendingKYC))
+	admin, err := bs.CreateUser("Bank", "Admin", "admin@example.com", "S3cure!Passw0rd",
+		"1 Bank Street", "9876543210", "ABCPK1234F", "234123412346")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if err := bs.AssignRole(admin.ID, bank.RoleAdmin); err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpcserver.NewServer(bs)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dialing bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	c := clients{
+		sessions:     bankpb.NewSessionServiceClient(conn),
+		users:        bankpb.NewUserServiceClient(conn),
+		accounts:     bankpb.NewAccountServiceClient(conn),
+		transactions: bankpb.NewTransactionServiceClient(conn),
+	}
+	c.admin = c.login(t, "admin@example.com")
+	return c
+}
+
+// login logs in as the user with email and returns a context that carries
+// the session token.
+func (c clients) login(t *testing.T, email string) context.Context {
+	t.Helper()
+	session, err := c.sessions.Login(context.Background(), &bankpb.LoginRequest{Email: email, Password: "S3cure!Passw0rd"})
+	if err != nil {
+		t.Fatalf("Login(%s): %v", email, err)
+	}
+	return withToken(session.GetToken())
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func inr(amount string) *bankpb.Money {
+	return &bankpb.Money{Currency: "INR", Amount: amount}
+}
+
+// createCustomer signs up a user with the given email and, as the admin,
+// opens and activates one account per account number.
+func createCustomer(t *testing.T, c clients, email string, accountNumbers ...string) *bankpb.User {
+	t.Helper()
+	ctx := c.admin
+
+	user, err := c.users.CreateUser(context.Background(), &bankpb.CreateUserRequest{
+		FirstName:        "Test",
+		LastName:         "User",
+		Email:            email,
+		Password:         "S3cure!Passw0rd",
+		Address:          "1 Test Street",
+		Phone:            "9876543210",
+		PanCardNumber:    "ABCPK1234F",
+		AadharCardNumber: "234123412346",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	for _, number := range accountNumbers {
+		_, err := c.accounts.CreateAccount(ctx, &bankpb.CreateAccountRequest{
+			UserId:        user.GetId(),
+			AccountNumber: number,
+			HolderName:    "Test User",
+			AccountType:   "Savings",
+		})
+		if err != nil {
+			t.Fatalf("CreateAccount(%s): %v", number, err)
+		}
+		_, err = c.accounts.ChangeAccountStatus(ctx, &bankpb.ChangeAccountStatusRequest{AccountNumber: number, Status: "Active", Reason: "KYC verified"})
+		if err != nil {
+			t.Fatalf("ChangeAccountStatus(%s): %v", number, err)
+		}
+	}
+	return user
+}
+
+func TestCreateUserAndAccounts(t *testing.T) {
+	c := newTestClients(t)
+	ctx := c.admin
+
+	user := createCustomer(t, c, "test.user@example.com", "ACC001", "ACC002")
+
+	got, err := c.users.GetUserByEmail(ctx, &bankpb.GetUserByEmailRequest{Email: "test.user@example.com"})
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if got.GetId() != user.GetId() {
+		t.Errorf("GetUserByEmail returned user %d, want %d", got.GetId(), user.GetId())
+	}
+	if want := []string{"ACC001", "ACC002"}; len(got.GetAccountNumbers()) != 2 ||
+		got.GetAccountNumbers()[0] != want[0] || got.GetAccountNumbers()[1] != want[1] {
+		t.Errorf("account numbers = %v, want %v", got.GetAccountNumbers(), want)
+	}
+
+	// Accounts open pending KYC and take deposits only until activated.
+	opened, err := c.accounts.CreateAccount(ctx, &bankpb.CreateAccountRequest{
+		UserId: user.GetId(), AccountNumber: "ACC003", HolderName: "Test User", AccountType: "Savings",
+	})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if opened.GetStatus() != "PendingKYC" {
+		t.Errorf("new account is %s, want PendingKYC", opened.GetStatus())
+	}
+	if _, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC003", Amount: inr("10.00")}); err != nil {
+		t.Errorf("Deposit to a PendingKYC account: %v", err)
+	}
+	if _, err := c.accounts.Withdraw(ctx, &bankpb.WithdrawRequest{AccountNumber: "ACC003", Amount: inr("1.00")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Withdraw from a PendingKYC account = %v, want FailedPrecondition", err)
+	}
+	activated, err := c.accounts.ChangeAccountStatus(ctx, &bankpb.ChangeAccountStatusRequest{AccountNumber: "ACC003", Status: "active", Reason: "KYC verified"})
+	if err != nil {
+		t.Fatalf("ChangeAccountStatus: %v", err)
+	}
+	if activated.GetStatus() != "Active" || activated.GetStatusReason() != "KYC verified" {
+		t.Errorf("activated account = %v", activated)
+	}
+
+	accounts, err := c.users.ListUserAccounts(ctx, &bankpb.ListUserAccountsRequest{UserId: user.GetId()})
+	if err != nil {
+		t.Fatalf("ListUserAccounts: %v", err)
+	}
+	for _, account := range accounts.GetAccounts() {
+		if account.GetAccountNumber() == "ACC003" {
+			continue
+		}
+		if account.GetStatus() != "Active" || account.GetCurrency() != "INR" || account.GetBalance().GetMinorUnits() != 0 {
+			t.Errorf("new account = %v, want an active, empty INR account", account)
+		}
+		if account.GetCreatedAt() == nil {
+			t.Errorf("account %s has no creation time", account.GetAccountNumber())
+		}
+	}
+}
+
+func TestMoneyMovements(t *testing.T) {
+	c := newTestClients(t)
+	ctx := c.admin
+	createCustomer(t, c, "test.user@example.com", "ACC001", "ACC002")
+
+	deposit, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC001", Amount: inr("500.00")})
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	if deposit.GetType() != "DEPOSIT" || deposit.GetBalanceAfter().GetAmount() != "500.00" {
+		t.Errorf("deposit = %v, want a DEPOSIT leaving 500.00", deposit)
+	}
+
+	// An amount without a currency is taken in the account's currency.
+	if _, err := c.accounts.Withdraw(ctx, &bankpb.WithdrawRequest{AccountNumber: "ACC001", Amount: &bankpb.Money{Amount: "120.25"}}); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+
+	transfer, err := c.transactions.Transfer(ctx, &bankpb.TransferRequest{FromAccount: "ACC001", ToAccount: "ACC002", Amount: inr("79.75")})
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	got, err := c.transactions.GetTransaction(ctx, &bankpb.GetTransactionRequest{Id: transfer.GetId()})
+	if err != nil {
+		t.Fatalf("GetTransaction: %v", err)
+	}
+	if got.GetFromAccount() != "ACC001" || got.GetToAccount() != "ACC002" || got.GetAmount().GetMinorUnits() != 7975 {
+		t.Errorf("GetTransaction = %v, want the 79.75 transfer", got)
+	}
+
+	for account, want := range map[string]string{"ACC001": "300.00", "ACC002": "79.75"} {
+		balance, err := c.accounts.GetBalance(ctx, &bankpb.GetBalanceRequest{AccountNumber: account})
+		if err != nil {
+			t.Fatalf("GetBalance(%s): %v", account, err)
+		}
+		if balance.GetBalance().GetAmount() != want {
+			t.Errorf("balance of %s = %s, want %s", account, balance.GetBalance().GetAmount(), want)
+		}
+	}
+
+	summary, err := c.transactions.GetTransactionSummary(ctx, &bankpb.GetTransactionSummaryRequest{AccountNumber: "ACC001"})
+	if err != nil {
+		t.Fatalf("GetTransactionSummary: %v", err)
+	}
+	if summary.GetTransactionCount() != 3 || summary.GetNetAmount().GetAmount() != "300.00" ||
+		summary.GetTotalTransfersOut().GetAmount() != "79.75" {
+		t.Errorf("summary = %v, want 3 transactions netting 300.00", summary)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	c := newTestClients(t)
+	ctx := c.admin
+	createCustomer(t, c, "test.user@example.com", "ACC001", "ACC002")
+	if _, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC001", Amount: inr("10.00")}); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"unknown user", func() error {
+			_, err := c.users.GetUser(ctx, &bankpb.GetUserRequest{Id: 999})
+			return err
+		}, codes.NotFound},
+		{"unknown account", func() error {
+			_, err := c.accounts.GetAccount(ctx, &bankpb.GetAccountRequest{AccountNumber: "NOPE"})
+			return err
+		}, codes.NotFound},
+		{"unknown transaction", func() error {
+			_, err := c.transactions.GetTransaction(ctx, &bankpb.GetTransactionRequest{Id: "TXN0"})
+			return err
+		}, codes.NotFound},
+		{"duplicate email", func() error {
+			_, err := c.users.CreateUser(ctx, &bankpb.CreateUserRequest{FirstName: "A", LastName: "B", Email: "test.user@example.com", Password: "An0ther!Passw0rd",
+				Phone: "9876543210", PanCardNumber: "ABCPK1234F", AadharCardNumber: "234123412346"})
+			return err
+		}, codes.AlreadyExists},
+		{"weak password", func() error {
+			_, err := c.users.CreateUser(ctx, &bankpb.CreateUserRequest{FirstName: "A", LastName: "B", Email: "new.user@example.com", Password: "password"})
+			return err
+		}, codes.InvalidArgument},
+		{"duplicate account", func() error {
+			_, err := c.accounts.CreateAccount(ctx, &bankpb.CreateAccountRequest{UserId: 1, AccountNumber: "ACC001", HolderName: "Test User", AccountType: "Savings"})
+			return err
+		}, codes.AlreadyExists},
+		{"insufficient funds", func() error {
+			_, err := c.accounts.Withdraw(ctx, &bankpb.WithdrawRequest{AccountNumber: "ACC001", Amount: inr("10.01")})
+			return err
+		}, codes.FailedPrecondition},
+		{"close with balance", func() error {
+			_, err := c.accounts.CloseAccount(ctx, &bankpb.CloseAccountRequest{AccountNumber: "ACC001"})
+			return err
+		}, codes.FailedPrecondition},
+		{"too many decimals", func() error {
+			_, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC001", Amount: inr("1.001")})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown currency", func() error {
+			_, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC001", Amount: &bankpb.Money{Currency: "XYZ", Amount: "1.00"}})
+			return err
+		}, codes.InvalidArgument},
+		{"currency mismatch", func() error {
+			_, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC001", Amount: &bankpb.Money{Currency: "USD", Amount: "1.00"}})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown account status", func() error {
+			_, err := c.accounts.ChangeAccountStatus(ctx, &bankpb.ChangeAccountStatusRequest{AccountNumber: "ACC002", Status: "Sleeping", Reason: "x"})
+			return err
+		}, codes.InvalidArgument},
+		{"status change not allowed", func() error {
+			_, err := c.accounts.ChangeAccountStatus(ctx, &bankpb.ChangeAccountStatusRequest{AccountNumber: "ACC002", Status: "PendingKYC", Reason: "x"})
+			return err
+		}, codes.FailedPrecondition},
+		{"same account transfer", func() error {
+			_, err := c.transactions.Transfer(ctx, &bankpb.TransferRequest{FromAccount: "ACC001", ToAccount: "ACC001", Amount: inr("1.00")})
+			return err
+		}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("status code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Failed calls must not have moved any money.
+	balance, err := c.accounts.GetBalance(ctx, &bankpb.GetBalanceRequest{AccountNumber: "ACC001"})
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if balance.GetBalance().GetAmount() != "10.00" {
+		t.Errorf("balance = %s after failed calls, want 10.00", balance.GetBalance().GetAmount())
+	}
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	c := newTestClients(t)
+	ctx := c.admin
+
+	_, err := c.users.CreateUser(ctx, &bankpb.CreateUserRequest{
+		FirstName:        "Test",
+		LastName:         "User",
+		Email:            "Test User <test.user@example.com>",
+		Password:         "S3cure!Passw0rd",
+		Phone:            "5876543210",
+		PanCardNumber:    "DFFGD7657JKHG",
+		AadharCardNumber: "234123412345",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("status code = %v, want InvalidArgument", status.Code(err))
+	}
+
+	var got []string
+	for _, detail := range status.Convert(err).Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				got = append(got, v.GetField())
+			}
+		}
+	}
+	want := []string{"email", "phone", "pan_card_number", "aadhar_card_number"}
+	if !slices.Equal(got, want) {
+		t.Errorf("field violations = %v, want %v", got, want)
+	}
+
+	// Spacing, case and a country code are normalized away.
+	user, err := c.users.CreateUser(ctx, &bankpb.CreateUserRequest{
+		FirstName:        "Test",
+		LastName:         "User",
+		Email:            "test.user@example.com",
+		Password:         "S3cure!Passw0rd",
+		Phone:            "+91 98765-43210",
+		PanCardNumber:    "abcpk1234f",
+		AadharCardNumber: "2341 2341 2346",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.GetPhone() != "9876543210" || user.GetPanCardNumber() != "ABCPK1234F" || user.GetAadharCardNumber() != "234123412346" {
+		t.Errorf("user = %v, want normalized phone, PAN and Aadhaar", user)
+	}
+}
+
+func TestStreamTransactionHistory(t *testing.T) {
+	c := newTestClients(t)
+	ctx := c.admin
+	createCustomer(t, c, "test.user@example.com", "ACC001", "ACC002")
+
+	const deposits = 25
+	for range deposits {
+		if _, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC001", Amount: inr("1.00")}); err != nil {
+			t.Fatalf("Deposit: %v", err)
+		}
+	}
+	if _, err := c.accounts.Deposit(ctx, &bankpb.DepositRequest{AccountNumber: "ACC002", Amount: inr("1.00")}); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+
+	history := func(accountNumber string) ([]*bankpb.Transaction, error) {
+		stream, err := c.transactions.StreamTransactionHistory(ctx, &bankpb.TransactionHistoryRequest{AccountNumber: accountNumber})
+		if err != nil {
+			return nil, err
+		}
+
+		var received []*bankpb.Transaction
+		for {
+			transaction, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return received, nil
+			}
+			if err != nil {
+				return received, err
+			}
+			received = append(received, transaction)
+		}
+	}
+
+	received, err := history("ACC001")
+	if err != nil {
+		t.Fatalf("streaming ACC001: %v", err)
+	}
+	if len(received) != deposits {
+		t.Fatalf("received %d transactions, want %d", len(received), deposits)
+	}
+	for i, transaction := range received {
+		if transaction.GetToAccount() != "ACC001" {
+			t.Errorf("transaction %s is for %s, want ACC001", transaction.GetId(), transaction.GetToAccount())
+		}
+		if want := int64(i+1) * 100; transaction.GetBalanceAfter().GetMinorUnits() != want {
+			t.Errorf("transaction %d left %d, want %d: not oldest first", i, transaction.GetBalanceAfter().GetMinorUnits(), want)
+		}
+	}
+
+	all, err := history("")
+	if err != nil {
+		t.Fatalf("streaming all: %v", err)
+	}
+	if len(all) != deposits+1 {
+		t.Errorf("received %d transactions for the whole bank, want %d", len(all), deposits+1)
+	}
+
+	if _, err := history("NOPE"); status.Code(err) != codes.NotFound {
+		t.Errorf("streaming an unknown account: got %v, want NotFound", err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	c := newTestClients(t)
+	createCustomer(t, c, "test.user@example.com", "ACC001")
+
+	// Signing up and logging in need no session.
+	session, err := c.sessions.Login(context.Background(), &bankpb.LoginRequest{Email: "test.user@example.com", Password: "S3cure!Passw0rd"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if session.GetTokenType() != "Bearer" || session.GetToken() == "" {
+		t.Errorf("session = %v, want a bearer token", session)
+	}
+	if _, err := c.sessions.Login(context.Background(), &bankpb.LoginRequest{Email: "test.user@example.com", Password: "wrong"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Login with the wrong password = %v, want Unauthenticated", err)
+	}
+	ctx := withToken(session.GetToken())
+	if _, err := c.accounts.GetAccount(ctx, &bankpb.GetAccountRequest{AccountNumber: "ACC001"}); err != nil {
+		t.Fatalf("GetAccount with a session: %v", err)
+	}
+
+	for name, ctx := range map[string]context.Context{
+		"no token":      context.Background(),
+		"unknown token": withToken("never issued"),
+		"not bearer":    metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+session.GetToken()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.accounts.GetAccount(ctx, &bankpb.GetAccountRequest{AccountNumber: "ACC001"}); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("GetAccount = %v, want Unauthenticated", err)
+			}
+			stream, err := c.transactions.StreamTransactionHistory(ctx, &bankpb.TransactionHistoryRequest{AccountNumber: "ACC001"})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("StreamTransactionHistory = %v, want Unauthenticated", err)
+			}
+		})
+	}
+
+	if _, err := c.sessions.Logout(ctx, &bankpb.LogoutRequest{}); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := c.accounts.GetAccount(ctx, &bankpb.GetAccountRequest{AccountNumber: "ACC001"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetAccount after Logout = %v, want Unauthenticated", err)
+	}
+}
+
+func TestCustomerCannotReachAnotherCustomersAccounts(t *testing.T) {
+	c := newTestClients(t)
+	createCustomer(t, c, "alice@example.com", "ALI001")
+	bob := createCustomer(t, c, "bob@example.com", "BOB001")
+	if _, err := c.accounts.Deposit(c.admin, &bankpb.DepositRequest{AccountNumber: "BOB001", Amount: inr("100.00")}); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	alice := c.login(t, "alice@example.com")
+
+	if _, err := c.accounts.GetAccount(alice, &bankpb.GetAccountRequest{AccountNumber: "ALI001"}); err != nil {
+		t.Fatalf("GetAccount of her own account: %v", err)
+	}
+
+	for name, call := range map[string]func() error{
+		"GetAccount": func() error {
+			_, err := c.accounts.GetAccount(alice, &bankpb.GetAccountRequest{AccountNumber: "BOB001"})
+			return err
+		},
+		"GetBalance": func() error {
+			_, err := c.accounts.GetBalance(alice, &bankpb.GetBalanceRequest{AccountNumber: "BOB001"})
+			return err
+		},
+		"GetUser": func() error {
+			_, err := c.users.GetUser(alice, &bankpb.GetUserRequest{Id: bob.GetId()})
+			return err
+		},
+		"Withdraw": func() error {
+			_, err := c.accounts.Withdraw(alice, &bankpb.WithdrawRequest{AccountNumber: "BOB001", Amount: inr("1.00")})
+			return err
+		},
+		"Transfer": func() error {
+			_, err := c.transactions.Transfer(alice, &bankpb.TransferRequest{FromAccount: "BOB001", ToAccount: "ALI001", Amount: inr("1.00")})
+			return err
+		},
+		"StreamTransactionHistory": func() error {
+			stream, err := c.transactions.StreamTransactionHistory(alice, &bankpb.TransactionHistoryRequest{AccountNumber: "BOB001"})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+	} {
+		if err := call(); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s on another customer's account = %v, want PermissionDenied", name, err)
+		}
+	}
+
+	// Listings leave the other customer out rather than failing.
+	accounts, err := c.accounts.ListAccounts(alice, &bankpb.ListAccountsRequest{})
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(accounts.GetAccounts()) != 1 || accounts.GetAccounts()[0].GetAccountNumber() != "ALI001" {
+		t.Errorf("ListAccounts = %v, want only ALI001", accounts.GetAccounts())
+	}
+}
@@ -1,16 +1,168 @@
 package main
 
 import (
+	"bank-system/api"
 	"bank-system/bank"
+	"bank-system/bank/sqlitestore"
+	"bank-system/grpcserver"
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
 )
 
 func main() {
-	bankingSystem := bank.NewBankingSystem()
+	dataDir := flag.String("data", "", "directory for persistent storage (in-memory if empty)")
+	sqlitePath := flag.String("sqlite", "", "SQLite database file for persistent storage")
+	httpAddr := flag.String("http", "", "serve the JSON API on this address (e.g. :8080) instead of the menu")
+	grpcAddr := flag.String("grpc", "", "serve the gRPC API on this address (e.g. :9090) instead of the menu")
+	adminEmail := flag.String("admin", "", "give the user with this email the admin role at startup")
+	keyFile := flag.String("keyfile", "", "encrypt customers' personal details with keys from this file (created if missing)")
+	verifyAudit := flag.Bool("verify-audit", false, "check the audit trail for edited or removed entries and exit")
+	rotateKey := flag.Bool("rotate-key", false, "add a new key to -keyfile and re-encrypt every customer with it at startup")
+	chargeFees := flag.String("charge-fees", "", "charge monthly fees for every month that ended by the end of this date (YYYY-MM-DD) and exit")
+	accrueInterest := flag.String("accrue-interest", "", "accrue interest through the end of this date (YYYY-MM-DD), credit it for periods that have ended, and exit")
+	scheduleEvery := flag.Duration("schedule-every", time.Minute, "how often to run standing instructions that are due and purge expired idempotency keys (0 turns the scheduler off)")
+	nodeID := flag.Int("node-id", -1, "ID of this node, from 0 to 1023, unique among the nodes sharing storage; part of every transaction ID (required with -sqlite)")
+	requireKYC := flag.Bool("kyc", false, "open accounts pending KYC, taking deposits only until a branch manager activates them")
+	idempotencyRetention := flag.Duration("idempotency-retention", bank.DefaultIdempotencyRetention, "how long idempotency keys on deposits, withdrawals and transfers are remembered")
+	flag.Parse()
+
+	if *nodeID < 0 {
+		// Nodes left to default to the same ID would hand out the same
+		// transaction IDs.
+		if *sqlitePath != "" {
+			fmt.Println("-sqlite needs -node-id, unique among the nodes sharing the database")
+			os.Exit(2)
+		}
+		*nodeID = 0
+	}
+	ids, err := bank.NewSnowflakeGenerator(*nodeID)
+	if err != nil {
+		fmt.Printf("Invalid -node-id: %v\n", err)
+		os.Exit(2)
+	}
+	opts := []bank.Option{bank.WithIDGenerator(ids)}
+	switch {
+	case *dataDir != "" && *sqlitePath != "":
+		fmt.Println("Use either -data or -sqlite, not both")
+		os.Exit(2)
+	case *dataDir != "":
+		store, err := bank.OpenFileStore(*dataDir, 0, bank.WithWarningHandler(func(err error) {
+			fmt.Printf("Warning: %v\n", err)
+		}))
+		if err != nil {
+			fmt.Printf("Failed to open data directory: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, bank.WithStore(store))
+	case *sqlitePath != "":
+		store, err := sqlitestore.Open(*sqlitePath)
+		if err != nil {
+			fmt.Printf("Failed to open database: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, bank.WithStore(store))
+	}
+
+	if *keyFile != "" {
+		keys, err := bank.OpenKeyFile(*keyFile)
+		if err != nil {
+			fmt.Printf("Failed to open key file: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, bank.WithKeyProvider(keys))
+	} else if *rotateKey {
+		fmt.Println("-rotate-key needs -keyfile")
+		os.Exit(2)
+	}
+
+	if *requireKYC {
+		opts = append(opts, bank.WithInitialAccountStatus(bank.StatusPendingKYC))
+	}
+	opts = append(opts, bank.WithIdempotencyRetention(*idempotencyRetention))
+	opts = append(opts, bank.WithNotifier(bank.NotifierFunc(func(n bank.Notification) {
+		fmt.Printf("NOTICE [%s] %s\n", n.Kind, n.Message)
+	})))
+
+	bankingSystem := bank.NewBankingSystem(opts...)
+	defer func() {
+		if err := bankingSystem.Close(); err != nil {
+			fmt.Printf("Failed to close storage: %v\n", err)
+		}
+	}()
+
+	if *verifyAudit {
+		head, err := bankingSystem.VerifyAuditLog()
+		if err != nil {
+			fmt.Printf("Audit trail verification failed: %v\n", err)
+			os.Exit(1)
+		}
+		if head == nil {
+			fmt.Println("Audit trail is empty.")
+		} else {
+			fmt.Printf("Audit trail intact: %d entries, last hash %s\n", head.Sequence, head.Hash)
+		}
+		return
+	}
+
+	if *accrueInterest != "" || *chargeFees != "" {
+		if *accrueInterest != "" {
+			if err := runInterest(bankingSystem, *accrueInterest); err != nil {
+				fmt.Printf("Interest run failed: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *chargeFees != "" {
+			if err := runFees(bankingSystem, *chargeFees); err != nil {
+				fmt.Printf("Fee run failed: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	if *rotateKey {
+		n, err := bankingSystem.RotateEncryptionKey()
+		if err != nil {
+			fmt.Printf("Failed to rotate encryption key: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Re-encrypted %d users with the new key.\n", n)
+	}
+
+	if *adminEmail != "" {
+		if err := grantAdmin(bankingSystem, *adminEmail); err != nil {
+			fmt.Printf("Failed to grant admin role: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *scheduleEvery > 0 {
+		stop := startScheduler(bankingSystem, *scheduleEvery)
+		defer stop()
+	}
+
+	if *httpAddr != "" || *grpcAddr != "" {
+		if err := serve(bankingSystem, *httpAddr, *grpcAddr); err != nil {
+			fmt.Printf("Server failed: %v\n", err)
+		}
+		return
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 
 	fmt.Println("=== Integrated Banking System ===")
@@ -18,6 +170,10 @@ func main() {
 
 	// createSampleData(bankingSystem)
 
+	// session acts for the logged-in customer; nil until someone logs in.
+	var session *bank.BankingSystem
+	var token string
+
 	for {
 		displayMenu()
 		fmt.Print("Enter your choice: ")
@@ -30,41 +186,239 @@ func main() {
 		switch choice {
 		case "1":
 			createUserHandler(bankingSystem, scanner)
+			continue
+		case "16":
+			if s, t, ok := loginHandler(bankingSystem, scanner); ok {
+				if token != "" {
+					bankingSystem.Logout(token)
+				}
+				session, token = s, t
+			}
+			continue
+		case "28":
+			fmt.Println("Exiting the Banking System. Goodbye!")
+			return
+		}
+
+		if session == nil {
+			if n, err := strconv.Atoi(choice); err == nil && n >= 2 && n <= 27 {
+				fmt.Println("Please log in first.")
+			} else {
+				fmt.Println("Invalid choice. Please try again.")
+			}
+			continue
+		}
+
+		switch choice {
 		case "2":
-			createAccountHandler(bankingSystem, scanner)
+			createAccountHandler(session, scanner)
 		case "3":
-			depositHandler(bankingSystem, scanner)
+			depositHandler(session, scanner)
 		case "4":
-			withdrawHandler(bankingSystem, scanner)
+			withdrawHandler(session, scanner)
 		case "5":
-			transferHandler(bankingSystem, scanner)
+			transferHandler(session, scanner)
 		case "6":
-			viewAccountHandler(bankingSystem, scanner)
+			viewAccountHandler(session, scanner)
 		case "7":
-			viewUserHandler(bankingSystem, scanner)
+			viewUserHandler(session, scanner)
 		case "8":
-			bankingSystem.ListAllAccounts()
+			session.ListAllAccounts()
 		case "9":
-			bankingSystem.ListAllUsers()
+			session.ListAllUsers()
 		case "10":
-			bankingSystem.GetTransactionHistory()
+			session.GetTransactionHistory()
 		case "11":
-			viewAccountTransactionsHandler(bankingSystem, scanner)
+			viewAccountTransactionsHandler(session, scanner)
 		case "12":
-			viewTransactionSummaryHandler(bankingSystem, scanner)
+			viewTransactionSummaryHandler(session, scanner)
 		case "13":
-			viewBalanceHandler(bankingSystem, scanner)
+			viewBalanceHandler(session, scanner)
 		case "14":
-			closeAccountHandler(bankingSystem, scanner)
+			closeAccountHandler(session, scanner)
 		case "15":
-			fmt.Println("Exiting the Banking System. Goodbye!")
-			return
+			viewTrialBalanceHandler(session)
+		case "17":
+			bankingSystem.Logout(token)
+			session, token = nil, ""
+			fmt.Println("Logged out.")
+		case "18":
+			assignRoleHandler(session, scanner)
+		case "19":
+			freezeAccountHandler(session, scanner)
+		case "20":
+			unfreezeAccountHandler(session, scanner)
+		case "21":
+			reverseTransactionHandler(session, scanner)
+		case "22":
+			createStandingInstructionHandler(session, scanner)
+		case "23":
+			listStandingInstructionsHandler(session)
+		case "24":
+			manageStandingInstructionHandler(session, scanner)
+		case "25":
+			searchTransactionsHandler(session, scanner)
+		case "26":
+			statementHandler(session, scanner)
+		case "27":
+			changeAccountStatusHandler(session, scanner)
 		default:
 			fmt.Println("Invalid choice. Please try again.")
 		}
 	}
 }
 
+// endOfDate returns the midnight that ends date, a YYYY-MM-DD date in local
+// time.
+func endOfDate(date string) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// runInterest accrues interest for every day up to and including date.
+func runInterest(bs *bank.BankingSystem, date string) error {
+	asOf, err := endOfDate(date)
+	if err != nil {
+		return err
+	}
+
+	run, err := bs.AccrueInterest(asOf)
+	if run != nil {
+		for _, transaction := range run.Credits {
+			fmt.Printf("Credited %s to %s (%s)\n", transaction.Amount, transaction.ToAccount, transaction.Description)
+		}
+		for accountNumber, accrued := range run.Accrued {
+			fmt.Printf("Accrued %s on %s, not yet due\n", accrued, accountNumber)
+		}
+		fmt.Printf("Interest run through %s posted %d credits.\n", date, len(run.Credits))
+	}
+	return err
+}
+
+// runFees charges monthly fees for every month that ended by the end of
+// date.
+func runFees(bs *bank.BankingSystem, date string) error {
+	asOf, err := endOfDate(date)
+	if err != nil {
+		return err
+	}
+
+	charged, err := bs.ChargeMonthlyFees(asOf)
+	for _, fee := range charged {
+		fmt.Printf("Charged %s to %s (%s)\n", fee.Amount, fee.FromAccount, fee.Description)
+	}
+	fmt.Printf("Fee run through %s charged %d fees.\n", date, len(charged))
+	return err
+}
+
+// grantAdmin bootstraps the first administrator, who can then assign roles
+// to everyone else.
+func grantAdmin(bs *bank.BankingSystem, email string) error {
+	user, err := bs.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	return bs.AssignRole(user.ID, bank.RoleAdmin)
+}
+
+// startScheduler runs due standing instructions and purges expired
+// idempotency keys every interval in the background. The returned function
+// stops both and waits for work in progress to finish.
+func startScheduler(bs *bank.BankingSystem, interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		bank.NewScheduler(bs).Run(ctx, interval, func(err error) {
+			fmt.Printf("Standing instructions: %v\n", err)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if _, err := bs.PurgeIdempotencyKeys(); err != nil {
+				fmt.Printf("Idempotency keys: %v\n", err)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// serve runs the JSON API, the gRPC API or both until the process is
+// interrupted, then lets in-flight requests finish.
+func serve(bs *bank.BankingSystem, httpAddr, grpcAddr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 2)
+
+	var grpcServer *grpc.Server
+	if grpcAddr != "" {
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			return err
+		}
+
+		grpcServer = grpcserver.NewServer(bs)
+		go func() {
+			fmt.Printf("Serving the gRPC API on %s\n", lis.Addr())
+			if err := grpcServer.Serve(lis); err != nil {
+				errc <- fmt.Errorf("gRPC server: %w", err)
+			}
+		}()
+	}
+
+	var httpServer *http.Server
+	if httpAddr != "" {
+		httpServer = &http.Server{
+			Addr:              httpAddr,
+			Handler:           api.NewServer(bs),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			fmt.Printf("Serving the HTTP API on %s\n", httpAddr)
+			if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				errc <- fmt.Errorf("HTTP server: %w", err)
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+	}
+
+	fmt.Println("Shutting down...")
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	if httpServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if shutdownErr := httpServer.Shutdown(shutdownCtx); err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
+
 func displayMenu() {
 	fmt.Println("\nPlease select an option:")
 	fmt.Println("1. Create User")
@@ -81,7 +435,20 @@ func displayMenu() {
 	fmt.Println("12. View Transaction Summary")
 	fmt.Println("13. View Account Balance")
 	fmt.Println("14. Close Account")
-	fmt.Println("15. Exit")
+	fmt.Println("15. View Trial Balance")
+	fmt.Println("16. Login")
+	fmt.Println("17. Logout")
+	fmt.Println("18. Assign Role")
+	fmt.Println("19. Freeze Account")
+	fmt.Println("20. Unfreeze Account")
+	fmt.Println("21. Reverse Transaction")
+	fmt.Println("22. Create Standing Instruction")
+	fmt.Println("23. List Standing Instructions")
+	fmt.Println("24. Manage Standing Instruction")
+	fmt.Println("25. Search Transactions")
+	fmt.Println("26. Account Statement")
+	fmt.Println("27. Change Account Status")
+	fmt.Println("28. Exit")
 }
 
 // func createSampleData(bs *bank.BankingSystem) {
@@ -90,11 +457,11 @@ func displayMenu() {
 // 		"Raushan",
 // 		"Kumar",
 // 		"raushan.kumar@hk.com",
-// 		"RK@tr",
+// 		"Rk@tr-2024!",
 // 		"Motihari, Bihar",
 // 		"7645927364",
-// 		"DFFGD7657JKHG",
-// 		"3245733353443626",
+// 		"DFFPK7657H",
+// 		"324573335346",
 // 	)
 // 	if err != nil {
 // 		fmt.Printf("Failed to create sample user: %v\n", err)
@@ -102,15 +469,15 @@ func displayMenu() {
 // 	}
 
 // 	// Create sample accounts for the user
-// 	err = bs.CreateAccount("ACC001", "Raushan Kumar", "Savings", user.ID)
+// 	_, err = bs.CreateAccount("ACC001", "Raushan Kumar", "Savings", user.ID)
 // 	if err != nil {
 // 		fmt.Printf("Failed to create sample account: %v\n", err)
 // 	}
 
 // 	// Create some transactions
-// 	bs.Deposit("ACC001", 1000.0)
-// 	bs.Deposit("ACC001", 500.0)
-// 	bs.Withdraw("ACC001", 200.0)
+// 	bs.Deposit("ACC001", bank.MustParseMoney("1000.00", bank.INR))
+// 	bs.Deposit("ACC001", bank.MustParseMoney("500.00", bank.INR))
+// 	bs.Withdraw("ACC001", bank.MustParseMoney("200.00", bank.INR))
 // }
 
 func createUserHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -148,10 +515,40 @@ func createUserHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	scanner.Scan()
 	aadharCard := strings.TrimSpace(scanner.Text())
 
-	_, err := bs.CreateUser(firstName, lastName, email, password, address, phone, panCard, aadharCard)
+	user, err := bs.CreateUser(firstName, lastName, email, password, address, phone, panCard, aadharCard)
 	if err != nil {
 		fmt.Printf("Error creating user: %v\n", err)
+		return
+	}
+
+	fmt.Printf("User created successfully: %s %s (ID: %d)\n", user.FirstName, user.LastName, user.ID)
+}
+
+func loginHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) (*bank.BankingSystem, string, bool) {
+	fmt.Println("\n=== Login ===")
+
+	fmt.Print("Enter email: ")
+	scanner.Scan()
+	email := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter password: ")
+	scanner.Scan()
+	password := strings.TrimSpace(scanner.Text())
+
+	session, err := bs.Login(email, password)
+	if err != nil {
+		fmt.Printf("Login failed: %v\n", err)
+		return nil, "", false
+	}
+
+	scoped, err := bs.Authenticate(session.Token)
+	if err != nil {
+		fmt.Printf("Login failed: %v\n", err)
+		return nil, "", false
 	}
+
+	fmt.Printf("Logged in as %s.\n", session.Principal.Email)
+	return scoped, session.Token, true
 }
 
 func createAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -179,10 +576,17 @@ func createAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	scanner.Scan()
 	accountType := strings.TrimSpace(scanner.Text())
 
-	err = bs.CreateAccount(accountNumber, holderName, accountType, userID)
+	account, err := bs.CreateAccount(accountNumber, holderName, accountType, userID)
 	if err != nil {
 		fmt.Printf("Error creating account: %v\n", err)
+		return
 	}
+
+	fmt.Printf("Account created successfully for %s (User ID: %d)\n", holderName, userID)
+	if user, err := bs.GetUser(userID); err == nil {
+		user.DisplayUserInfo()
+	}
+	account.DisplayAccountInfo()
 }
 
 func depositHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -194,16 +598,19 @@ func depositHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	scanner.Scan()
 	amountStr := strings.TrimSpace(scanner.Text())
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := bank.ParseMoney(amountStr, bank.DefaultCurrency)
 	if err != nil {
-		fmt.Println("Invalid amount. Please enter a valid number.")
+		fmt.Printf("Invalid amount: %v\n", err)
 		return
 	}
 
-	err = bs.Deposit(accountNumber, amount)
+	_, err = bs.Deposit(accountNumber, amount)
 	if err != nil {
 		fmt.Printf("Error depositing money: %v\n", err)
+		return
 	}
+
+	fmt.Printf("Deposited %s to account %s\n", amount, accountNumber)
 }
 
 func withdrawHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -215,16 +622,19 @@ func withdrawHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	scanner.Scan()
 	amountStr := strings.TrimSpace(scanner.Text())
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := bank.ParseMoney(amountStr, bank.DefaultCurrency)
 	if err != nil {
-		fmt.Println("Invalid amount. Please enter a valid number.")
+		fmt.Printf("Invalid amount: %v\n", err)
 		return
 	}
 
-	err = bs.Withdraw(accountNumber, amount)
+	_, err = bs.Withdraw(accountNumber, amount)
 	if err != nil {
 		fmt.Printf("Error withdrawing money: %v\n", err)
+		return
 	}
+
+	fmt.Printf("Withdrew %s from account %s\n", amount, accountNumber)
 }
 
 func transferHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -240,16 +650,19 @@ func transferHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	scanner.Scan()
 	amountStr := strings.TrimSpace(scanner.Text())
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := bank.ParseMoney(amountStr, bank.DefaultCurrency)
 	if err != nil {
-		fmt.Println("Invalid amount. Please enter a valid number.")
+		fmt.Printf("Invalid amount: %v\n", err)
 		return
 	}
 
-	err = bs.Transfer(fromAccount, toAccount, amount)
+	_, err = bs.Transfer(fromAccount, toAccount, amount)
 	if err != nil {
 		fmt.Printf("Error transferring money: %v\n", err)
+		return
 	}
+
+	fmt.Printf("Transferred %s from %s to %s\n", amount, fromAccount, toAccount)
 }
 
 func viewAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -295,6 +708,117 @@ func viewAccountTransactionsHandler(bs *bank.BankingSystem, scanner *bufio.Scann
 	bs.GetAccountTransactions(accountNumber)
 }
 
+// searchTransactionsHandler asks for a filter and shows the matching
+// transactions a page at a time, newest first.
+func searchTransactionsHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	filter := bank.TransactionFilter{NewestFirst: true, Limit: 10}
+
+	fmt.Print("Enter account number (blank for all): ")
+	scanner.Scan()
+	filter.AccountNumber = strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter type, such as DEPOSIT or TRANSFER (blank for all): ")
+	scanner.Scan()
+	if tType := strings.ToUpper(strings.TrimSpace(scanner.Text())); tType != "" {
+		filter.Types = []bank.TransactionType{bank.TransactionType(tType)}
+	}
+
+	fmt.Print("Enter text to find in the description or reference (blank for any): ")
+	scanner.Scan()
+	filter.Text = strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter first date (YYYY-MM-DD, blank for any): ")
+	scanner.Scan()
+	if date := strings.TrimSpace(scanner.Text()); date != "" {
+		end, err := endOfDate(date)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		filter.Since = end.AddDate(0, 0, -1)
+	}
+
+	fmt.Print("Enter last date (YYYY-MM-DD, blank for any): ")
+	scanner.Scan()
+	if date := strings.TrimSpace(scanner.Text()); date != "" {
+		var err error
+		if filter.Until, err = endOfDate(date); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	}
+
+	for {
+		page, err := bs.QueryTransactions(filter)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		bank.DisplayTransactions(page.Transactions, "Matching Transactions")
+		if page.NextCursor == "" {
+			return
+		}
+
+		fmt.Print("Show more? (y/n): ")
+		scanner.Scan()
+		if !strings.EqualFold(strings.TrimSpace(scanner.Text()), "y") {
+			return
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// statementHandler shows an account's statement for a past month, or saves
+// it to a file as HTML or PDF.
+func statementHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	fmt.Print("Enter account number: ")
+	scanner.Scan()
+	accountNumber := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter month (YYYY-MM): ")
+	scanner.Scan()
+	month, err := time.Parse("2006-01", strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("Error: the month must be given as YYYY-MM")
+		return
+	}
+
+	statement, err := bs.MonthlyStatement(accountNumber, month.Year(), month.Month())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Print("Enter format (text, html or pdf; blank for text): ")
+	scanner.Scan()
+	format := bank.StatementFormat(strings.ToLower(strings.TrimSpace(scanner.Text())))
+	if format == "" || format == bank.StatementText {
+		if err := statement.RenderText(os.Stdout); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+
+	defaultPath := fmt.Sprintf("statement-%s-%s.%s", accountNumber, month.Format("2006-01"), format)
+	fmt.Printf("Enter file to save to (blank for %s): ", defaultPath)
+	scanner.Scan()
+	path := strings.TrimSpace(scanner.Text())
+	if path == "" {
+		path = defaultPath
+	}
+
+	var document bytes.Buffer
+	if err := statement.Render(&document, format); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(path, document.Bytes(), 0o600); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Statement saved to %s.\n", path)
+}
+
 func viewTransactionSummaryHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	fmt.Print("Enter account number: ")
 	scanner.Scan()
@@ -314,7 +838,13 @@ func viewBalanceHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 		return
 	}
 
-	fmt.Printf("Account %s balance: $%.2f\n", accountNumber, balance)
+	available, err := bs.GetAvailableBalance(accountNumber)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Account %s balance: %s (available: %s)\n", accountNumber, balance, available)
 }
 
 func closeAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
@@ -328,4 +858,253 @@ func closeAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
 	} else {
 		fmt.Printf("Account %s closed successfully\n", accountNumber)
 	}
-}
\ No newline at end of file
+}
+
+func freezeAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	accountNumber, reason := readStatusChange(scanner, "freeze")
+	if err := bs.FreezeAccount(accountNumber, reason); err != nil {
+		fmt.Printf("Error freezing account: %v\n", err)
+		return
+	}
+	fmt.Printf("Account %s frozen\n", accountNumber)
+}
+
+func unfreezeAccountHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	accountNumber, reason := readStatusChange(scanner, "unfreeze")
+	if err := bs.UnfreezeAccount(accountNumber, reason); err != nil {
+		fmt.Printf("Error unfreezing account: %v\n", err)
+		return
+	}
+	fmt.Printf("Account %s is active again\n", accountNumber)
+}
+
+// changeAccountStatusHandler moves an account to any status the lifecycle
+// allows, such as from PendingKYC to Active once KYC is done.
+func changeAccountStatusHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	fmt.Print("Enter account number: ")
+	scanner.Scan()
+	accountNumber := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter new status (PendingKYC/Active/Frozen/DebitFrozen/Dormant/Closed): ")
+	scanner.Scan()
+	status, err := bank.ParseAccountStatus(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Print("Enter reason: ")
+	scanner.Scan()
+	reason := strings.TrimSpace(scanner.Text())
+
+	if err := bs.ChangeAccountStatus(accountNumber, status, reason); err != nil {
+		fmt.Printf("Error changing account status: %v\n", err)
+		return
+	}
+	fmt.Printf("Account %s is now %s\n", accountNumber, status)
+}
+
+// readStatusChange asks for the account to change and the reason for it.
+func readStatusChange(scanner *bufio.Scanner, verb string) (accountNumber, reason string) {
+	fmt.Printf("Enter account number to %s: ", verb)
+	scanner.Scan()
+	accountNumber = strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter reason: ")
+	scanner.Scan()
+	reason = strings.TrimSpace(scanner.Text())
+	return accountNumber, reason
+}
+
+func reverseTransactionHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	fmt.Print("Enter transaction ID to reverse: ")
+	scanner.Scan()
+	transactionID := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter reason: ")
+	scanner.Scan()
+	reason := strings.TrimSpace(scanner.Text())
+
+	reversal, err := bs.ReverseTransaction(transactionID, reason)
+	if err != nil {
+		fmt.Printf("Error reversing transaction: %v\n", err)
+		return
+	}
+	fmt.Printf("Transaction %s reversed by %s\n", transactionID, reversal.ID)
+}
+
+func createStandingInstructionHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	fmt.Print("Enter source account number: ")
+	scanner.Scan()
+	fromAccount := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter destination account number: ")
+	scanner.Scan()
+	toAccount := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter amount: ")
+	scanner.Scan()
+	amount, err := bank.ParseMoney(strings.TrimSpace(scanner.Text()), bank.DefaultCurrency)
+	if err != nil {
+		fmt.Printf("Invalid amount: %v\n", err)
+		return
+	}
+
+	fmt.Print("Enter description: ")
+	scanner.Scan()
+	description := strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter frequency (daily, weekly or monthly): ")
+	scanner.Scan()
+	frequency, err := bank.ParseFrequency(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	recurrence := bank.Recurrence{Frequency: frequency}
+
+	if frequency == bank.Monthly {
+		fmt.Print("Enter day of month (blank for today's): ")
+		scanner.Scan()
+		if day := strings.TrimSpace(scanner.Text()); day != "" {
+			if recurrence.DayOfMonth, err = strconv.Atoi(day); err != nil {
+				fmt.Println("Invalid day of month. Please enter a number.")
+				return
+			}
+		}
+	}
+
+	fmt.Print("Enter last date (YYYY-MM-DD, blank for none): ")
+	scanner.Scan()
+	if date := strings.TrimSpace(scanner.Text()); date != "" {
+		if recurrence.End, err = endOfDate(date); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	}
+
+	instruction, err := bs.CreateStandingInstruction(fromAccount, toAccount, amount, description, recurrence)
+	if err != nil {
+		fmt.Printf("Error creating standing instruction: %v\n", err)
+		return
+	}
+	fmt.Printf("Standing instruction %d created; first run %s\n", instruction.ID, instruction.NextRun.Format("2006-01-02 15:04"))
+}
+
+func listStandingInstructionsHandler(bs *bank.BankingSystem) {
+	instructions, err := bs.ListStandingInstructions()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n=== Standing Instructions ===")
+	if len(instructions) == 0 {
+		fmt.Println("No standing instructions found.")
+		return
+	}
+	for _, instruction := range instructions {
+		instruction.DisplayStandingInstruction()
+	}
+}
+
+// manageStandingInstructionHandler pauses, resumes or cancels an
+// instruction, or shows its run history.
+func manageStandingInstructionHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	fmt.Print("Enter standing instruction ID: ")
+	scanner.Scan()
+	id, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("Invalid ID. Please enter a valid number.")
+		return
+	}
+
+	fmt.Print("Enter action (pause, resume, cancel or history): ")
+	scanner.Scan()
+	var instruction *bank.StandingInstruction
+	switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+	case "pause":
+		instruction, err = bs.PauseStandingInstruction(id)
+	case "resume":
+		instruction, err = bs.ResumeStandingInstruction(id)
+	case "cancel":
+		instruction, err = bs.CancelStandingInstruction(id)
+	case "history":
+		attempts, err := bs.ListInstructionAttempts(id)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(attempts) == 0 {
+			fmt.Println("The instruction has not run yet.")
+		}
+		for _, a := range attempts {
+			detail := a.TransactionID
+			if a.Error != "" {
+				detail = a.Error
+			}
+			fmt.Printf("%d. %s, for the run due %s: %s (%s)\n", a.Sequence, a.AttemptedAt.Format("2006-01-02 15:04"),
+				a.ScheduledFor.Format("2006-01-02 15:04"), a.Outcome, detail)
+		}
+		return
+	default:
+		fmt.Println("Invalid action.")
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Standing instruction %d is now %s\n", instruction.ID, instruction.Status)
+}
+
+func viewTrialBalanceHandler(bs *bank.BankingSystem) {
+	tb, err := bs.GetTrialBalance()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	tb.DisplayTrialBalance()
+
+	discrepancies, err := bs.ReconcileLedger()
+	if err != nil {
+		fmt.Printf("Error reconciling ledger: %v\n", err)
+		return
+	}
+
+	if len(discrepancies) == 0 {
+		fmt.Println("All account balances agree with the ledger.")
+		return
+	}
+
+	for _, d := range discrepancies {
+		fmt.Printf("Account %s: balance %s, ledger %s\n", d.AccountNumber, d.Balance, d.LedgerBalance)
+	}
+}
+
+func assignRoleHandler(bs *bank.BankingSystem, scanner *bufio.Scanner) {
+	fmt.Println("\n=== Assign Role ===")
+
+	fmt.Print("Enter user ID: ")
+	scanner.Scan()
+	userID, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Println("Invalid user ID. Please enter a valid number.")
+		return
+	}
+
+	fmt.Printf("Enter role (%s): ", bank.Roles)
+	scanner.Scan()
+	role, err := bank.ParseRole(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	if err := bs.AssignRole(userID, role); err != nil {
+		fmt.Printf("Error assigning role: %v\n", err)
+		return
+	}
+
+	fmt.Printf("User %d is now %s.\n", userID, role)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"errors"
 	"fmt"
+
+	"bank-system/bank"
 )
 
 type User struct {
@@ -10,7 +12,8 @@ type User struct {
 	FirstName       string
 	LastName        string
 	Email           string
-	Password        string
+	Password        string // plaintext, read by Create only and never stored
+	PasswordHash    string
 	Address         string
 	Phone           string
 	PanCardNumber   string
@@ -23,6 +26,7 @@ type UserService interface {
 	Update(user User) error
 	Delete(id int) error
 	List() ([]User, error)
+	VerifyPassword(email, password string) (*User, error)
 }
 
 var (
@@ -30,17 +34,22 @@ var (
 	ErrEmailExists  = errors.New("email already exists")
 	ErrInvalidInput = errors.New("invalid input")
 	ErrInternal     = errors.New("internal server error")
+
+	ErrInvalidCredentials = bank.ErrInvalidCredentials
+	ErrWeakPassword       = bank.ErrWeakPassword
 )
 
 type userService struct {
-	users  map[int]User
-	nextID int
+	users     map[int]User
+	nextID    int
+	passwords *bank.PasswordHasher
 }
 
 func NewUserService() UserService {
 	return &userService{
-		users:  make(map[int]User),
-		nextID: 1,
+		users:     make(map[int]User),
+		nextID:    1,
+		passwords: bank.NewPasswordHasher(bank.DefaultPasswordParams),
 	}
 }
 
@@ -49,12 +58,23 @@ func (us *userService) Create(user User) (*User, error) {
 		return nil, ErrInvalidInput
 	}
 
+	if err := bank.DefaultPasswordPolicy.Check(user.Password); err != nil {
+		return nil, err
+	}
+
 	for _, u := range us.users {
 		if u.Email == user.Email {
 			return nil, ErrEmailExists
 		}
 	}
 
+	hash, err := us.passwords.Hash(user.Password)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	user.PasswordHash = hash
+
 	user.ID = us.nextID
 	us.users[us.nextID] = user
 	us.nextID++
@@ -72,11 +92,14 @@ func (us *userService) Get(id int) (*User, error) {
 }
 
 func (us *userService) Update(user User) error {
-	_, exists := us.users[user.ID]
+	existing, exists := us.users[user.ID]
 	if !exists {
 		return ErrUserNotFound
 	}
 
+	user.Password = ""
+	user.PasswordHash = existing.PasswordHash
+
 	us.users[user.ID] = user
 	return nil
 }
@@ -101,6 +124,32 @@ func (us *userService) List() ([]User, error) {
 	return userList, nil
 }
 
+func (us *userService) VerifyPassword(email, password string) (*User, error) {
+	for id, user := range us.users {
+		if user.Email != email {
+			continue
+		}
+
+		ok, needsRehash, err := us.passwords.Verify(user.PasswordHash, password)
+		if err != nil {
+			return nil, fmt.Errorf("%w: user %d: %v", ErrInternal, user.ID, err)
+		}
+		if !ok {
+			return nil, ErrInvalidCredentials
+		}
+
+		if needsRehash {
+			if hash, err := us.passwords.Hash(password); err == nil {
+				user.PasswordHash = hash
+				us.users[id] = user
+			}
+		}
+		return &user, nil
+	}
+
+	return nil, ErrInvalidCredentials
+}
+
 func (u User) String() string {
 	return fmt.Sprintf("User{ID: %d, Name: %s %s, Email: %s, Phone: %s, Address: %s}",
 		u.ID, u.FirstName, u.LastName, u.Email, u.Phone, u.Address)
@@ -113,11 +162,11 @@ func main() {
 		FirstName:       "Raushan",
 		LastName:        "Kumar",
 		Email:           "raushan.kumar@hk.com",
-		Password:        "RK@tr",
+		Password:        "Rk@tr-2024!",
 		Address:         "Motihari, Bihar",
 		Phone:           "7645927364",
-		PanCardNumber:   "DFFGD7657JKHG",
-		AddarCardNumber: "3245733353443626",
+		PanCardNumber:   "DFFPK7657H",
+		AddarCardNumber: "324573335346",
 	}
 
 	createdUsr, err := usrService.Create(usr)
@@ -127,4 +176,4 @@ func main() {
 	}
 
 	fmt.Printf("New created User :: %s\n", createdUsr)
-}
\ No newline at end of file
+}